@@ -7,6 +7,8 @@ export type Round = {
   special: boolean;
   drive_folder: string;
   discord_category: string;
+  version: number;
+  hunt: number;
 };
 
 export const RoundKeys: (keyof Omit<Round, "id">)[] = [
@@ -46,13 +48,57 @@ export type Puzzle = {
   meta: boolean;
   voice_room: string;
   reminder: string;
+  priority: number;
+  needs_help: boolean;
+  version: number;
+  tags: string[];
+  solvers: string[];
 };
 
 export const PuzzleKeys: (keyof Omit<Puzzle, "id">)[] = [
   "name", "answer", "round", "status", "note", "location", "puzzle_url",
   "spreadsheet_id", "discord_channel", "meta", "voice_room", "reminder",
+  "priority", "needs_help",
 ];
 
+export type PuzzleFeed = {
+  puzzle: number;
+  meta: number;
+};
+
+export type Guess = {
+  id: number;
+  puzzle: number;
+  guess: string;
+  result: "correct" | "incorrect" | "partial" | "intermediate";
+  note: string;
+  actor: string;
+  submitted_at: string;
+};
+
+export type SearchResult = {
+  id: number;
+  name: string;
+  answer: string;
+  discord_channel: string;
+  round_name: string;
+  round_emoji: string;
+  rank: number;
+};
+
+export type Trash = {
+  puzzles: (Puzzle & { deleted_at: string; })[];
+  rounds: (Round & { deleted_at: string; })[];
+};
+
+export type Hunt = {
+  id: number;
+  name: string;
+  active: boolean;
+  archived: boolean;
+  created_at: string;
+};
+
 export type NewPuzzle = {
   name: string;
   round: number;
@@ -126,29 +172,76 @@ export type SettingsMessage = {
   voice_rooms: Record<string, string>;
 };
 
-export type DiscoveryConfig = {
-  puzzles_url: string;
-  cookie_name: string;
-  cookie_value: string;
+export type ScraperConfig = {
+  scraper: "" | "html" | "json";
   group_mode: boolean;
   group_selector: string;
   round_name_selector: string;
   puzzle_list_selector: string;
   puzzle_item_selector: string;
+  rounds_path: string;
+  round_name_path: string;
+  puzzles_path: string;
+  puzzle_name_path: string;
+  puzzle_url_path: string;
+  graphql_query: string;
+};
+
+export type DiscoveryConfig = ScraperConfig & {
+  puzzles_url: string;
+  cookie_name: string;
+  cookie_value: string;
   websocket_url: string;
   websocket_token: string;
-  hunt_name: string;
+  sources: DiscoverySource[] | null;
+};
+
+export type DiscoverySource = Partial<ScraperConfig> & {
+  url: string;
+  name_prefix?: string;
+  round_name?: string;
+  cookie_name?: string;
+  cookie_value?: string;
+};
+
+export type HuntInfo = {
   hunt_url: string;
   hunt_credentials: string;
   logistics_url: string;
 };
 
+export type Setting = {
+  key: string;
+  description: string;
+  schema: object;
+  default: any;
+  value: any;
+};
+
 export type ScrapedPuzzle = {
   name: string;
   round_name: string;
   puzzle_url: string;
 };
 
+export type DiscoveryPreview = {
+  create: ScrapedPuzzle[] | null;
+  rounds: {
+    name: string;
+    pending: boolean; // already posted to #qm
+    ignored: boolean; // QMs chose not to create it
+    recreate: boolean; // approved, then deleted; will be re-posted to #qm
+    puzzles: ScrapedPuzzle[];
+  }[] | null;
+  duplicates: ScrapedPuzzle[] | null;
+};
+
+export type TestDiscoveryResponse = {
+  puzzles: ScrapedPuzzle[] | null;
+  errors: Record<string, string>; // by source URL
+  preview: DiscoveryPreview;
+};
+
 export type User = {
   username: string;
   avatarUrl: string;
@@ -195,6 +288,9 @@ export type SyncMessage = {
   kind: "upsert" | "delete";
   puzzle?: Puzzle;
   round?: Round;
+  feed?: PuzzleFeed;
+  guess?: Guess;
+  actor?: string; // Discord user ID, "discovery" or "sync"
 };
 export type UsersMessage = {
   users: Record<string, [string, string]>;
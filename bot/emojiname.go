@@ -3,13 +3,11 @@ package bot
 import (
 	"context"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/emojihunt/emojihunt/discord"
 	"github.com/emojihunt/emojihunt/emojiname"
-	"golang.org/x/xerrors"
 )
 
 type EmojiNameBot struct{}
@@ -33,13 +31,7 @@ func (b *EmojiNameBot) Handle(ctx context.Context, input *discord.CommandInput)
 	}
 
 	for _, e := range emoji {
-		for _, hex := range strings.Split(e.Unified, "-") {
-			n, err := strconv.ParseInt(hex, 16, 32)
-			if err != nil {
-				return "", xerrors.Errorf("ParseInt in %x: %w", e.Unified, err)
-			}
-			chars = append(chars, string(rune(n)))
-		}
+		chars = append(chars, e.String())
 		names = append(names, e.Name)
 	}
 	return fmt.Sprintf(
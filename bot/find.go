@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/emojihunt/emojihunt/discord"
+	"github.com/emojihunt/emojihunt/state"
+)
+
+const findMaxResults = 10
+
+type FindBot struct {
+	state *state.Client
+}
+
+func NewFindBot(state *state.Client) discord.Bot {
+	return &FindBot{state}
+}
+
+func (b *FindBot) Register() (*discordgo.ApplicationCommand, bool) {
+	return &discordgo.ApplicationCommand{
+		Name:        "find",
+		Description: "Search puzzles, notes, answers and chat 🔎",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "query",
+				Description: "What are you looking for?",
+				Required:    true,
+				Type:        discordgo.ApplicationCommandOptionString,
+			},
+		},
+	}, false
+}
+
+func (b *FindBot) Handle(ctx context.Context, input *discord.CommandInput) (string, error) {
+	var query = input.Options["query"].StringValue()
+	results, err := b.state.Search(ctx, query, findMaxResults)
+	if err != nil {
+		return "", err
+	} else if len(results) == 0 {
+		return fmt.Sprintf(":shrug: No puzzles match %q", query), nil
+	}
+
+	var reply strings.Builder
+	fmt.Fprintf(&reply, ":mag: Puzzles matching %q:\n", query)
+	for i, result := range results {
+		var link = result.Name
+		if result.DiscordChannel != "" {
+			link = fmt.Sprintf("<#%s>", result.DiscordChannel)
+		}
+		fmt.Fprintf(&reply, "%d. %s %s", i+1, result.RoundEmoji, link)
+		if result.Answer != "" {
+			fmt.Fprintf(&reply, " (`%s`)", result.Answer)
+		}
+		reply.WriteString("\n")
+	}
+	return reply.String(), nil
+}
+
+func (b *FindBot) HandleScheduledEvent(context.Context,
+	*discordgo.GuildScheduledEventUpdate) error {
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/emojihunt/emojihunt/discord"
+	"github.com/emojihunt/emojihunt/huntyet"
 	"github.com/emojihunt/emojihunt/state"
 	"github.com/emojihunt/emojihunt/state/status"
 	"golang.org/x/xerrors"
@@ -127,11 +128,191 @@ func (b *PuzzleBot) Register() (*discordgo.ApplicationCommand, bool) {
 					},
 				},
 			},
+			{
+				Name:        "priority",
+				Description: "Use in a puzzle channel to move it up or down its round 📶",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "to",
+						Description: "How urgent is this puzzle?",
+						Required:    true,
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: status.PriorityUrgent.Pretty(), Value: status.PriorityUrgent},
+							{Name: status.PriorityHigh.Pretty(), Value: status.PriorityHigh},
+							{Name: status.PriorityNormal.Pretty(), Value: status.PriorityNormal},
+							{Name: status.PriorityLow.Pretty(), Value: status.PriorityLow},
+						},
+					},
+				},
+			},
+			{
+				Name:        "help-wanted",
+				Description: "Use in a puzzle channel to ask for more solvers 🆘",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "needed",
+						Description: "Do we still need help? (default: yes)",
+						Required:    false,
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+					},
+				},
+			},
+			{
+				Name:        "join",
+				Description: "Use in a puzzle channel to sign up as one of its solvers 🙋",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "leave",
+				Description: "Use in a puzzle channel when you stop working on it 👋",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "history",
+				Description: "Use in a puzzle channel to see who changed what, and when 📜",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "tag",
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Description: "Label this puzzle, e.g. \"logic\" or \"needs-fresh-eyes\" 🏷️",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "add",
+						Description: "Add a tag to this puzzle ➕",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Name:        "tag",
+								Description: "What's the tag?",
+								Required:    true,
+								Type:        discordgo.ApplicationCommandOptionString,
+							},
+						},
+					},
+					{
+						Name:        "remove",
+						Description: "Remove a tag from this puzzle ➖",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Name:        "tag",
+								Description: "What's the tag?",
+								Required:    true,
+								Type:        discordgo.ApplicationCommandOptionString,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:        "guess",
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Description: "Keep track of the answers we've tried 🎯",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "record",
+						Description: "Use after submitting an answer to the hunt site 📝",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Name:        "answer",
+								Description: "What did we submit?",
+								Required:    true,
+								Type:        discordgo.ApplicationCommandOptionString,
+							},
+							{
+								Name:        "result",
+								Description: "What did the hunt site say?",
+								Required:    true,
+								Type:        discordgo.ApplicationCommandOptionString,
+								Choices: []*discordgo.ApplicationCommandOptionChoice{
+									{Name: status.GuessIncorrect.Pretty(), Value: status.GuessIncorrect},
+									{Name: status.GuessPartial.Pretty(), Value: status.GuessPartial},
+									{Name: status.GuessIntermediate.Pretty(), Value: status.GuessIntermediate},
+									{Name: status.GuessCorrect.Pretty(), Value: status.GuessCorrect},
+								},
+							},
+							{
+								Name:        "response",
+								Description: "Anything interesting in the response?",
+								Required:    false,
+								Type:        discordgo.ApplicationCommandOptionString,
+							},
+						},
+					},
+					{
+						Name:        "list",
+						Description: "See the answers we've already tried 📋",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+					},
+				},
+			},
+			{
+				Name:        "feeds",
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Description: "Track which metas this puzzle feeds into 🔗",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "list",
+						Description: "Show the metas this puzzle feeds into, and its feeders 🔍",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+					},
+					{
+						Name:        "add",
+						Description: "Record that this puzzle feeds into a meta ➕",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Name:        "into",
+								Description: "The meta's puzzle channel",
+								Required:    true,
+								Type:        discordgo.ApplicationCommandOptionChannel,
+								ChannelTypes: []discordgo.ChannelType{
+									discordgo.ChannelTypeGuildText,
+								},
+							},
+						},
+					},
+					{
+						Name:        "remove",
+						Description: "Record that this puzzle doesn't feed into a meta after all ➖",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Name:        "from",
+								Description: "The meta's puzzle channel",
+								Required:    true,
+								Type:        discordgo.ApplicationCommandOptionChannel,
+								ChannelTypes: []discordgo.ChannelType{
+									discordgo.ChannelTypeGuildText,
+								},
+							},
+						},
+					},
+				},
+			},
 		},
 	}, true
 }
 
 func (b *PuzzleBot) Handle(ctx context.Context, input *discord.CommandInput) (string, error) {
+	switch input.Subcommand {
+	case "history":
+		return b.handleHistory(ctx, input)
+	case "feeds.list", "feeds.add", "feeds.remove":
+		return b.handleFeeds(ctx, input)
+	case "guess.record", "guess.list":
+		return b.handleGuess(ctx, input)
+	case "tag.add", "tag.remove":
+		return b.handleTag(ctx, input)
+	case "join", "leave":
+		return b.handleSolvers(ctx, input)
+	}
+
 	var reply string
 	change, err := b.state.UpdatePuzzleByDiscordChannel(ctx, input.IC.ChannelID,
 		func(puzzle *state.RawPuzzle) error {
@@ -180,6 +361,7 @@ func (b *PuzzleBot) Handle(ctx context.Context, input *discord.CommandInput) (st
 					puzzle.Answer = strings.ToUpper(opt.StringValue())
 					puzzle.Location = ""
 					puzzle.VoiceRoom = ""
+					puzzle.NeedsHelp = false
 					reply = fmt.Sprintf(
 						"🎉 Congratulations on the %s I'll record the answer `%s` and archive this channel.",
 						puzzle.Status.SolvedNoun(), puzzle.Answer,
@@ -211,6 +393,33 @@ func (b *PuzzleBot) Handle(ctx context.Context, input *discord.CommandInput) (st
 					reply += fmt.Sprintf(" Previous location was: ```\n%s\n```", puzzle.Location)
 				}
 				puzzle.Location = location
+			case "priority":
+				if opt, ok := input.Options["to"]; !ok {
+					return xerrors.Errorf("missing option: to")
+				} else if priority := status.Priority(opt.IntValue()); !priority.IsValid() {
+					return xerrors.Errorf("invalid priority: %d", priority)
+				} else if puzzle.Priority == priority {
+					reply = fmt.Sprintf(":elephant: This puzzle already has priority %s", priority.Pretty())
+				} else {
+					reply = fmt.Sprintf(":bar_chart: Updated puzzle priority to %s!", priority.Pretty())
+					puzzle.Priority = priority
+				}
+			case "help-wanted":
+				var needed = true
+				if opt, ok := input.Options["needed"]; ok {
+					needed = opt.BoolValue()
+				}
+				if puzzle.Status.IsSolved() && needed {
+					reply = ":face_with_raised_eyebrow: This puzzle is already solved!"
+				} else if puzzle.NeedsHelp == needed {
+					reply = ":elephant: Nothing to change, this puzzle is already marked that way."
+				} else if needed {
+					reply = ":sos: Marked this puzzle as needing help. I'll let #progress know!"
+					puzzle.NeedsHelp = true
+				} else {
+					reply = ":handshake: Marked this puzzle as no longer needing help."
+					puzzle.NeedsHelp = false
+				}
 			default:
 				return xerrors.Errorf("unexpected /puzzle subcommand: %q", input.Subcommand)
 			}
@@ -222,10 +431,326 @@ func (b *PuzzleBot) Handle(ctx context.Context, input *discord.CommandInput) (st
 	} else if err != nil {
 		return "", err
 	}
+	if input.Subcommand == "solved" {
+		b.recordSolve(ctx, *change.After)
+	}
 	err = <-change.BotComplete
 	return reply, err
 }
 
+// recordSolve adds the answer to the puzzle's list of guesses, unless someone
+// already recorded it with `/puzzle guess`. Errors are logged rather than
+// returned, since the solve itself has already gone through.
+func (b *PuzzleBot) recordSolve(ctx context.Context, puzzle state.Puzzle) {
+	if puzzle.Status != status.Solved && puzzle.Status != status.Backsolved {
+		return // purchased answers weren't submitted
+	}
+	previous, err := b.state.FindGuess(ctx, puzzle.ID, puzzle.Answer)
+	if err != nil {
+		log.Printf("failed to record solve of %q as a guess: %v", puzzle.Name, err)
+		return
+	} else if previous != nil {
+		return
+	}
+	_, _, err = b.state.CreateGuess(ctx, state.Guess{
+		Puzzle: puzzle.ID,
+		Guess:  puzzle.Answer,
+		Result: status.GuessCorrect,
+	})
+	if err != nil {
+		log.Printf("failed to record solve of %q as a guess: %v", puzzle.Name, err)
+	}
+}
+
+func (b *PuzzleBot) handleGuess(ctx context.Context, input *discord.CommandInput) (string, error) {
+	puzzle, err := b.state.GetPuzzleByChannel(ctx, input.IC.ChannelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ":butterfly: I can't find a puzzle associated with this channel. Is this a puzzle channel?", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if input.Subcommand == "guess.list" {
+		guesses, err := b.state.ListGuesses(ctx, puzzle.ID)
+		if err != nil {
+			return "", err
+		} else if len(guesses) == 0 {
+			return ":dart: No guesses have been recorded for this puzzle yet.", nil
+		}
+		var msg = ":dart: Answers we've tried so far:\n"
+		for _, guess := range guesses {
+			msg += " • " + formatGuess(b.discord, guess) + "\n"
+		}
+		return msg, nil
+	}
+
+	opt, ok := input.Options["answer"]
+	if !ok {
+		return "", xerrors.Errorf("missing option: answer")
+	}
+	var answer = opt.StringValue()
+	opt, ok = input.Options["result"]
+	if !ok {
+		return "", xerrors.Errorf("missing option: result")
+	}
+	var result = status.GuessResult(opt.StringValue())
+	var note string
+	if opt, ok := input.Options["response"]; ok {
+		note = opt.StringValue()
+	}
+
+	previous, err := b.state.FindGuess(ctx, puzzle.ID, answer)
+	if err != nil {
+		return "", err
+	} else if previous != nil {
+		return ":no_entry_sign: We already tried that! " + formatGuess(b.discord, *previous), nil
+	}
+	guess, _, err := b.state.CreateGuess(ctx, state.Guess{
+		Puzzle: puzzle.ID,
+		Guess:  answer,
+		Result: result,
+		Note:   note,
+	})
+	var ve state.ValidationError
+	if errors.As(err, &ve) {
+		return fmt.Sprintf(":no_entry_sign: Couldn't record that guess: %s.", ve.Error()), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var reply = fmt.Sprintf("%s Recorded %s guess `%s`.", guess.Result.Emoji(), guess.Result, guess.Guess)
+	if guess.Result == status.GuessCorrect && !puzzle.Status.IsSolved() {
+		reply += " Don't forget to mark the puzzle solved with `/puzzle solved`!"
+	}
+	return reply, nil
+}
+
+func formatGuess(client *discord.Client, guess state.Guess) string {
+	var msg = fmt.Sprintf("%s `%s` by %s at %s ET",
+		guess.Result.Emoji(), guess.Guess,
+		client.DisplayActor(state.Actor(guess.Actor)),
+		guess.SubmittedAt.In(huntyet.BostonTime).Format("Mon 3:04 PM"),
+	)
+	if guess.Note != "" {
+		msg += fmt.Sprintf(" (%q)", guess.Note)
+	}
+	return msg
+}
+
+const historyLimit = 15
+
+func (b *PuzzleBot) handleHistory(ctx context.Context, input *discord.CommandInput) (string, error) {
+	puzzle, err := b.state.GetPuzzleByChannel(ctx, input.IC.ChannelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ":butterfly: I can't find a puzzle associated with this channel. Is this a puzzle channel?", nil
+	} else if err != nil {
+		return "", err
+	}
+	history, err := b.state.ListPuzzleHistory(ctx, puzzle.ID)
+	if err != nil {
+		return "", err
+	} else if len(history) == 0 {
+		return ":scroll: No changes have been recorded for this puzzle yet.", nil
+	}
+
+	var msg = ":scroll: Recent changes to this puzzle:\n"
+	if len(history) > historyLimit {
+		msg = fmt.Sprintf(":scroll: Last %d changes to this puzzle (see the web app for more):\n",
+			historyLimit)
+		history = history[len(history)-historyLimit:]
+	}
+	for _, entry := range history {
+		var change string
+		if entry.NewValue == "" {
+			change = fmt.Sprintf("cleared **%s** (was %s)",
+				entry.Field, formatHistoryValue(entry.Field, entry.OldValue))
+		} else {
+			change = fmt.Sprintf("set **%s** to %s",
+				entry.Field, formatHistoryValue(entry.Field, entry.NewValue))
+		}
+		msg += fmt.Sprintf(" • %s ET: %s %s\n",
+			entry.ChangedAt.In(huntyet.BostonTime).Format("Mon 3:04 PM"),
+			b.discord.DisplayActor(state.Actor(entry.Actor)), change,
+		)
+	}
+	return msg, nil
+}
+
+func formatHistoryValue(field, value string) string {
+	switch field {
+	case "discord_channel", "voice_room":
+		return fmt.Sprintf("<#%s>", value)
+	case "status":
+		if value == string(status.NotStarted) {
+			return "`" + status.AlternateNotStarted + "`"
+		}
+	}
+	if runes := []rune(value); len(runes) > 80 {
+		value = string(runes[:77]) + "..."
+	}
+	return "`" + strings.ReplaceAll(value, "`", "'") + "`"
+}
+
+func (b *PuzzleBot) handleTag(ctx context.Context, input *discord.CommandInput) (string, error) {
+	puzzle, err := b.state.GetPuzzleByChannel(ctx, input.IC.ChannelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ":butterfly: I can't find a puzzle associated with this channel. Is this a puzzle channel?", nil
+	} else if err != nil {
+		return "", err
+	}
+	opt, ok := input.Options["tag"]
+	if !ok {
+		return "", xerrors.Errorf("missing option: tag")
+	}
+	var tag = state.NormalizeTag(opt.StringValue())
+
+	var reply string
+	if input.Subcommand == "tag.add" {
+		if puzzle.HasTag(tag) {
+			return fmt.Sprintf(":elephant: This puzzle is already tagged `%s`.", tag), nil
+		}
+		puzzle, _, err = b.state.AddPuzzleTag(ctx, puzzle.ID, tag)
+		reply = fmt.Sprintf(":label: Tagged this puzzle `%s`.", tag)
+	} else {
+		if !puzzle.HasTag(tag) {
+			return fmt.Sprintf(":elephant: This puzzle isn't tagged `%s`.", tag), nil
+		}
+		puzzle, _, err = b.state.RemovePuzzleTag(ctx, puzzle.ID, tag)
+		reply = fmt.Sprintf(":cl: Removed tag `%s`.", tag)
+	}
+	var ve state.ValidationError
+	if errors.As(err, &ve) {
+		return fmt.Sprintf(":no_entry_sign: Invalid tag: %s.", ve.Message), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if tags := puzzle.TagList(); len(tags) > 0 {
+		reply += fmt.Sprintf(" Tags are now: `%s`.", strings.Join(tags, "`, `"))
+	}
+	return reply, nil
+}
+
+func (b *PuzzleBot) handleSolvers(ctx context.Context, input *discord.CommandInput) (string, error) {
+	puzzle, err := b.state.GetPuzzleByChannel(ctx, input.IC.ChannelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ":butterfly: I can't find a puzzle associated with this channel. Is this a puzzle channel?", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var reply string
+	if input.Subcommand == "join" {
+		if puzzle.HasSolver(input.User.ID) {
+			return ":elephant: You're already working on this puzzle.", nil
+		}
+		puzzle, _, err = b.state.JoinPuzzle(ctx, puzzle.ID, input.User.ID)
+		reply = ":raising_hand: Signed you up for this puzzle. Good luck!"
+	} else {
+		if !puzzle.HasSolver(input.User.ID) {
+			return ":elephant: You aren't signed up for this puzzle.", nil
+		}
+		puzzle, _, err = b.state.LeavePuzzle(ctx, puzzle.ID, input.User.ID)
+		reply = ":wave: Took you off this puzzle. Thanks for your help!"
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if solvers := puzzle.SolverList(); len(solvers) > 0 {
+		var names []string
+		for _, solver := range solvers {
+			names = append(names, b.discord.DisplayName(&discordgo.User{ID: solver}))
+		}
+		reply += fmt.Sprintf(" Solvers are now: %s.", strings.Join(names, ", "))
+	}
+	return reply, nil
+}
+
+func (b *PuzzleBot) handleFeeds(ctx context.Context, input *discord.CommandInput) (string, error) {
+	puzzle, err := b.state.GetPuzzleByChannel(ctx, input.IC.ChannelID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ":butterfly: I can't find a puzzle associated with this channel. Is this a puzzle channel?", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if input.Subcommand == "feeds.list" {
+		metas, err := b.state.ListFedMetas(ctx, puzzle.ID)
+		if err != nil {
+			return "", err
+		}
+		feeders, err := b.state.ListFeeders(ctx, puzzle.ID)
+		if err != nil {
+			return "", err
+		}
+		if len(metas) == 0 && len(feeders) == 0 {
+			return ":link: This puzzle isn't linked to any metas yet. " +
+				"Use `/puzzle feeds add` to record one.", nil
+		}
+		var msg string
+		if len(metas) > 0 {
+			msg += ":link: This puzzle feeds into:\n"
+			for _, meta := range metas {
+				msg += fmt.Sprintf(" • %s\n", feedMention(meta))
+			}
+		}
+		if len(feeders) > 0 {
+			msg += ":link: This meta's feeders:\n"
+			for _, feeder := range feeders {
+				if feeder.Answer == "" {
+					msg += fmt.Sprintf(" • %s\n", feedMention(feeder))
+				} else {
+					msg += fmt.Sprintf(" • %s: `%s`\n", feedMention(feeder), feeder.Answer)
+				}
+			}
+		}
+		return msg, nil
+	}
+
+	var option = "into"
+	if input.Subcommand == "feeds.remove" {
+		option = "from"
+	}
+	opt, ok := input.Options[option]
+	if !ok {
+		return "", xerrors.Errorf("missing option: %s", option)
+	}
+	meta, err := b.state.GetPuzzleByChannel(ctx, opt.Value.(string))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Sprintf(":butterfly: <#%s> isn't a puzzle channel.", opt.Value), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	var feed = state.PuzzleFeed{Puzzle: puzzle.ID, Meta: meta.ID}
+	if input.Subcommand == "feeds.add" {
+		_, err = b.state.CreateFeed(ctx, feed)
+		var ve state.ValidationError
+		if errors.As(err, &ve) {
+			return fmt.Sprintf(":no_entry_sign: Can't link to %s: %s.", meta.Mention(), ve.Message), nil
+		} else if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(":link: Recorded that this puzzle feeds into %s.", meta.Mention()), nil
+	} else {
+		_, err = b.state.DeleteFeed(ctx, feed)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Sprintf(":elephant: This puzzle wasn't recorded as feeding into %s.", meta.Mention()), nil
+		} else if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(":broken_chain: Recorded that this puzzle doesn't feed into %s.", meta.Mention()), nil
+	}
+}
+
+func feedMention(info state.FeedInfo) string {
+	if info.DiscordChannel == "" {
+		return fmt.Sprintf("%q", info.Name)
+	}
+	return fmt.Sprintf("<#%s>", info.DiscordChannel)
+}
+
 func (b *PuzzleBot) HandleScheduledEvent(ctx context.Context,
 	i *discordgo.GuildScheduledEventUpdate) error {
 
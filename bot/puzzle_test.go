@@ -0,0 +1,29 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFormatHistoryValue(t *testing.T) {
+	for _, tc := range []struct {
+		field, value, want string
+	}{
+		{"note", "short", "`short`"},
+		{"note", "it's `code`", "`it's 'code'`"},
+		{"discord_channel", "1234", "<#1234>"},
+		{"status", "", "`Not Started`"},
+		{"note", strings.Repeat("a", 80), "`" + strings.Repeat("a", 80) + "`"},
+		{"note", strings.Repeat("a", 81), "`" + strings.Repeat("a", 77) + "...`"},
+		// Long values are cut by character, not by byte
+		{"note", strings.Repeat("🍎", 81), "`" + strings.Repeat("🍎", 77) + "...`"},
+	} {
+		var got = formatHistoryValue(tc.field, tc.value)
+		if got != tc.want {
+			t.Errorf("formatHistoryValue(%q, %q): got %q, want %q", tc.field, tc.value, got, tc.want)
+		} else if !utf8.ValidString(got) {
+			t.Errorf("formatHistoryValue(%q, %q): got invalid UTF-8", tc.field, tc.value)
+		}
+	}
+}
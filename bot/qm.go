@@ -3,13 +3,23 @@ package bot
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/emojihunt/emojihunt/discord"
+	"github.com/emojihunt/emojihunt/discovery"
 	"github.com/emojihunt/emojihunt/state"
 	"golang.org/x/xerrors"
 )
 
+// Keep /qm trash list and /qm discovery preview under Discord's message length
+// limit
+const (
+	trashListLimit   = 20
+	previewListLimit = 10
+)
+
 type QMBot struct {
 	discord *discord.Client
 	state   *state.Client
@@ -58,8 +68,30 @@ func (b *QMBot) Register() (*discordgo.ApplicationCommand, bool) {
 					},
 				},
 			},
+			{
+				Name:        "stats",
+				Description: "Summarize the team's progress 📊",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "trash",
+				Description: "Manage deleted puzzles and rounds.",
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "list",
+						Description: "List the puzzles and rounds in the trash 🗑️",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+					},
+					{
+						Name:        "purge",
+						Description: "Permanently delete everything in the trash 🔥",
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+					},
+				},
+			},
 		},
-	}, false
+	}, true
 }
 
 func (b *QMBot) Handle(ctx context.Context, input *discord.CommandInput) (string, error) {
@@ -100,11 +132,135 @@ func (b *QMBot) Handle(ctx context.Context, input *discord.CommandInput) (string
 		} else {
 			return "Discovery was already enabled. Pause it with `/qm discovery pause`.", nil
 		}
+	case "discovery.preview":
+		if config.PuzzlesURL == "" {
+			return "Puzzle discovery isn't configured.", nil
+		}
+		poller, err := discovery.NewPoller(config)
+		if err != nil {
+			return "", err
+		}
+		puzzles, errs := poller.Scrape(ctx)
+		preview, err := discovery.PreviewScrape(ctx, b.state, puzzles)
+		if err != nil {
+			return "", err
+		}
+		return formatPreview(len(puzzles), preview, discovery.Unjoin(errs)), nil
+	case "stats":
+		stats, err := b.state.Stats(ctx)
+		if err != nil {
+			return "", err
+		}
+		return formatStats(stats), nil
+	case "trash.list":
+		puzzles, rounds, err := b.state.ListTrash(ctx)
+		if err != nil {
+			return "", err
+		} else if len(puzzles) == 0 && len(rounds) == 0 {
+			return "The trash is empty.", nil
+		}
+		var lines []string
+		for _, round := range rounds {
+			lines = append(lines, fmt.Sprintf("• %s %s (round, deleted <t:%d:R>)",
+				round.Emoji, round.Name, round.DeletedAt.Unix()))
+		}
+		for _, puzzle := range puzzles {
+			lines = append(lines, fmt.Sprintf("• %s %s (deleted <t:%d:R>)",
+				puzzle.Round.Emoji, puzzle.Mention(), puzzle.DeletedAt.Unix()))
+		}
+		var reply = "Trash:\n" + strings.Join(limitLines(lines, trashListLimit), "\n") + "\n"
+		return reply + "Restore items from the web UI, or purge them with `/qm trash purge`.", nil
+	case "trash.purge":
+		puzzles, rounds, err := b.state.PurgeTrash(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Ok, I've permanently deleted %d puzzle(s) and %d round(s). "+
+			"Their channels and spreadsheets need to be cleaned up manually.",
+			puzzles, rounds), nil
 	default:
 		return "", xerrors.Errorf("unexpected /qm subcommand: %q", input.Subcommand)
 	}
 }
 
+func formatPreview(scraped int, preview discovery.Preview, errs []error) string {
+	var reply = fmt.Sprintf("🔍 Found %d puzzle(s) on the hunt site. "+
+		"This is a preview, nothing has been changed.\n", scraped)
+	for _, err := range errs {
+		reply += fmt.Sprintf("⚠️ Couldn't scrape %s\n", err)
+	}
+
+	var lines []string
+	for _, puzzle := range preview.Create {
+		lines = append(lines, fmt.Sprintf("• %s (%s)", puzzle.Name, puzzle.RoundName))
+	}
+	if len(lines) > 0 {
+		reply += fmt.Sprintf("**Would create %d puzzle(s):**\n", len(lines))
+		reply += strings.Join(limitLines(lines, previewListLimit), "\n") + "\n"
+	}
+
+	lines = nil
+	for _, round := range preview.Rounds {
+		var status = "new, would be posted here for approval"
+		if round.Ignored {
+			status = "ignored by the QMs"
+		} else if round.Recreate {
+			status = "deleted since it was approved, would be posted here again"
+		} else if round.Pending {
+			status = "already waiting for approval"
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %d puzzle(s), %s",
+			round.Name, len(round.Puzzles), status))
+	}
+	if len(lines) > 0 {
+		reply += fmt.Sprintf("**%d round(s) need approval:**\n", len(lines))
+		reply += strings.Join(limitLines(lines, previewListLimit), "\n") + "\n"
+	}
+
+	reply += fmt.Sprintf("**Ignored %d duplicate(s)** (already created or discovered)",
+		len(preview.Duplicates))
+	return reply
+}
+
+// Truncates the list, noting how many items were left off
+func limitLines(lines []string, limit int) []string {
+	if len(lines) <= limit {
+		return lines
+	}
+	var more = len(lines) - limit
+	return append(lines[:limit:limit], fmt.Sprintf("• ...and %d more", more))
+}
+
+func formatStats(stats state.Stats) string {
+	var reply = fmt.Sprintf("📊 **%d/%d** puzzles solved, **%d/%d** metas solved",
+		stats.Solved, stats.Unlocked, stats.MetasSolved, stats.MetasTotal)
+	if len(stats.SolvesPerHour) > 0 {
+		reply += fmt.Sprintf(", %d in the last hour", stats.SolvesLastHour)
+	}
+	reply += "\n"
+	for _, round := range stats.Rounds {
+		reply += fmt.Sprintf("%s %s: %d/%d", round.Emoji, round.Name, round.Solved, round.Unlocked)
+		if round.MedianSolveSeconds > 0 {
+			var median = time.Duration(round.MedianSolveSeconds) * time.Second
+			reply += fmt.Sprintf(", median solve %s", formatHoursMinutes(median))
+		}
+		reply += "\n"
+	}
+	for i, puzzle := range stats.LongestOpen {
+		if i == 0 {
+			reply += "Longest open:"
+		} else if i == 3 {
+			break
+		}
+		reply += fmt.Sprintf(" %q (<t:%d:R>)", puzzle.Name, puzzle.UnlockedAt.Unix())
+	}
+	return strings.TrimSpace(reply)
+}
+
+func formatHoursMinutes(d time.Duration) string {
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
 func (b *QMBot) HandleScheduledEvent(context.Context,
 	*discordgo.GuildScheduledEventUpdate) error {
 	return nil
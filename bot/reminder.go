@@ -2,8 +2,12 @@ package bot
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -11,6 +15,7 @@ import (
 	"github.com/emojihunt/emojihunt/huntyet"
 	"github.com/emojihunt/emojihunt/state"
 	"github.com/getsentry/sentry-go"
+	"golang.org/x/xerrors"
 )
 
 type ReminderBot struct {
@@ -35,18 +40,156 @@ func NewReminderBot(main context.Context, discord *discord.Client, state *state.
 
 func (b *ReminderBot) Register() (*discordgo.ApplicationCommand, bool) {
 	return &discordgo.ApplicationCommand{
-		Name:        "reminders",
-		Description: "List all puzzle reminders ⏱️",
+		Name:        "remind",
+		Description: "Set, list and cancel reminders ⏱️",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Name:        "create",
+				Description: "Set a reminder for this puzzle, or for the team if used elsewhere ⏰",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "at",
+						Description: "When? e.g. \"3pm\", \"Sat 15:30\" or \"in 45m\" (Boston time)",
+						Required:    true,
+						Type:        discordgo.ApplicationCommandOptionString,
+					},
+					{
+						Name:        "message",
+						Description: "What should I say?",
+						Required:    true,
+						Type:        discordgo.ApplicationCommandOptionString,
+					},
+					{
+						Name:        "before",
+						Description: "Send heads-ups this long before, e.g. \"1h,15m\"",
+						Required:    false,
+						Type:        discordgo.ApplicationCommandOptionString,
+					},
+					{
+						Name:        "every",
+						Description: "Repeat this often, e.g. \"4h\"",
+						Required:    false,
+						Type:        discordgo.ApplicationCommandOptionString,
+					},
+				},
+			},
+			{
+				Name:        "list",
+				Description: "List all reminders 📋",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+			},
+			{
+				Name:        "cancel",
+				Description: "Cancel a reminder 🗑️",
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Name:        "id",
+						Description: "The reminder's number, from /remind list",
+						Required:    true,
+						Type:        discordgo.ApplicationCommandOptionInteger,
+					},
+				},
+			},
+		},
 	}, false
 }
 
 func (b *ReminderBot) Handle(ctx context.Context, input *discord.CommandInput) (string, error) {
+	switch input.Subcommand {
+	case "create":
+		return b.handleCreate(ctx, input)
+	case "list":
+		return b.handleList(ctx)
+	case "cancel":
+		id := input.Options["id"].IntValue()
+		err := b.state.CancelReminder(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Sprintf(":butterfly: I can't find reminder #%d.", id), nil
+		} else if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(":wastebasket: Cancelled reminder #%d.", id), nil
+	default:
+		return "", xerrors.Errorf("unexpected /remind subcommand: %q", input.Subcommand)
+	}
+}
+
+func (b *ReminderBot) handleCreate(ctx context.Context, input *discord.CommandInput) (string, error) {
+	var reminder = state.Reminder{
+		DiscordChannel: input.IC.ChannelID,
+		Message:        input.Options["message"].StringValue(),
+		CreatedBy:      input.User.ID,
+	}
+	var err error
+	var at = input.Options["at"].StringValue()
+	if reminder.RemindAt, err = parseWhen(at, time.Now()); err != nil {
+		return fmt.Sprintf(":no_entry_sign: I don't understand %q: %s.", at, err), nil
+	}
+	if opt, ok := input.Options["before"]; ok {
+		var leads []time.Duration
+		for _, item := range strings.Split(opt.StringValue(), ",") {
+			lead, err := parseMinutes(item)
+			if err != nil {
+				return fmt.Sprintf(":no_entry_sign: I don't understand %q: %s.", item, err), nil
+			}
+			leads = append(leads, lead)
+		}
+		reminder.LeadMinutes = state.FormatLeadTimes(leads)
+	}
+	if opt, ok := input.Options["every"]; ok {
+		every, err := parseMinutes(opt.StringValue())
+		if err != nil {
+			return fmt.Sprintf(":no_entry_sign: I don't understand %q: %s.", opt.StringValue(), err), nil
+		}
+		reminder.EveryMinutes = int64(every / time.Minute)
+	}
+
+	// Reminders set in a puzzle channel belong to the puzzle
+	puzzle, err := b.state.GetPuzzleByChannel(ctx, input.IC.ChannelID)
+	if err == nil {
+		reminder.Puzzle = sql.NullInt64{Int64: puzzle.ID, Valid: true}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	reminder, err = b.state.CreateReminder(ctx, reminder)
+	var ve state.ValidationError
+	if errors.As(err, &ve) {
+		return fmt.Sprintf(":no_entry_sign: Couldn't set that reminder: %s.", ve.Error()), nil
+	} else if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(":alarm_clock: Set reminder #%d for %s.",
+		reminder.ID, formatReminder(reminder, time.Now())), nil
+}
+
+func (b *ReminderBot) handleList(ctx context.Context) (string, error) {
+	now := time.Now()
+	reminders, err := b.state.ListReminders(ctx)
+	if err != nil {
+		return "", err
+	}
+	// Upcoming reminders first; passed ones sink to the bottom
+	slices.SortStableFunc(reminders, func(a, b state.Reminder) int {
+		var x, y = state.NextOccurrence(a, now), state.NextOccurrence(b, now)
+		if x.Before(now) && !y.Before(now) {
+			return 1
+		} else if !x.Before(now) && y.Before(now) {
+			return -1
+		}
+		return x.Compare(y)
+	})
+
 	results, err := b.state.ListPuzzles(ctx)
 	if err != nil {
 		return "", err
 	}
 	var puzzles []state.Puzzle
+	var names = make(map[int64]string)
 	for _, puzzle := range results {
+		names[puzzle.ID] = puzzle.Mention()
 		if puzzle.HasReminder() {
 			puzzles = append(puzzles, puzzle)
 		}
@@ -55,12 +198,20 @@ func (b *ReminderBot) Handle(ctx context.Context, input *discord.CommandInput) (
 		return a.Reminder.Compare(b.Reminder)
 	})
 
-	if len(puzzles) < 1 {
-		return ":zero: There are no puzzle reminders. Use the `Reminder` field in " +
-			"the puzzle tracker to set a reminder.", nil
+	if len(reminders) < 1 && len(puzzles) < 1 {
+		return ":zero: There are no reminders. Use `/remind create`, or the " +
+			"`Reminder` field in the puzzle tracker, to set a reminder.", nil
 	}
 
 	msg := ":calendar_spiral: Reminders:\n"
+	for _, reminder := range reminders {
+		var prefix string
+		if reminder.Puzzle.Valid {
+			prefix = names[reminder.Puzzle.Int64] + ": "
+		}
+		msg += fmt.Sprintf(" • #%d %s%s — %s\n", reminder.ID, prefix,
+			reminder.Message, formatReminder(reminder, now))
+	}
 	for _, puzzle := range puzzles {
 		suffix := ""
 		if time.Now().After(puzzle.Reminder) {
@@ -78,6 +229,100 @@ func (b *ReminderBot) Handle(ctx context.Context, input *discord.CommandInput) (
 	return msg, nil
 }
 
+// Describes when the reminder goes off, e.g. "Sat 3:00 PM ET, every 4h, with
+// heads-ups 1h, 15m before".
+func formatReminder(reminder state.Reminder, now time.Time) string {
+	var next = state.NextOccurrence(reminder, now)
+	var msg = next.In(huntyet.BostonTime).Format("Mon 3:04 PM") + " ET"
+	if reminder.EveryMinutes > 0 {
+		msg += ", every " + formatMinutes(time.Duration(reminder.EveryMinutes)*time.Minute)
+	}
+	if leads := state.ReminderLeadTimes(reminder); len(leads) > 0 {
+		var items []string
+		for _, lead := range leads {
+			items = append(items, formatMinutes(lead))
+		}
+		msg += ", with heads-ups " + strings.Join(items, ", ") + " before"
+	}
+	if next.Before(now) {
+		msg += " (passed)"
+	}
+	return msg
+}
+
+// Formats a duration like "1h30m", without the seconds.
+func formatMinutes(d time.Duration) string {
+	var s = d.Round(time.Minute).String()
+	s = strings.TrimSuffix(s, "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Parses a duration like "1h30m" or "45m". Bare numbers are minutes.
+func parseMinutes(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if minutes, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, xerrors.New("durations look like \"90\", \"1h30m\" or \"45m\"")
+	} else if d < time.Minute {
+		return 0, xerrors.New("durations must be at least a minute")
+	}
+	return d, nil
+}
+
+// Parses a time like "in 45m", "3pm", "15:30", or "Sat 3:30 PM". Times are in
+// Boston, and refer to the next time the clock reads that (on the given day of
+// the week, if any).
+func parseWhen(s string, now time.Time) (time.Time, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if rest, ok := strings.CutPrefix(s, "IN "); ok {
+		d, err := parseMinutes(strings.ToLower(rest))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	var weekday = -1
+	if day, rest, ok := strings.Cut(s, " "); ok {
+		for i := time.Sunday; i <= time.Saturday; i++ {
+			if len(day) >= 3 && strings.HasPrefix(strings.ToUpper(i.String()), day) {
+				weekday, s = int(i), strings.TrimSpace(rest)
+			}
+		}
+	}
+
+	var clock time.Time
+	var err = xerrors.New("times look like \"3pm\", \"Sat 15:30\" or \"in 45m\"")
+	for _, layout := range []string{"3PM", "3:04PM", "3 PM", "3:04 PM", "15:04"} {
+		if t, e := time.Parse(layout, s); e == nil {
+			clock, err = t, nil
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var local = now.In(huntyet.BostonTime)
+	var t = time.Date(local.Year(), local.Month(), local.Day(),
+		clock.Hour(), clock.Minute(), 0, 0, huntyet.BostonTime)
+	if weekday >= 0 {
+		t = t.AddDate(0, 0, (weekday-int(t.Weekday())+7)%7)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 7)
+		}
+	} else if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
 func (b *ReminderBot) worker(main context.Context) {
 	ctx, cancel := context.WithCancel(main)
 	defer cancel()
@@ -111,6 +356,9 @@ func (b *ReminderBot) worker(main context.Context) {
 	}
 }
 
+// Sends the reminders that went off in the window (since, now], and returns
+// now. Since the worker saves the returned time as the new ReminderTimestamp,
+// each reminder is sent exactly once, even across restarts.
 func (b *ReminderBot) notify(ctx context.Context, since time.Time) (*time.Time, error) {
 	now := time.Now()
 
@@ -119,7 +367,9 @@ func (b *ReminderBot) notify(ctx context.Context, since time.Time) (*time.Time,
 		return nil, err
 	}
 
+	var byID = make(map[int64]state.Puzzle)
 	for _, puzzle := range puzzles {
+		byID[puzzle.ID] = puzzle
 		if !puzzle.HasReminder() {
 			continue
 		}
@@ -152,6 +402,43 @@ func (b *ReminderBot) notify(ctx context.Context, since time.Time) (*time.Time,
 		}
 	}
 
+	reminders, err := b.state.ListReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, reminder := range reminders {
+		at, lead, ok := state.ReminderDue(reminder, since, now)
+		if !ok {
+			continue
+		}
+		var subject = reminder.Message
+		if puzzle, ok := byID[reminder.Puzzle.Int64]; reminder.Puzzle.Valid && ok {
+			subject = fmt.Sprintf("%s (%s)", reminder.Message, puzzle.Mention())
+		}
+
+		var msg string
+		if lead > 0 {
+			msg = fmt.Sprintf(":hourglass_flowing_sand: Reminder: %s in %s",
+				subject, formatMinutes(at.Sub(now)))
+		} else {
+			msg = fmt.Sprintf(":alarm_clock: It's time! %s (%s ET)",
+				subject, at.In(huntyet.BostonTime).Format("Mon 3:04 PM"))
+		}
+
+		// Puzzle reminders are copied to the QM channel, like the puzzle's own
+		// reminder
+		if reminder.Puzzle.Valid {
+			_, err = b.discord.ChannelSend(b.discord.QMChannel, msg)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = b.discord.ChannelSendRawID(reminder.DiscordChannel, msg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &now, nil
 }
 
@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emojihunt/emojihunt/huntyet"
+)
+
+func boston(month time.Month, day, hour, minute int) time.Time {
+	return time.Date(2026, month, day, hour, minute, 0, 0, huntyet.BostonTime)
+}
+
+func TestParseWhen(t *testing.T) {
+	var friday = boston(time.January, 16, 14, 0)
+	for _, tc := range []struct {
+		input string
+		now   time.Time
+		want  time.Time
+	}{
+		{"in 45m", friday, boston(time.January, 16, 14, 45)},
+		{"in 90", friday, boston(time.January, 16, 15, 30)},
+		{"IN 1h30m", friday, boston(time.January, 16, 15, 30)},
+		{"3pm", friday, boston(time.January, 16, 15, 0)},
+		{"3:30 PM", friday, boston(time.January, 16, 15, 30)},
+		{" 15:30 ", friday, boston(time.January, 16, 15, 30)},
+
+		// Times that have passed today roll over to tomorrow
+		{"2pm", friday, boston(time.January, 17, 14, 0)},
+		{"9:15 am", friday, boston(time.January, 17, 9, 15)},
+		{"1am", boston(time.January, 31, 23, 0), boston(time.February, 1, 1, 0)},
+
+		// ...and days of the week to next week
+		{"Sat 3:30 PM", friday, boston(time.January, 17, 15, 30)},
+		{"fri 3pm", friday, boston(time.January, 16, 15, 0)},
+		{"Fri 1pm", friday, boston(time.January, 23, 13, 0)},
+		{"friday 2pm", friday, boston(time.January, 23, 14, 0)},
+		{"Thu 10:00", friday, boston(time.January, 22, 10, 0)},
+
+		// The clock time is kept across daylight saving time
+		{"Sun 2pm", boston(time.March, 7, 14, 0), boston(time.March, 8, 14, 0)},
+		{"3pm", boston(time.March, 7, 16, 0), boston(time.March, 8, 15, 0)},
+
+		// Times are in Boston, whatever zone now is in
+		{"3pm", friday.UTC(), boston(time.January, 16, 15, 0)},
+	} {
+		got, err := parseWhen(tc.input, tc.now)
+		if err != nil {
+			t.Errorf("parseWhen(%q): %v", tc.input, err)
+		} else if !got.Equal(tc.want) {
+			t.Errorf("parseWhen(%q): got %v, want %v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParseWhenErrors(t *testing.T) {
+	var now = boston(time.January, 16, 14, 0)
+	for _, input := range []string{
+		"", "tomorrow", "25:00", "in 30s", "in soon", "Fr 3pm", "Sat",
+	} {
+		if got, err := parseWhen(input, now); err == nil {
+			t.Errorf("parseWhen(%q): got %v, want error", input, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package discord
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -76,3 +77,50 @@ func (c *Client) RegisterBots(bots ...Bot) {
 		}
 	}()
 }
+
+// A ComponentHandler handles interactions with message components, like
+// buttons and select menus, and with modals. They're routed by custom ID, which
+// should be built with ComponentID and the handler's prefix. The handler
+// usually responds by updating the message the component is attached to.
+type ComponentHandler func(context.Context, *ComponentInput) (*discordgo.InteractionResponse, error)
+
+type ComponentInput struct {
+	client   *Client
+	deferred bool
+
+	IC     *discordgo.InteractionCreate
+	User   *discordgo.User
+	Args   []string          // from ComponentID
+	Values []string          // for select menus
+	Fields map[string]string // for modals, by the text input's custom ID
+}
+
+// Defer acknowledges the interaction, for handlers that may take more than 3
+// seconds. The message is then updated with the handler's response once it
+// returns.
+func (i *ComponentInput) Defer() error {
+	err := i.client.s.InteractionRespond(i.IC.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		return xerrors.Errorf("InteractionRespond: %w", err)
+	}
+	i.deferred = true
+	return nil
+}
+
+// ComponentID builds the custom ID for a message component, which is routed to
+// the handler registered with the given prefix. Neither the prefix nor the args
+// may contain colons.
+func ComponentID(prefix string, args ...string) string {
+	return strings.Join(append([]string{prefix}, args...), ":")
+}
+
+func (c *Client) RegisterComponentHandler(prefix string, handler ComponentHandler) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.componentHandlers[prefix]; ok {
+		panic("duplicate component handler: " + prefix)
+	}
+	c.componentHandlers[prefix] = handler
+}
@@ -41,6 +41,7 @@ func (c *Client) handleChannelDelete(ctx context.Context, r *discordgo.ChannelDe
 		c.state.DiscoveryChange <- true
 	}
 	c.mutex.Unlock()
+	ctx = state.WithActor(ctx, state.ActorSync)
 	c.state.UpdatePuzzleByDiscordChannel(ctx, r.ID,
 		func(puzzle *state.RawPuzzle) error {
 			if puzzle.DiscordChannel == r.ID {
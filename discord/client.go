@@ -60,6 +60,7 @@ type Client struct {
 
 	ably                      *ably.RealtimeChannel
 	commandsRegistered        bool
+	componentHandlers         map[string]ComponentHandler // by prefix
 	channelCache              map[string]*discordgo.Channel
 	memberCache               map[string]*discordgo.Member
 	webhookCache              map[string]*discordgo.Webhook
@@ -103,6 +104,7 @@ func Connect(ctx context.Context, prod bool, state *state.Client,
 		TeamCategoryID:            config.TeamCategoryID,
 		ably:                      ably.Channels.Get(ablyChannelName),
 		botsByCommand:             make(map[string]*botRegistration),
+		componentHandlers:         make(map[string]ComponentHandler),
 		channelCache:              make(map[string]*discordgo.Channel),
 		scheduledEventsLastUpdate: time.Now().Add(-24 * time.Hour),
 		rateLimits:                make(map[string]*time.Time),
@@ -214,6 +216,7 @@ func Connect(ctx context.Context, prod bool, state *state.Client,
 func (c *Client) RegisterHandlers(ctx context.Context) {
 	// Register handlers. Remember to register the necessary intents above!
 	c.s.AddHandler(WrapHandler(ctx, "bot.unknown", c.handleCommand))
+	c.s.AddHandler(WrapHandler(ctx, "component.unknown", c.handleComponent))
 	c.s.AddHandler(WrapHandler(ctx, "bot.unknown", c.handleScheduledEvent))
 	c.s.AddHandler(WrapHandler(ctx, "rate_limit", c.handleRateLimit))
 
@@ -278,6 +281,16 @@ func (c *Client) ChannelSendComponents(ch *discordgo.Channel, msg string,
 	return sent.ID, nil
 }
 
+// ChannelSendComplex sends a message with embeds and any number of rows of
+// components. Returns the message ID.
+func (c *Client) ChannelSendComplex(ch *discordgo.Channel, msg *discordgo.MessageSend) (string, error) {
+	sent, err := c.s.ChannelMessageSendComplex(ch.ID, msg)
+	if err != nil {
+		return "", xerrors.Errorf("ChannelMessageSendComplex: %w", err)
+	}
+	return sent.ID, nil
+}
+
 func (c *Client) ChannelSendRawID(chID, msg string) error {
 	if _, err := c.s.ChannelMessageSend(chID, msg); err != nil {
 		return xerrors.Errorf("ChannelMessageSend: %w", err)
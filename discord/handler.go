@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/emojihunt/emojihunt/state"
 	"github.com/getsentry/sentry-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -118,7 +119,8 @@ func (c *Client) handleCommand(
 		}
 	}
 
-	// Call the handler!
+	// Call the handler! Changes it makes are attributed to the calling user.
+	ctx = state.WithActor(ctx, state.Actor(input.User.ID))
 	reply, err := command.Handle(ctx, input)
 	if err != nil {
 		sentry.GetHubFromContext(ctx).CaptureException(
@@ -146,6 +148,97 @@ func (c *Client) handleCommand(
 	}
 }
 
+// Message Component Handling
+
+func (c *Client) handleComponent(
+	ctx context.Context, i *discordgo.InteractionCreate,
+) error {
+	input := &ComponentInput{client: c, IC: i, User: i.User}
+	if input.User == nil {
+		input.User = i.Member.User
+	}
+
+	var customID string
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		var data = i.MessageComponentData()
+		customID, input.Values = data.CustomID, data.Values
+	case discordgo.InteractionModalSubmit:
+		var data = i.ModalSubmitData()
+		customID, input.Fields = data.CustomID, make(map[string]string)
+		for _, row := range data.Components {
+			if row, ok := row.(*discordgo.ActionsRow); ok {
+				for _, field := range row.Components {
+					if field, ok := field.(*discordgo.TextInput); ok {
+						input.Fields[field.CustomID] = field.Value
+					}
+				}
+			}
+		}
+	default:
+		return nil
+	}
+
+	var parts = strings.Split(customID, ":")
+	input.Args = parts[1:]
+	c.mutex.Lock()
+	handler, ok := c.componentHandlers[parts[0]]
+	c.mutex.Unlock()
+	if !ok {
+		return xerrors.Errorf("unknown component %q", parts[0])
+	}
+
+	log.Printf("handling component %s from @%s", parts[0], input.User.Username)
+	commandsHandled.WithLabelValues(parts[0], input.User.Username).Inc()
+	sentry.GetHubFromContext(ctx).ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTag("task", fmt.Sprintf("component.%s", parts[0]))
+	})
+
+	// Changes the handler makes are attributed to the clicking user. Errors are
+	// shown only to them, and the message is left as-is so they can retry.
+	ctx = state.WithActor(ctx, state.Actor(input.User.ID))
+	response, err := handler(ctx, input)
+	if err != nil {
+		sentry.GetHubFromContext(ctx).CaptureException(
+			xerrors.Errorf("%s: %w", parts[0], err),
+		)
+		var msg = fmt.Sprintf("🚨 Error! Please ping in %s for help.\n```%s```",
+			c.QMChannel.Mention(), err.Error())
+		if input.deferred {
+			_, err := c.s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+				Content: msg, Flags: discordgo.MessageFlagsEphemeral,
+			})
+			if err != nil {
+				return xerrors.Errorf("FollowupMessageCreate: %w", err)
+			}
+			return nil
+		}
+		response = &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: msg, Flags: discordgo.MessageFlagsEphemeral,
+			},
+		}
+	}
+
+	if input.deferred {
+		// The response can only update the message at this point
+		_, err := c.s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &response.Data.Content,
+			Embeds:     &response.Data.Embeds,
+			Components: &response.Data.Components,
+		})
+		if err != nil {
+			return xerrors.Errorf("InteractionResponseEdit: %w", err)
+		}
+		return nil
+	}
+	if err := c.s.InteractionRespond(i.Interaction, response); err != nil {
+		return xerrors.Errorf("InteractionRespond: %w", err)
+	}
+	return nil
+}
+
 // Scheduled Event Handling
 
 func (c *Client) handleScheduledEvent(
@@ -61,6 +61,17 @@ func (c *Client) handleMessageCreate(
 		Timestamp: m.Timestamp.UnixMilli(),
 		Content:   m.Message.Content,
 	}
+	err := c.state.IndexMessage(ctx, m.Message.ID, m.ChannelID, m.Message.Content)
+	if err != nil {
+		log.Printf("discord: failed to index message %s: %v", m.Message.ID, err)
+	}
+	if m.Author != nil && m.WebhookID == "" {
+		// Posting in a puzzle channel keeps the author's claim on the puzzle alive
+		err = c.state.TouchPuzzleSolver(ctx, m.ChannelID, m.Author.ID, m.Timestamp)
+		if err != nil {
+			log.Printf("discord: failed to record activity for %s: %v", m.Author.ID, err)
+		}
+	}
 	c.state.LiveMessage <- message
 	return c.ably.Publish(ctx, state.EventTypeDiscord, message)
 }
@@ -76,6 +87,10 @@ func (c *Client) handleMessageUpdate(
 		ID:      m.Message.ID,
 		Content: m.Message.Content,
 	}
+	err := c.state.IndexMessage(ctx, m.Message.ID, m.ChannelID, m.Message.Content)
+	if err != nil {
+		log.Printf("discord: failed to index message %s: %v", m.Message.ID, err)
+	}
 	c.state.LiveMessage <- message
 	return c.ably.Publish(ctx, state.EventTypeDiscord, message)
 }
@@ -90,6 +105,9 @@ func (c *Client) handleMessageDelete(
 	var message = AblyMessage{
 		ID: m.Message.ID,
 	}
+	if err := c.state.UnindexMessage(ctx, m.Message.ID); err != nil {
+		log.Printf("discord: failed to unindex message %s: %v", m.Message.ID, err)
+	}
 	c.state.LiveMessage <- message
 	return c.ably.Publish(ctx, state.EventTypeDiscord, message)
 }
@@ -123,6 +141,19 @@ func (c *Client) DisplayName(u *discordgo.User) string {
 	}
 }
 
+// DisplayActor returns a human-readable name for the actor behind a change.
+func (c *Client) DisplayActor(actor state.Actor) string {
+	if actor == "" {
+		return "someone"
+	} else if !actor.IsUser() {
+		return fmt.Sprintf("huntbot (%s)", actor)
+	} else if name := c.DisplayName(&discordgo.User{ID: string(actor)}); name != "" {
+		return name
+	} else {
+		return fmt.Sprintf("<@%s>", actor)
+	}
+}
+
 func (c *Client) DisplayAvatar(u *discordgo.User) string {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
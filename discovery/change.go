@@ -0,0 +1,248 @@
+package discovery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/emojihunt/emojihunt/discord"
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+// Prefix for the buttons on change notices in #qm
+const changeComponent = "discovery.change"
+
+// checkForChanges compares the scraped puzzles against the ones we've created,
+// matching on URL, and logs a change for each puzzle that's been renamed or
+// moved to another round on the hunt site. If the scrape is complete, it also
+// logs the discovered puzzles that are no longer listed.
+func (c *Client) checkForChanges(ctx context.Context, result ScrapeResult) error {
+	puzzles, err := c.state.ListPuzzles(ctx)
+	if err != nil {
+		return err
+	}
+	var byURL = make(map[string]state.Puzzle)
+	for _, puzzle := range puzzles {
+		if puzzle.PuzzleURL != "" {
+			byURL[strings.ToLower(puzzle.PuzzleURL)] = puzzle
+		}
+	}
+
+	// A puzzle can be listed more than once, e.g. a meta that appears in each of
+	// the rounds that feed into it
+	var listings = make(map[int64][]state.ScrapedPuzzle)
+	for _, scraped := range result.Puzzles {
+		if puzzle, ok := byURL[strings.ToLower(scraped.PuzzleURL)]; ok {
+			listings[puzzle.ID] = append(listings[puzzle.ID], scraped)
+		}
+	}
+
+	for _, puzzle := range puzzles {
+		var scraped = listings[puzzle.ID]
+		if len(scraped) == 0 {
+			continue
+		}
+		var renamed, moved = true, true
+		for _, listing := range scraped {
+			if listing.Name == puzzle.Name {
+				renamed = false
+			}
+			if strings.EqualFold(listing.RoundName, puzzle.Round.Name) {
+				moved = false
+			}
+		}
+
+		var change = db.DiscoveredChange{Puzzle: puzzle.ID}
+		if renamed {
+			change.Name = scraped[0].Name
+		}
+		if moved {
+			// Only suggest moving the puzzle to a round that exists. If the round
+			// is new (or a QM has renamed it), there's nothing to move it to.
+			round, err := c.state.GetCreatedRound(ctx, scraped[0].RoundName)
+			if err == nil {
+				change.RoundName = round.Name
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+		if change.Name == "" && change.RoundName == "" {
+			continue
+		}
+		if err := c.logChange(ctx, puzzle, change); err != nil {
+			return err
+		}
+	}
+
+	if !result.Complete {
+		// Puzzles from the failed sources are missing, but haven't disappeared
+		return nil
+	}
+	discovered, err := c.state.ListDiscoveredPuzzles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range discovered {
+		puzzle, ok := byURL[strings.ToLower(item.PuzzleURL)]
+		if !ok || len(listings[puzzle.ID]) > 0 {
+			continue
+		}
+		var change = db.DiscoveredChange{Puzzle: puzzle.ID, Removed: true}
+		if err := c.logChange(ctx, puzzle, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) logChange(ctx context.Context, puzzle state.Puzzle, change db.DiscoveredChange) error {
+	logged, err := c.state.LogDiscoveredChange(ctx, change)
+	if err != nil {
+		return err
+	} else if logged {
+		log.Printf("discovery: puzzle %q has changed on the hunt site (name: %q, round: %q, removed: %v)",
+			puzzle.Name, change.Name, change.RoundName, change.Removed)
+	}
+	return nil
+}
+
+func (c *Client) notifyChange(ctx context.Context, change db.DiscoveredChange) error {
+	puzzle, err := c.state.GetPuzzle(ctx, change.Puzzle)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // in the trash; hold the notice in case it's restored
+	} else if err != nil {
+		return err
+	}
+
+	var msg []string
+	var apply string
+	if change.Removed {
+		msg = append(msg, fmt.Sprintf("👻 %s %s has disappeared from the hunt site.",
+			puzzle.Round.Emoji, puzzle.Mention()))
+	}
+	if change.Name != "" {
+		msg = append(msg, fmt.Sprintf("✏️ %s %s has been renamed to %q on the hunt site "+
+			"(we have it as %q).", puzzle.Round.Emoji, puzzle.Mention(), change.Name, puzzle.Name))
+		apply = "Rename"
+	}
+	if change.RoundName != "" {
+		msg = append(msg, fmt.Sprintf("🚚 %s %s has moved to the %q round on the hunt site.",
+			puzzle.Round.Emoji, puzzle.Mention(), change.RoundName))
+		if apply == "" {
+			apply = "Move"
+		} else {
+			apply += " and move"
+		}
+	}
+
+	var id = strconv.FormatInt(change.ID, 10)
+	var buttons []discordgo.MessageComponent
+	if change.Removed {
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Move to trash",
+			Style:    discordgo.DangerButton,
+			CustomID: discord.ComponentID(changeComponent, "trash", id),
+		})
+	} else {
+		buttons = append(buttons, discordgo.Button{
+			Label:    apply,
+			Style:    discordgo.PrimaryButton,
+			CustomID: discord.ComponentID(changeComponent, "apply", id),
+		})
+	}
+	buttons = append(buttons, discordgo.Button{
+		Label:    "Dismiss",
+		Style:    discordgo.SecondaryButton,
+		CustomID: discord.ComponentID(changeComponent, "dismiss", id),
+	})
+
+	log.Printf("discovery: notifying #qm of changes to puzzle %q", puzzle.Name)
+	change.MessageID, err = c.discord.ChannelSendComponents(
+		c.discord.QMChannel, strings.Join(msg, "\n"), buttons,
+	)
+	if err != nil {
+		return err
+	}
+	change.NotifiedAt = time.Now()
+	return c.state.UpdateDiscoveredChange(ctx, change)
+}
+
+// handleChangeComponent handles the buttons on a change notice. The notice is
+// updated to say what was done, and the buttons are removed.
+func (c *Client) handleChangeComponent(ctx context.Context,
+	input *discord.ComponentInput) (*discordgo.InteractionResponse, error) {
+	if len(input.Args) != 2 {
+		return nil, xerrors.Errorf("unexpected args: %#v", input.Args)
+	}
+	id, err := strconv.ParseInt(input.Args[1], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid change ID: %w", err)
+	}
+	change, err := c.state.GetDiscoveredChange(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result string
+	switch input.Args[0] {
+	case "apply":
+		var round state.Round
+		if change.RoundName != "" {
+			round, err = c.state.GetCreatedRound(ctx, change.RoundName)
+			if errors.Is(err, sql.ErrNoRows) {
+				result = fmt.Sprintf("🤷 The round %q has since been deleted.", change.RoundName)
+				break
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		_, _, err := c.state.UpdatePuzzle(ctx, change.Puzzle, state.AnyVersion,
+			func(puzzle *state.RawPuzzle) error {
+				if change.Name != "" {
+					puzzle.Name = change.Name
+				}
+				if change.RoundName != "" {
+					puzzle.Round = round.ID
+				}
+				return nil
+			},
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			result = "🤷 The puzzle has already been deleted."
+		} else if err != nil {
+			return nil, err
+		} else {
+			result = fmt.Sprintf("✅ Updated by %s.", input.User.Mention())
+		}
+	case "trash":
+		_, err := c.state.DeletePuzzle(ctx, change.Puzzle)
+		if errors.Is(err, sql.ErrNoRows) {
+			result = "🤷 The puzzle has already been deleted."
+		} else if err != nil {
+			return nil, err
+		} else {
+			result = fmt.Sprintf("🗑️ Moved to the trash by %s. Restore it from the web UI.",
+				input.User.Mention())
+		}
+	case "dismiss":
+		result = fmt.Sprintf("🙈 Dismissed by %s.", input.User.Mention())
+	default:
+		return nil, xerrors.Errorf("unexpected action: %q", input.Args[0])
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    input.IC.Message.Content + "\n" + result,
+			Components: []discordgo.MessageComponent{},
+		},
+	}, nil
+}
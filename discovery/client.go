@@ -4,16 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/emojihunt/emojihunt/discord"
-	"github.com/emojihunt/emojihunt/emojiname"
 	"github.com/emojihunt/emojihunt/state"
 	"github.com/emojihunt/emojihunt/state/db"
 	"github.com/emojihunt/emojihunt/syncer"
 	"github.com/getsentry/sentry-go"
+	"golang.org/x/xerrors"
 )
 
 type Client struct {
@@ -21,11 +21,20 @@ type Client struct {
 	state   *state.Client
 	syncer  *syncer.Client
 
-	discovered chan []state.ScrapedPuzzle
+	discovered chan ScrapeResult
+	mutex      sync.Mutex // for round approval
 }
 
 func New(discord *discord.Client, s *state.Client, y *syncer.Client) *Client {
-	return &Client{discord, s, y, make(chan []state.ScrapedPuzzle)}
+	var c = &Client{
+		discord:    discord,
+		state:      s,
+		syncer:     y,
+		discovered: make(chan ScrapeResult),
+	}
+	discord.RegisterComponentHandler(changeComponent, c.handleChangeComponent)
+	discord.RegisterComponentHandler(roundComponent, c.handleRoundComponent)
+	return c
 }
 
 func (c *Client) Watch(ctx context.Context) {
@@ -85,19 +94,29 @@ func (c *Client) SyncWorker(ctx context.Context) {
 		scope.SetTag("task", "discovery.sync")
 	})
 	ctx = sentry.SetHubOnContext(ctx, hub)
+	ctx = state.WithActor(ctx, state.ActorDiscovery)
 	// *do* allow panics to bubble up to main()
 
 	var wakeup = time.Now().Add(roundCreationPause)
 	for {
 		select {
-		case puzzles := <-c.discovered:
-			for _, puzzle := range puzzles {
+		case result := <-c.discovered:
+			for _, puzzle := range result.Puzzles {
 				if !c.state.IsEnabled(ctx) {
 					break
 				}
-				if err := c.handleScrapedPuzzle(ctx, puzzle); err != nil {
+				// If one puzzle fails, keep going: the rest of the batch
+				// shouldn't wait on it.
+				err := c.handleScrapedPuzzle(ctx, puzzle)
+				if err != nil {
+					sentry.GetHubFromContext(ctx).CaptureException(
+						xerrors.Errorf("handleScrapedPuzzle(%q): %w", puzzle.Name, err),
+					)
+				}
+			}
+			if c.state.IsEnabled(ctx) {
+				if err := c.checkForChanges(ctx, result); err != nil {
 					sentry.GetHubFromContext(ctx).CaptureException(err)
-					break
 				}
 			}
 		case <-time.After(time.Until(wakeup)):
@@ -111,10 +130,27 @@ func (c *Client) SyncWorker(ctx context.Context) {
 			continue
 		}
 		for _, round := range rounds {
+			if !c.state.IsEnabled(ctx) {
+				break
+			} else if round.MessageID != "" {
+				continue // waiting for the QMs
+			}
+			err := c.notifyRound(ctx, round)
+			if err != nil {
+				sentry.GetHubFromContext(ctx).CaptureException(err)
+			}
+		}
+
+		changes, err := c.state.ListPendingDiscoveredChanges(ctx)
+		if err != nil {
+			sentry.GetHubFromContext(ctx).CaptureException(err)
+			continue
+		}
+		for _, change := range changes {
 			if !c.state.IsEnabled(ctx) {
 				break
 			}
-			err := c.handleDiscoveredRound(ctx, round)
+			err := c.notifyChange(ctx, change)
 			if err != nil {
 				sentry.GetHubFromContext(ctx).CaptureException(err)
 			}
@@ -139,50 +175,48 @@ func (c *Client) SyncWorker(ctx context.Context) {
 }
 
 func (c *Client) handleScrapedPuzzle(ctx context.Context, record state.ScrapedPuzzle) error {
-	created, err := c.state.IsPuzzleCreated(ctx, record)
-	if err != nil {
-		return err
-	} else if created {
-		return nil // already created
-	}
-	discovered, err := c.state.IsPuzzleDiscovered(ctx, record)
+	action, round, discovered, err := classifyScrapedPuzzle(ctx, c.state, record)
 	if err != nil {
 		return err
-	} else if discovered {
-		return nil // already handled
 	}
 
 	var params = db.CreateDiscoveredPuzzleParams{
 		PuzzleURL: record.PuzzleURL,
 		Name:      record.Name,
 	}
-	round, err := c.state.GetCreatedRound(ctx, record.RoundName)
-	if errors.Is(err, sql.ErrNoRows) {
-		round, err := c.state.GetDiscoveredRound(ctx, record.RoundName)
-		if errors.Is(err, sql.ErrNoRows) {
-			// New round, hasn't even been logged yet
-			new, err := c.state.CreateDiscoveredRound(ctx, record.RoundName)
-			if err != nil {
-				return err
-			}
-			params.DiscoveredRound = sql.NullInt64{Int64: new, Valid: true}
-		} else if err != nil {
+	switch action {
+	case actionIgnore:
+		return nil // already handled
+	case actionNewRound:
+		// New round, hasn't even been logged yet
+		new, err := c.state.CreateDiscoveredRound(ctx, record.RoundName)
+		if err != nil {
 			return err
-		} else {
-			// New round, pending QM approval & creation
-			params.DiscoveredRound = sql.NullInt64{Int64: round.ID, Valid: true}
 		}
+		params.DiscoveredRound = sql.NullInt64{Int64: new, Valid: true}
 		log.Printf("discovery: logging scraped puzzle %q", record.Name)
 		return c.state.CreateDiscoveredPuzzle(ctx, params)
-	} else if err != nil {
-		return err
-	} else {
+	case actionReopen:
+		// The round was created and then deleted, so ask the QMs again
+		log.Printf("discovery: re-opening deleted round %q", discovered.Name)
+		if err := c.state.ReopenDiscoveredRound(ctx, discovered); err != nil {
+			return err
+		}
+		fallthrough
+	case actionWait:
+		// New round, pending QM approval & creation
+		params.DiscoveredRound = sql.NullInt64{Int64: discovered.ID, Valid: true}
+		log.Printf("discovery: logging scraped puzzle %q", record.Name)
+		return c.state.CreateDiscoveredPuzzle(ctx, params)
+	case actionCreate:
 		// Ready to create puzzle
 		err = c.state.CreateDiscoveredPuzzle(ctx, params)
 		if err != nil {
 			return err
 		}
 		return c.createPuzzle(ctx, record, round)
+	default:
+		return xerrors.Errorf("unexpected action: %d", action)
 	}
 }
 
@@ -208,59 +242,6 @@ func (c *Client) createPuzzle(ctx context.Context, record state.ScrapedPuzzle,
 	return err
 }
 
-func (c *Client) handleDiscoveredRound(ctx context.Context, round db.DiscoveredRound) error {
-	if round.MessageID == "" {
-		log.Printf("discovery: notifying #qm of new round %q", round.Name)
-		puzzles, err := c.state.ListDiscoveredPuzzlesForRound(ctx, round.ID)
-		if err != nil {
-			return err
-		}
-
-		msg := fmt.Sprintf("```*** ❓ NEW ROUND: \"%s\" ***\n\n", round.Name)
-		for _, puzzle := range puzzles {
-			msg += fmt.Sprintf("%s\n%s\n\n", puzzle.Name, puzzle.PuzzleURL)
-		}
-		msg += "Reminder: use `/qm discovery pause` to stop the bot.\n\n"
-		msg += fmt.Sprintf(
-			"```\n%s please react to pick an emoji for this round\n",
-			c.discord.QMRole.Mention(),
-		)
-
-		id, err := c.discord.ChannelSend(c.discord.QMChannel, msg)
-		if err != nil {
-			return err
-		}
-		round.MessageID = id
-		round.NotifiedAt = time.Now()
-		return c.state.UpdateDiscoveredRound(ctx, round)
-
-	} else if time.Now().After(round.NotifiedAt.Add(roundCreationPause)) {
-		// It's been a while, check Discord for round emoji
-		emoji, err := c.discord.GetTopReaction(c.discord.QMChannel, round.MessageID)
-		if err != nil {
-			return err
-		} else if emoji == "" {
-			// QM hasn't assigned an emoji yet
-			return nil
-		}
-
-		var hue = emojiname.EmojiHue(emoji)
-		var record = state.Round{Name: round.Name, Emoji: emoji, Hue: int64(hue)}
-		log.Printf("discovery: creating round %q with emoji %q, hue %d",
-			record.Name, record.Emoji, record.Hue)
-		record.DriveFolder, err = c.syncer.CreateDriveFolder(ctx, record)
-		if err != nil {
-			return err
-		}
-		created, _, err := c.state.CreateRound(ctx, record)
-		if err != nil {
-			return err
-		}
-		return c.state.CompleteDiscoveredRound(ctx, round.ID, created)
-	}
-	return nil
-}
-
 func (c *Client) handleCreatablePuzzle(ctx context.Context, row db.ListCreatablePuzzlesRow) error {
 	var scraped = state.ScrapedPuzzle{
 		Name:      row.Name,
@@ -275,8 +256,18 @@ func (c *Client) handleCreatablePuzzle(ctx context.Context, row db.ListCreatable
 		return c.state.CompleteDiscoveredPuzzle(ctx, row.ID)
 	}
 
-	round, err := c.state.GetCreatedRound(ctx, scraped.RoundName)
-	if err != nil {
+	// The QMs may have renamed the round, or merged it into another
+	round, err := c.state.GetRound(ctx, row.CreatedAs)
+	if errors.Is(err, sql.ErrNoRows) {
+		// ...and then deleted it. Leave the puzzle in the queue and ask the QMs
+		// about the round again.
+		discovered, err := c.state.GetDiscoveredRoundByID(ctx, row.DiscoveredRound.Int64)
+		if err != nil {
+			return err
+		}
+		log.Printf("discovery: re-opening deleted round %q", discovered.Name)
+		return c.state.ReopenDiscoveredRound(ctx, discovered)
+	} else if err != nil {
 		return err
 	}
 	err = c.state.CompleteDiscoveredPuzzle(ctx, row.ID)
@@ -0,0 +1,196 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/emojihunt/emojihunt/state"
+	"golang.org/x/net/html"
+	"golang.org/x/xerrors"
+)
+
+// HTMLScraper reads the puzzle list from a web page using CSS selectors. See
+// state.DiscoveryConfig for how the selectors are used.
+type HTMLScraper struct {
+	groupMode          bool
+	groupSelector      cascadia.Selector
+	roundNameSelector  cascadia.Selector
+	puzzleListSelector cascadia.Selector
+	puzzleItemSelector cascadia.Selector
+}
+
+var _ Scraper = (*HTMLScraper)(nil)
+
+func NewHTMLScraper(config state.ScraperConfig) (*HTMLScraper, error) {
+	groupSelector, err := cascadia.Compile(config.GroupSelector)
+	if err != nil {
+		return nil, state.ValidationError{Field: "group_selector", Message: err.Error()}
+	}
+	roundNameSelector, err := cascadia.Compile(config.RoundNameSelector)
+	if err != nil {
+		return nil, state.ValidationError{Field: "round_name_selector", Message: err.Error()}
+	}
+	puzzleListSelector, err := cascadia.Compile(config.PuzzleListSelector)
+	if err != nil {
+		return nil, state.ValidationError{Field: "puzzle_list_selector", Message: err.Error()}
+	}
+	itemSelector := config.PuzzleItemSelector
+	if itemSelector == "" {
+		itemSelector = "a"
+	}
+	puzzleItemSelector, err := cascadia.Compile(itemSelector)
+	if err != nil {
+		return nil, state.ValidationError{Field: "puzzle_item_selector", Message: err.Error()}
+	}
+	return &HTMLScraper{
+		groupMode:          config.GroupMode,
+		groupSelector:      groupSelector,
+		roundNameSelector:  roundNameSelector,
+		puzzleListSelector: puzzleListSelector,
+		puzzleItemSelector: puzzleItemSelector,
+	}, nil
+}
+
+func (s *HTMLScraper) NewRequest(ctx context.Context, target *url.URL) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, "GET", target.String(), nil)
+}
+
+// TODO: make this an option
+var roundSuffix = regexp.MustCompile(`\s+\(\d+\)$`)
+
+func (s *HTMLScraper) Parse(r io.Reader, target *url.URL) ([]state.ScrapedPuzzle, error) {
+	// Parse round structure
+	var discovered [][2]*html.Node
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.groupMode {
+		groups := s.groupSelector.MatchAll(root)
+		if len(groups) == 0 {
+			return nil, xerrors.Errorf("no groups found")
+		}
+
+		for _, group := range groups {
+			nameNode := s.roundNameSelector.MatchFirst(group)
+			if nameNode == nil {
+				return nil, xerrors.Errorf("round name node not found in group: %#v", group)
+			}
+
+			puzzleListNode := s.puzzleListSelector.MatchFirst(group)
+			if puzzleListNode == nil {
+				// TODO: option for whether to allow empty rounds?
+				// return nil, xerrors.Errorf("puzzle list node not found in group: %#v", group)
+			}
+			discovered = append(discovered, [2]*html.Node{nameNode, puzzleListNode})
+		}
+	} else {
+		container := s.groupSelector.MatchFirst(root)
+		if container == nil {
+			return nil, xerrors.Errorf("container not found, did login succeed?")
+		}
+
+		node := container.FirstChild
+		for {
+			if s.roundNameSelector.Match(node) {
+				nameNode := node
+
+				node = node.NextSibling
+				for node != nil && node.Type == html.TextNode {
+					// Skip over text nodes.
+					node = node.NextSibling
+				}
+				if node == nil {
+					// Nothing found, abort.
+					return nil, xerrors.Errorf("nil puzzle table")
+				} else if s.puzzleListSelector.Match(node) {
+					// Puzzle list found!
+					discovered = append(discovered, [2]*html.Node{nameNode, node})
+				} else if s.roundNameSelector.Match(node) {
+					// Another round heading! This is probably a sub-round;
+					// start over treating the new heading as the round name.
+					continue
+				} else {
+					// Unknown structure, abort.
+					return nil, xerrors.Errorf("puzzle table not found, got: %#v", node)
+				}
+			}
+
+			// Advance to next node.
+			node = node.NextSibling
+			if node == nil {
+				break
+			}
+		}
+
+		if len(discovered) == 0 {
+			return nil, xerrors.Errorf("no rounds found in container: %#v", container)
+		}
+	}
+
+	// Parse out individual puzzles
+	var puzzles []state.ScrapedPuzzle
+	for _, pair := range discovered {
+		nameNode, puzzleListNode := pair[0], pair[1]
+		var roundBuf bytes.Buffer
+		collectText(nameNode, &roundBuf)
+		roundName := strings.TrimSpace(roundBuf.String())
+		// trim (8) puzzle count
+		roundName = roundSuffix.ReplaceAllString(roundName, "")
+		roundName = strings.Title(roundName)
+
+		if puzzleListNode == nil {
+			continue
+		}
+		puzzleItemNodes := s.puzzleItemSelector.MatchAll(puzzleListNode)
+		// if len(puzzleItemNodes) == 0 {
+		// 	return nil, xerrors.Errorf("no puzzle item nodes found in puzzle list: %#v", puzzleListNode)
+		// }
+		for _, item := range puzzleItemNodes {
+			var puzzleBuf bytes.Buffer
+			collectText(item, &puzzleBuf)
+
+			var u *url.URL
+			for _, attr := range item.Attr {
+				if attr.Key == "href" {
+					u, err = url.Parse(attr.Val)
+					if err != nil {
+						return nil, xerrors.Errorf("invalid puzzle url: %#v", u)
+					}
+				}
+			}
+			if u == nil {
+				return nil, xerrors.Errorf("could not find puzzle url for puzzle: %#v", item)
+			}
+
+			url := target.ResolveReference(u).String()
+			puzzles = append(puzzles, state.ScrapedPuzzle{
+				Name:      strings.TrimSpace(puzzleBuf.String()),
+				RoundName: roundName,
+				PuzzleURL: url,
+			})
+		}
+	}
+	return puzzles, nil
+}
+
+func collectText(n *html.Node, buf *bytes.Buffer) bool {
+	// https://stackoverflow.com/a/18275336
+	if n.Type == html.TextNode && len(n.Data) > 0 {
+		buf.WriteString(n.Data)
+		return true
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if collectText(c, buf) {
+			return true
+		}
+	}
+	return false
+}
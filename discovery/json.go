@@ -0,0 +1,170 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/emojihunt/emojihunt/jsonpath"
+	"github.com/emojihunt/emojihunt/state"
+	"golang.org/x/xerrors"
+)
+
+// JSONScraper reads the puzzle list from a JSON API using JSONPath
+// expressions. It can also send a GraphQL query. See state.DiscoveryConfig for
+// how the expressions are used.
+type JSONScraper struct {
+	roundsPath     jsonpath.Path // optional
+	roundNamePath  jsonpath.Path
+	puzzlesPath    jsonpath.Path
+	puzzleNamePath jsonpath.Path
+	puzzleURLPath  jsonpath.Path
+
+	graphQLQuery string
+}
+
+var _ Scraper = (*JSONScraper)(nil)
+
+func NewJSONScraper(config state.ScraperConfig) (*JSONScraper, error) {
+	var scraper = JSONScraper{graphQLQuery: config.GraphQLQuery}
+	var paths = []struct {
+		field, expr string
+		path        *jsonpath.Path
+	}{
+		{"rounds_path", config.RoundsPath, &scraper.roundsPath},
+		{"round_name_path", config.RoundNamePath, &scraper.roundNamePath},
+		{"puzzles_path", config.PuzzlesPath, &scraper.puzzlesPath},
+		{"puzzle_name_path", config.PuzzleNamePath, &scraper.puzzleNamePath},
+		{"puzzle_url_path", config.PuzzleURLPath, &scraper.puzzleURLPath},
+	}
+	for _, item := range paths {
+		if item.expr == "" && item.field == "rounds_path" {
+			continue // optional
+		}
+		path, err := jsonpath.Compile(item.expr)
+		if err != nil {
+			return nil, state.ValidationError{Field: item.field, Message: err.Error()}
+		}
+		*item.path = path
+	}
+	return &scraper, nil
+}
+
+func (s *JSONScraper) NewRequest(ctx context.Context, target *url.URL) (*http.Request, error) {
+	if s.graphQLQuery == "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", target.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		return req, nil
+	}
+	data, err := json.Marshal(map[string]string{"query": s.graphQLQuery})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", target.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func (s *JSONScraper) Parse(r io.Reader, target *url.URL) ([]state.ScrapedPuzzle, error) {
+	var root any
+	if err := json.NewDecoder(r).Decode(&root); err != nil {
+		return nil, xerrors.Errorf("invalid JSON: %w", err)
+	}
+
+	var puzzles []state.ScrapedPuzzle
+	if s.roundsPath == nil {
+		// Each puzzle names its own round
+		for _, item := range s.puzzlesPath.Eval(root) {
+			roundName, err := s.text(item, s.roundNamePath, "round name")
+			if err != nil {
+				return nil, err
+			}
+			puzzle, err := s.parsePuzzle(item, roundName, target)
+			if err != nil {
+				return nil, err
+			}
+			puzzles = append(puzzles, puzzle)
+		}
+	} else {
+		rounds := s.roundsPath.Eval(root)
+		if len(rounds) == 0 {
+			return nil, xerrors.Errorf("no rounds found")
+		}
+		for _, round := range rounds {
+			roundName, err := s.text(round, s.roundNamePath, "round name")
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range s.puzzlesPath.Eval(round) {
+				puzzle, err := s.parsePuzzle(item, roundName, target)
+				if err != nil {
+					return nil, err
+				}
+				puzzles = append(puzzles, puzzle)
+			}
+		}
+	}
+	if len(puzzles) == 0 {
+		return nil, xerrors.Errorf("no puzzles found")
+	}
+	return puzzles, nil
+}
+
+func (s *JSONScraper) parsePuzzle(item any, roundName string, target *url.URL) (state.ScrapedPuzzle, error) {
+	name, err := s.text(item, s.puzzleNamePath, "puzzle name")
+	if err != nil {
+		return state.ScrapedPuzzle{}, err
+	}
+	raw, err := s.text(item, s.puzzleURLPath, "puzzle url")
+	if err != nil {
+		return state.ScrapedPuzzle{}, err
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return state.ScrapedPuzzle{}, xerrors.Errorf("invalid puzzle url: %q", raw)
+	}
+	return state.ScrapedPuzzle{
+		Name:      name,
+		RoundName: roundName,
+		PuzzleURL: target.ResolveReference(u).String(),
+	}, nil
+}
+
+// Evaluates the path and returns the first match as a string. Numbers are
+// allowed too, since some hunts identify puzzles by number.
+func (s *JSONScraper) text(value any, path jsonpath.Path, what string) (string, error) {
+	var matches = path.Eval(value)
+	if len(matches) == 0 {
+		return "", xerrors.Errorf("%s not found in %s", what, abbreviate(value))
+	}
+	switch v := matches[0].(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			return v, nil
+		}
+	case float64:
+		return fmt.Sprint(v), nil
+	}
+	return "", xerrors.Errorf("%s is not a string: %s", what, abbreviate(matches[0]))
+}
+
+// Formats a JSON value for an error message.
+func abbreviate(value any) string {
+	data, _ := json.Marshal(value)
+	if len(data) > 200 {
+		return string(data[:200]) + "..."
+	}
+	return string(data)
+}
@@ -1,38 +1,28 @@
 package discovery
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
-	"regexp"
-	"strings"
 	"time"
 
-	"github.com/andybalholm/cascadia"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/emojihunt/emojihunt/state"
 	"github.com/getsentry/sentry-go"
-	"golang.org/x/net/html"
 	"golang.org/x/net/websocket"
 	"golang.org/x/time/rate"
 	"golang.org/x/xerrors"
 )
 
 type Poller struct {
-	puzzlesURL *url.URL
-	cookie     *http.Cookie
-
-	groupMode          bool
-	groupSelector      cascadia.Selector
-	roundNameSelector  cascadia.Selector
-	puzzleListSelector cascadia.Selector
-	puzzleItemSelector cascadia.Selector
+	sources []source // the puzzle list comes first
+	cookie  *http.Cookie
 
 	wsURL     *url.URL
 	wsToken   string
@@ -61,40 +51,52 @@ func NewPoller(config state.DiscoveryConfig) (*Poller, error) {
 		}
 	}
 
-	groupSelector, err := cascadia.Compile(config.GroupSelector)
-	if err != nil {
-		return nil, state.ValidationError{Field: "group_selector", Message: err.Error()}
-	}
-	roundNameSelector, err := cascadia.Compile(config.RoundNameSelector)
-	if err != nil {
-		return nil, state.ValidationError{Field: "round_name_selector", Message: err.Error()}
-	}
-	puzzleListSelector, err := cascadia.Compile(config.PuzzleListSelector)
+	scraper, err := NewScraper(config.ScraperConfig)
 	if err != nil {
-		return nil, state.ValidationError{Field: "puzzle_name_selector", Message: err.Error()}
+		return nil, err
 	}
-	itemSelector := config.PuzzleItemSelector
-	if itemSelector == "" {
-		itemSelector = "a"
+	var cookie = &http.Cookie{
+		Name:   config.CookieName,
+		Value:  config.CookieValue,
+		MaxAge: 0,
 	}
-	puzzleItemSelector, err := cascadia.Compile(config.PuzzleItemSelector)
-	if err != nil {
-		return nil, state.ValidationError{Field: "puzzle_item_selector", Message: err.Error()}
+	var sources = []source{{url: puzzlesURL, cookie: cookie, scraper: scraper}}
+
+	for i, item := range config.Sources {
+		var src = source{
+			cookie:     cookie,
+			scraper:    scraper,
+			namePrefix: item.NamePrefix,
+			roundName:  item.RoundName,
+		}
+		var prefix = fmt.Sprintf("sources[%d].", i)
+		src.url, err = url.Parse(item.URL)
+		if err != nil {
+			return nil, state.ValidationError{Field: prefix + "url", Message: err.Error()}
+		}
+		if item.CookieName != "" {
+			src.cookie = &http.Cookie{
+				Name:   item.CookieName,
+				Value:  item.CookieValue,
+				MaxAge: 0,
+			}
+		}
+		if item.ScraperConfig != (state.ScraperConfig{}) {
+			src.scraper, err = NewScraper(item.ScraperConfig)
+			var ve state.ValidationError
+			if errors.As(err, &ve) {
+				ve.Field = prefix + ve.Field
+				return nil, ve
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		sources = append(sources, src)
 	}
 
 	return &Poller{
-		puzzlesURL: puzzlesURL,
-		cookie: &http.Cookie{
-			Name:   config.CookieName,
-			Value:  config.CookieValue,
-			MaxAge: 0,
-		},
-
-		groupMode:          config.GroupMode,
-		groupSelector:      groupSelector,
-		roundNameSelector:  roundNameSelector,
-		puzzleListSelector: puzzleListSelector,
-		puzzleItemSelector: puzzleItemSelector,
+		sources: sources,
+		cookie:  cookie,
 
 		wsURL:     wsURL,
 		wsToken:   config.WebsocketToken,
@@ -102,7 +104,16 @@ func NewPoller(config state.DiscoveryConfig) (*Poller, error) {
 	}, nil
 }
 
-func (p *Poller) Poll(ctx context.Context, r chan []state.ScrapedPuzzle) error {
+// A ScrapeResult is the outcome of one pass over the discovery sources.
+type ScrapeResult struct {
+	Puzzles []state.ScrapedPuzzle
+
+	// Set if every source was scraped successfully, i.e. a puzzle that's missing
+	// from the results isn't on the hunt site.
+	Complete bool
+}
+
+func (p *Poller) Poll(ctx context.Context, r chan ScrapeResult) error {
 	hub := sentry.CurrentHub().Clone()
 	hub.ConfigureScope(func(scope *sentry.Scope) {
 		scope.SetTag("task", "discovery.poll")
@@ -120,10 +131,13 @@ reconnect:
 
 		for {
 			subctx, cancel := context.WithTimeout(ctx, pollTimeout)
-			if puzzles, err := p.Scrape(subctx); err != nil {
+			puzzles, err := p.Scrape(subctx)
+			for _, err := range Unjoin(err) {
+				// Report each failed source separately
 				sentry.GetHubFromContext(ctx).CaptureException(err)
-			} else {
-				r <- puzzles
+			}
+			if len(puzzles) > 0 {
+				r <- ScrapeResult{puzzles, err == nil}
 			}
 			cancel()
 
@@ -140,29 +154,47 @@ reconnect:
 	}
 }
 
-// TODO: make this an option
-var roundSuffix = regexp.MustCompile(`\s+\(\d+\)$`)
+// A source is a page to scrape for puzzles, along with how to read it.
+type source struct {
+	url        *url.URL
+	cookie     *http.Cookie
+	scraper    Scraper
+	namePrefix string
+	roundName  string // optional override
+}
 
-func (p *Poller) Scrape(ctx context.Context) (puz []state.ScrapedPuzzle, err error) {
-	puz, err = p.scrapeURL(ctx, p.puzzlesURL, "")
-	if err != nil {
-		return nil, err
-	}
-	tasksURL, err := url.Parse("https://puzzmon.world/research_tasks")
-	if err != nil {
-		log.Printf("discovery: failed to parse tasks URL: %v", err)
-		return puz, nil
-	}
-	puz2, err := p.scrapeURL(ctx, tasksURL, "[Task] ")
-	if err != nil {
-		log.Printf("discovery: failed to discover tasks: %v", err)
-		return puz, nil
+// A SourceError is a failure to scrape one of the discovery sources.
+type SourceError struct {
+	URL string
+	Err error
+}
+
+func (e SourceError) Error() string {
+	return fmt.Sprintf("%s: %v", e.URL, e.Err)
+}
+
+func (e SourceError) Unwrap() error {
+	return e.Err
+}
+
+// Scrape fetches and merges the puzzles from all of the sources. If any of the
+// sources fail, it returns the puzzles from the rest along with a SourceError
+// for each failure (combined with errors.Join).
+func (p *Poller) Scrape(ctx context.Context) ([]state.ScrapedPuzzle, error) {
+	var puzzles []state.ScrapedPuzzle
+	var errs []error
+	for _, src := range p.sources {
+		scraped, err := p.scrapeSource(ctx, src)
+		if err != nil {
+			errs = append(errs, SourceError{src.url.String(), err})
+			continue
+		}
+		puzzles = append(puzzles, scraped...)
 	}
-	puz = append(puz, puz2...)
-	return puz, nil
+	return puzzles, errors.Join(errs...)
 }
 
-func (p *Poller) scrapeURL(ctx context.Context, targetURL *url.URL, namePrefix string) (puz []state.ScrapedPuzzle, err error) {
+func (p *Poller) scrapeSource(ctx context.Context, src source) (puz []state.ScrapedPuzzle, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = xerrors.Errorf("panic: %w", r)
@@ -170,130 +202,32 @@ func (p *Poller) scrapeURL(ctx context.Context, targetURL *url.URL, namePrefix s
 	}()
 
 	// Download
-	log.Printf("discovery: scraping %q", targetURL.String())
-	req, err := http.NewRequestWithContext(ctx, "GET", targetURL.String(), nil)
+	log.Printf("discovery: scraping %q", src.url.String())
+	req, err := src.scraper.NewRequest(ctx, src.url)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(p.cookie)
+	if src.cookie.Name != "" {
+		req.AddCookie(src.cookie)
+	}
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
-	} else if res.StatusCode != http.StatusOK {
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
 		return nil, xerrors.Errorf("failed to fetch puzzle list: status code %v", res.Status)
 	}
 
-	// Parse round structure
-	var discovered [][2]*html.Node
-	root, err := html.Parse(res.Body)
+	// Parse
+	puzzles, err := src.scraper.Parse(res.Body, src.url)
 	if err != nil {
 		return nil, err
 	}
-
-	if p.groupMode {
-		groups := p.groupSelector.MatchAll(root)
-		if len(groups) == 0 {
-			return nil, xerrors.Errorf("no groups found")
-		}
-
-		for _, group := range groups {
-			nameNode := p.roundNameSelector.MatchFirst(group)
-			if nameNode == nil {
-				return nil, xerrors.Errorf("round name node not found in group: %#v", group)
-			}
-
-			puzzleListNode := p.puzzleListSelector.MatchFirst(group)
-			if puzzleListNode == nil {
-				// TODO: option for whether to allow empty rounds?
-				// return nil, xerrors.Errorf("puzzle list node not found in group: %#v", group)
-			}
-			discovered = append(discovered, [2]*html.Node{nameNode, puzzleListNode})
-		}
-	} else {
-		container := p.groupSelector.MatchFirst(root)
-		if container == nil {
-			return nil, xerrors.Errorf("container not found, did login succeed?")
-		}
-
-		node := container.FirstChild
-		for {
-			if p.roundNameSelector.Match(node) {
-				nameNode := node
-
-				node = node.NextSibling
-				for node != nil && node.Type == html.TextNode {
-					// Skip over text nodes.
-					node = node.NextSibling
-				}
-				if node == nil {
-					// Nothing found, abort.
-					return nil, xerrors.Errorf("nil puzzle table")
-				} else if p.puzzleListSelector.Match(node) {
-					// Puzzle list found!
-					discovered = append(discovered, [2]*html.Node{nameNode, node})
-				} else if p.roundNameSelector.Match(node) {
-					// Another round heading! This is probably a sub-round;
-					// start over treating the new heading as the round name.
-					continue
-				} else {
-					// Unknown structure, abort.
-					return nil, xerrors.Errorf("puzzle table not found, got: %#v", node)
-				}
-			}
-
-			// Advance to next node.
-			node = node.NextSibling
-			if node == nil {
-				break
-			}
-		}
-
-		if len(discovered) == 0 {
-			return nil, xerrors.Errorf("no rounds found in container: %#v", container)
-		}
-	}
-
-	// Parse out individual puzzles
-	var puzzles []state.ScrapedPuzzle
-	for _, pair := range discovered {
-		nameNode, puzzleListNode := pair[0], pair[1]
-		var roundBuf bytes.Buffer
-		collectText(nameNode, &roundBuf)
-		roundName := strings.TrimSpace(roundBuf.String())
-		// trim (8) puzzle count
-		roundName = roundSuffix.ReplaceAllString(roundName, "")
-		roundName = strings.Title(roundName)
-
-		if puzzleListNode == nil {
-			continue
-		}
-		puzzleItemNodes := p.puzzleItemSelector.MatchAll(puzzleListNode)
-		// if len(puzzleItemNodes) == 0 {
-		// 	return nil, xerrors.Errorf("no puzzle item nodes found in puzzle list: %#v", puzzleListNode)
-		// }
-		for _, item := range puzzleItemNodes {
-			var puzzleBuf bytes.Buffer
-			collectText(item, &puzzleBuf)
-
-			var u *url.URL
-			for _, attr := range item.Attr {
-				if attr.Key == "href" {
-					u, err = url.Parse(attr.Val)
-					if err != nil {
-						return nil, xerrors.Errorf("invalid puzzle url: %#v", u)
-					}
-				}
-			}
-			if u == nil {
-				return nil, xerrors.Errorf("could not find puzzle url for puzzle: %#v", item)
-			}
-
-			url := targetURL.ResolveReference(u).String()
-			puzzles = append(puzzles, state.ScrapedPuzzle{
-				Name:      namePrefix + strings.TrimSpace(puzzleBuf.String()),
-				RoundName: roundName,
-				PuzzleURL: url,
-			})
+	for i := range puzzles {
+		puzzles[i].Name = src.namePrefix + puzzles[i].Name
+		if src.roundName != "" {
+			puzzles[i].RoundName = src.roundName
 		}
 	}
 	return puzzles, nil
@@ -369,16 +303,13 @@ func (p *Poller) OpenWebsocket(ctx context.Context) (*websocket.Conn, chan bool,
 	return ws, ch, nil
 }
 
-func collectText(n *html.Node, buf *bytes.Buffer) bool {
-	// https://stackoverflow.com/a/18275336
-	if n.Type == html.TextNode && len(n.Data) > 0 {
-		buf.WriteString(n.Data)
-		return true
-	}
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		if collectText(c, buf) {
-			return true
-		}
+// Unjoin splits up an error created with errors.Join, such as the one
+// returned by Scrape. A nil error becomes an empty list.
+func Unjoin(err error) []error {
+	if err == nil {
+		return nil
+	} else if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
 	}
-	return false
+	return []error{err}
 }
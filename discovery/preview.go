@@ -0,0 +1,143 @@
+package discovery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/emojihunt/emojihunt/state/db"
+)
+
+// What SyncWorker does with a scraped puzzle
+type scrapedAction int
+
+const (
+	actionIgnore   scrapedAction = iota // already created or discovered
+	actionCreate                        // the round exists, so create the puzzle
+	actionWait                          // the round is waiting for QM approval (or ignored)
+	actionNewRound                      // the round is new, so ask the QMs about it
+	actionReopen                        // the round was approved but has since been deleted
+)
+
+// classifyScrapedPuzzle decides what to do with a scraped puzzle. It also
+// returns the round to create the puzzle in, for actionCreate, and the
+// discovered round, for actionWait and actionReopen.
+func classifyScrapedPuzzle(ctx context.Context, s *state.Client,
+	record state.ScrapedPuzzle) (scrapedAction, state.Round, db.DiscoveredRound, error) {
+
+	created, err := s.IsPuzzleCreated(ctx, record)
+	if err != nil {
+		return 0, state.Round{}, db.DiscoveredRound{}, err
+	} else if created {
+		return actionIgnore, state.Round{}, db.DiscoveredRound{}, nil
+	}
+	discovered, err := s.IsPuzzleDiscovered(ctx, record)
+	if err != nil {
+		return 0, state.Round{}, db.DiscoveredRound{}, err
+	} else if discovered {
+		return actionIgnore, state.Round{}, db.DiscoveredRound{}, nil
+	}
+
+	round, err := s.GetCreatedRound(ctx, record.RoundName)
+	if err == nil {
+		return actionCreate, round, db.DiscoveredRound{}, nil
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return 0, state.Round{}, db.DiscoveredRound{}, err
+	}
+	pending, err := s.GetDiscoveredRound(ctx, record.RoundName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return actionNewRound, state.Round{}, db.DiscoveredRound{}, nil
+	} else if err != nil {
+		return 0, state.Round{}, db.DiscoveredRound{}, err
+	} else if pending.CreatedAs != 0 {
+		// The QMs approved the round under another name, or merged it
+		round, err := s.GetRound(ctx, pending.CreatedAs)
+		if errors.Is(err, sql.ErrNoRows) {
+			// ...and then deleted it, so the round needs re-creating
+			return actionReopen, state.Round{}, pending, nil
+		} else if err != nil {
+			return 0, state.Round{}, db.DiscoveredRound{}, err
+		}
+		return actionCreate, round, db.DiscoveredRound{}, nil
+	}
+	return actionWait, state.Round{}, pending, nil
+}
+
+// A Preview is what SyncWorker would do with a scrape, given the current
+// state of the database.
+type Preview struct {
+	// Puzzles that would be created right away, in rounds that already exist
+	Create []state.ScrapedPuzzle `json:"create"`
+
+	// Rounds that need QM approval before their puzzles can be created
+	Rounds []PreviewRound `json:"rounds"`
+
+	// Puzzles that have already been created or discovered, or that are listed
+	// more than once
+	Duplicates []state.ScrapedPuzzle `json:"duplicates"`
+}
+
+type PreviewRound struct {
+	Name string `json:"name"`
+
+	// Set if the round has already been posted to #qm, otherwise it's new
+	Pending bool `json:"pending"`
+
+	// Set if the QMs chose not to create the round
+	Ignored bool `json:"ignored"`
+
+	// Set if the QMs approved the round but it has since been deleted, so it'll
+	// be posted to #qm again
+	Recreate bool                  `json:"recreate"`
+	Puzzles  []state.ScrapedPuzzle `json:"puzzles"`
+}
+
+// PreviewScrape works out what SyncWorker would do with the scraped puzzles,
+// without writing to the database. Puzzles are handled in order, as they are
+// by SyncWorker, so a puzzle that's listed twice is a duplicate the second time.
+func PreviewScrape(ctx context.Context, s *state.Client,
+	puzzles []state.ScrapedPuzzle) (Preview, error) {
+
+	var preview Preview
+	var names, urls = make(map[string]bool), make(map[string]bool)
+	var rounds = make(map[string]int) // index in preview.Rounds, by name
+	for _, record := range puzzles {
+		// The database won't see the puzzles we've already handled, so check
+		// them here (with the same case-sensitivity as the queries)
+		if names[record.Name] || urls[strings.ToLower(record.PuzzleURL)] {
+			preview.Duplicates = append(preview.Duplicates, record)
+			continue
+		}
+		action, _, pending, err := classifyScrapedPuzzle(ctx, s, record)
+		if err != nil {
+			return Preview{}, err
+		}
+		if action != actionIgnore {
+			names[record.Name] = true
+			urls[strings.ToLower(record.PuzzleURL)] = true
+		}
+
+		switch action {
+		case actionIgnore:
+			preview.Duplicates = append(preview.Duplicates, record)
+		case actionCreate:
+			preview.Create = append(preview.Create, record)
+		case actionWait, actionNewRound, actionReopen:
+			var key = strings.ToLower(record.RoundName)
+			if _, ok := rounds[key]; !ok {
+				rounds[key] = len(preview.Rounds)
+				preview.Rounds = append(preview.Rounds, PreviewRound{
+					Name:     record.RoundName,
+					Pending:  action == actionWait,
+					Ignored:  pending.Ignored,
+					Recreate: action == actionReopen,
+				})
+			}
+			var round = &preview.Rounds[rounds[key]]
+			round.Puzzles = append(round.Puzzles, record)
+		}
+	}
+	return preview, nil
+}
@@ -0,0 +1,194 @@
+package discovery
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/emojihunt/emojihunt/state/db"
+)
+
+// Sets up a hunt on the in-memory store with rounds and discovered rounds in
+// each state SyncWorker cares about.
+func newTestDiscovery(t *testing.T) (context.Context, *state.Client, map[string]state.Round) {
+	t.Helper()
+	var ctx = state.WithActor(context.Background(), state.ActorDiscovery)
+	var s = state.NewMemory(ctx)
+
+	var rounds = make(map[string]state.Round)
+	for _, round := range []state.Round{
+		{Name: "Fruit", Emoji: "🍎"},
+		{Name: "Nut Mix", Emoji: "🥜"},
+		{Name: "Berries", Emoji: "🍓"},
+	} {
+		round.DriveFolder = "folder"
+		created, _, err := s.CreateRound(ctx, round)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rounds[round.Name] = created
+	}
+	if _, _, err := s.CreatePuzzle(ctx, state.RawPuzzle{
+		Name: "Apples", Round: rounds["Fruit"].ID, PuzzleURL: "https://example.com/apples",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var discover = func(name string) db.DiscoveredRound {
+		t.Helper()
+		if _, err := s.CreateDiscoveredRound(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+		round, err := s.GetDiscoveredRound(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return round
+	}
+
+	// Waiting for approval, with a puzzle already queued
+	var vegetables = discover("Vegetables")
+	if err := s.CreateDiscoveredPuzzle(ctx, db.CreateDiscoveredPuzzleParams{
+		Name: "Carrots", PuzzleURL: "https://example.com/carrots",
+		DiscoveredRound: sql.NullInt64{Int64: vegetables.ID, Valid: true},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ignored by the QMs
+	var grains = discover("Grains")
+	grains.Ignored = true
+	if err := s.UpdateDiscoveredRound(ctx, grains); err != nil {
+		t.Fatal(err)
+	}
+
+	// Approved under another name
+	if err := s.CompleteDiscoveredRound(ctx, discover("Nuts").ID, rounds["Nut Mix"]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Approved, then deleted
+	if err := s.CompleteDiscoveredRound(ctx, discover("Berries").ID, rounds["Berries"]); err != nil {
+		t.Fatal(err)
+	} else if _, err := s.DeleteRound(ctx, rounds["Berries"].ID); err != nil {
+		t.Fatal(err)
+	}
+	return ctx, s, rounds
+}
+
+func TestClassifyScrapedPuzzle(t *testing.T) {
+	ctx, s, rounds := newTestDiscovery(t)
+	for _, tc := range []struct {
+		name    string
+		record  state.ScrapedPuzzle
+		action  scrapedAction
+		round   string // for actionCreate
+		pending string // for actionWait and actionReopen
+	}{
+		{
+			name:   "already created",
+			record: state.ScrapedPuzzle{Name: "Apples", RoundName: "Fruit", PuzzleURL: "https://example.com/apples"},
+			action: actionIgnore,
+		},
+		{
+			name:   "created under another url",
+			record: state.ScrapedPuzzle{Name: "Apples", RoundName: "Fruit", PuzzleURL: "https://example.com/apples2"},
+			action: actionIgnore,
+		},
+		{
+			name:   "already discovered",
+			record: state.ScrapedPuzzle{Name: "Carrots", RoundName: "Vegetables", PuzzleURL: "https://example.com/carrots"},
+			action: actionIgnore,
+		},
+		{
+			name:   "round exists",
+			record: state.ScrapedPuzzle{Name: "Bananas", RoundName: "Fruit", PuzzleURL: "https://example.com/bananas"},
+			action: actionCreate,
+			round:  "Fruit",
+		},
+		{
+			name:   "round approved under another name",
+			record: state.ScrapedPuzzle{Name: "Pecans", RoundName: "Nuts", PuzzleURL: "https://example.com/pecans"},
+			action: actionCreate,
+			round:  "Nut Mix",
+		},
+		{
+			name:    "round waiting for approval",
+			record:  state.ScrapedPuzzle{Name: "Peas", RoundName: "Vegetables", PuzzleURL: "https://example.com/peas"},
+			action:  actionWait,
+			pending: "Vegetables",
+		},
+		{
+			name:    "round ignored",
+			record:  state.ScrapedPuzzle{Name: "Oats", RoundName: "Grains", PuzzleURL: "https://example.com/oats"},
+			action:  actionWait,
+			pending: "Grains",
+		},
+		{
+			name:    "round deleted since approval",
+			record:  state.ScrapedPuzzle{Name: "Strawberries", RoundName: "Berries", PuzzleURL: "https://example.com/strawberries"},
+			action:  actionReopen,
+			pending: "Berries",
+		},
+		{
+			name:   "new round",
+			record: state.ScrapedPuzzle{Name: "Kale", RoundName: "Leafy Greens", PuzzleURL: "https://example.com/kale"},
+			action: actionNewRound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			action, round, pending, err := classifyScrapedPuzzle(ctx, s, tc.record)
+			if err != nil {
+				t.Fatal(err)
+			} else if action != tc.action {
+				t.Errorf("got action %d, want %d", action, tc.action)
+			}
+			if tc.round != "" && round.ID != rounds[tc.round].ID {
+				t.Errorf("got round %q, want %q", round.Name, tc.round)
+			}
+			if pending.Name != tc.pending {
+				t.Errorf("got discovered round %q, want %q", pending.Name, tc.pending)
+			}
+		})
+	}
+}
+
+func TestPreviewScrape(t *testing.T) {
+	ctx, s, _ := newTestDiscovery(t)
+	var (
+		apples   = state.ScrapedPuzzle{Name: "Apples", RoundName: "Fruit", PuzzleURL: "https://example.com/apples"}
+		bananas  = state.ScrapedPuzzle{Name: "Bananas", RoundName: "Fruit", PuzzleURL: "https://example.com/bananas"}
+		bananas2 = state.ScrapedPuzzle{Name: "Bananas", RoundName: "Fruit", PuzzleURL: "https://example.com/BANANAS"}
+		peas     = state.ScrapedPuzzle{Name: "Peas", RoundName: "Vegetables", PuzzleURL: "https://example.com/peas"}
+		beans    = state.ScrapedPuzzle{Name: "Beans", RoundName: "vegetables", PuzzleURL: "https://example.com/beans"}
+		oats     = state.ScrapedPuzzle{Name: "Oats", RoundName: "Grains", PuzzleURL: "https://example.com/oats"}
+		kale     = state.ScrapedPuzzle{Name: "Kale", RoundName: "Leafy Greens", PuzzleURL: "https://example.com/kale"}
+		figs     = state.ScrapedPuzzle{Name: "Figs", RoundName: "Berries", PuzzleURL: "https://example.com/figs"}
+	)
+	preview, err := PreviewScrape(ctx, s, []state.ScrapedPuzzle{
+		apples, bananas, peas, kale, bananas2, beans, oats, figs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want = Preview{
+		Create: []state.ScrapedPuzzle{bananas},
+		Rounds: []PreviewRound{
+			{Name: "Vegetables", Pending: true, Puzzles: []state.ScrapedPuzzle{peas, beans}},
+			{Name: "Leafy Greens", Puzzles: []state.ScrapedPuzzle{kale}},
+			{Name: "Grains", Pending: true, Ignored: true, Puzzles: []state.ScrapedPuzzle{oats}},
+			{Name: "Berries", Recreate: true, Puzzles: []state.ScrapedPuzzle{figs}},
+		},
+		Duplicates: []state.ScrapedPuzzle{apples, bananas2},
+	}
+	if !reflect.DeepEqual(preview, want) {
+		t.Errorf("got %+v\nwant %+v", preview, want)
+	}
+
+	// Previewing doesn't write anything
+	if _, err := s.GetDiscoveredRound(ctx, "Leafy Greens"); err == nil {
+		t.Error("PreviewScrape created a discovered round")
+	}
+}
@@ -0,0 +1,445 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/emojihunt/emojihunt/discord"
+	"github.com/emojihunt/emojihunt/emojiname"
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// Prefix for the components on new round notices in #qm
+	roundComponent = "discovery.round"
+
+	// Custom ID of the text input in the "Edit name" modal
+	roundNameField = "name"
+
+	// Discord allows at most 25 options in a select menu
+	selectMenuLimit = 25
+
+	roundPuzzleLimit = 15
+)
+
+// notifyRound posts a new round to #qm for approval.
+func (c *Client) notifyRound(ctx context.Context, round db.DiscoveredRound) error {
+	log.Printf("discovery: notifying #qm of new round %q", round.Name)
+	data, err := c.renderRound(ctx, round, "")
+	if err != nil {
+		return err
+	}
+	round.MessageID, err = c.discord.ChannelSendComplex(c.discord.QMChannel, &discordgo.MessageSend{
+		Content:    data.Content,
+		Embeds:     data.Embeds,
+		Components: data.Components,
+	})
+	if err != nil {
+		return err
+	}
+	round.NotifiedAt = time.Now()
+	return c.state.UpdateDiscoveredRound(ctx, round)
+}
+
+// renderRound builds the new round notice from the QMs' choices so far. Once
+// the round has been handled, pass a result to show instead of the components.
+func (c *Client) renderRound(ctx context.Context, round db.DiscoveredRound,
+	result string) (*discordgo.InteractionResponseData, error) {
+
+	puzzles, err := c.state.ListDiscoveredPuzzlesForRound(ctx, round.ID)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for i, puzzle := range puzzles {
+		if i == roundPuzzleLimit {
+			lines = append(lines, fmt.Sprintf("...and %d more", len(puzzles)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("[%s](%s)", puzzle.Name, puzzle.PuzzleURL))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "*No puzzles yet*")
+	}
+	if round.EditedName != "" {
+		lines = append([]string{fmt.Sprintf("*Listed on the hunt site as %q*\n", round.Name)}, lines...)
+	}
+
+	var emoji, hue = "*not picked yet*", emojiname.EmojiHue(round.Emoji)
+	if round.Emoji != "" {
+		emoji = round.Emoji
+	}
+	var embed = &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("❓ New Round: %s", roundDisplayName(round)),
+		Description: strings.Join(lines, "\n"),
+		Color:       hueColor(hue),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Emoji", Value: emoji, Inline: true},
+			{Name: "Hue", Value: fmt.Sprintf("%d°", hue), Inline: true},
+		},
+	}
+	if result != "" {
+		return &discordgo.InteractionResponseData{
+			Content:    result,
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		}, nil
+	}
+
+	rounds, err := c.state.ListRounds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var used []string
+	for _, other := range rounds {
+		used = append(used, other.Emoji)
+	}
+	suggestions, err := emojiname.SuggestEmoji(roundDisplayName(round), used, selectMenuLimit)
+	if err != nil {
+		return nil, err
+	}
+	var options []discordgo.SelectMenuOption
+	for _, suggestion := range suggestions {
+		var e = suggestion.String()
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       suggestion.ShortName,
+			Value:       e,
+			Description: fmt.Sprintf("hue %d°", emojiname.EmojiHue(e)),
+			Emoji:       &discordgo.ComponentEmoji{Name: e},
+			Default:     e == round.Emoji,
+		})
+	}
+	if round.Emoji != "" && !slices.ContainsFunc(options, func(o discordgo.SelectMenuOption) bool {
+		return o.Value == round.Emoji
+	}) {
+		// A QM can also pick an emoji by reacting to the message
+		options = append([]discordgo.SelectMenuOption{{
+			Label:   round.Emoji,
+			Value:   round.Emoji,
+			Emoji:   &discordgo.ComponentEmoji{Name: round.Emoji},
+			Default: true,
+		}}, options[:min(len(options), selectMenuLimit-1)]...)
+	}
+
+	var id = strconv.FormatInt(round.ID, 10)
+	return &discordgo.InteractionResponseData{
+		Content: fmt.Sprintf("%s please pick an emoji and approve this round, or merge it "+
+			"into an existing one.\nReminder: use `/qm discovery pause` to stop the bot.",
+			c.discord.QMRole.Mention()),
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    discord.ComponentID(roundComponent, "emoji", id),
+					Placeholder: "Pick an emoji",
+					Options:     options,
+				},
+			}},
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Approve",
+					Style:    discordgo.SuccessButton,
+					CustomID: discord.ComponentID(roundComponent, "approve", id),
+				},
+				discordgo.Button{
+					Label:    "Edit name",
+					Style:    discordgo.PrimaryButton,
+					CustomID: discord.ComponentID(roundComponent, "edit", id),
+				},
+				discordgo.Button{
+					Label:    "Merge into existing round",
+					Style:    discordgo.SecondaryButton,
+					CustomID: discord.ComponentID(roundComponent, "merge", id),
+				},
+				discordgo.Button{
+					Label:    "Ignore",
+					Style:    discordgo.DangerButton,
+					CustomID: discord.ComponentID(roundComponent, "ignore", id),
+				},
+			}},
+		},
+	}, nil
+}
+
+// handleRoundComponent handles the components on a new round notice, and the
+// "Edit name" modal. The notice is re-rendered after each change.
+func (c *Client) handleRoundComponent(ctx context.Context,
+	input *discord.ComponentInput) (*discordgo.InteractionResponse, error) {
+	if len(input.Args) != 2 {
+		return nil, xerrors.Errorf("unexpected args: %#v", input.Args)
+	}
+	id, err := strconv.ParseInt(input.Args[1], 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid round ID: %w", err)
+	}
+
+	// Serialize changes to discovered rounds, so two QMs can't both create the
+	// same round
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	round, err := c.state.GetDiscoveredRoundByID(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if round.CreatedAs != 0 || round.Ignored {
+		return ephemeral("🤷 This round has already been handled."), nil
+	}
+
+	var result string
+	switch input.Args[0] {
+	case "emoji":
+		if len(input.Values) != 1 {
+			return nil, xerrors.Errorf("unexpected values: %#v", input.Values)
+		}
+		round.Emoji = input.Values[0]
+		if err := c.state.UpdateDiscoveredRound(ctx, round); err != nil {
+			return nil, err
+		}
+	case "edit":
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: discord.ComponentID(roundComponent, "rename", input.Args[1]),
+				Title:    "Edit round name",
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:  roundNameField,
+							Label:     "Name",
+							Style:     discordgo.TextInputShort,
+							Value:     roundDisplayName(round),
+							Required:  true,
+							MaxLength: 100,
+						},
+					}},
+				},
+			},
+		}, nil
+	case "rename":
+		var name = strings.TrimSpace(input.Fields[roundNameField])
+		if name == "" {
+			return ephemeral("✋ The round name can't be blank."), nil
+		} else if name == round.Name {
+			name = "" // back to the name on the hunt site
+		}
+		round.EditedName = name
+		if err := c.state.UpdateDiscoveredRound(ctx, round); err != nil {
+			return nil, err
+		}
+	case "merge":
+		return c.renderMerge(ctx, input, round)
+	case "cancel":
+		// re-render with the usual components
+	case "into":
+		if len(input.Values) != 1 {
+			return nil, xerrors.Errorf("unexpected values: %#v", input.Values)
+		}
+		target, err := strconv.ParseInt(input.Values[0], 10, 64)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid round ID: %w", err)
+		}
+		existing, err := c.state.GetRound(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("discovery: merging new round %q into %q", round.Name, existing.Name)
+		if err := c.state.CompleteDiscoveredRound(ctx, round.ID, existing); err != nil {
+			return nil, err
+		}
+		result = fmt.Sprintf("🔀 Merged into %s %s by %s. Its puzzles will be created there.",
+			existing.Emoji, existing.Name, input.User.Mention())
+	case "ignore":
+		round.Ignored = true
+		if err := c.state.UpdateDiscoveredRound(ctx, round); err != nil {
+			return nil, err
+		}
+		result = fmt.Sprintf("🙈 Ignored by %s. Its puzzles won't be created, "+
+			"but you can still add them by hand.", input.User.Mention())
+	case "approve":
+		if round.Emoji == "" {
+			round.Emoji, err = c.discord.GetTopReaction(c.discord.QMChannel, round.MessageID)
+			if err != nil {
+				return nil, err
+			} else if round.Emoji == "" {
+				return ephemeral("✋ Pick an emoji for the round first."), nil
+			}
+		}
+		// Check the round before deferring, so problems can be reported to the QM
+		var ve state.ValidationError
+		if err := c.validateRound(ctx, newRound(round)); errors.As(err, &ve) {
+			return ephemeral(fmt.Sprintf("✋ Can't create the round: %s.", ve.Error())), nil
+		} else if err != nil {
+			return nil, err
+		}
+		// Creating the Drive folder can take a while
+		if err := input.Defer(); err != nil {
+			return nil, err
+		}
+		created, err := c.createRound(ctx, round)
+		if err != nil {
+			return nil, err
+		}
+		result = fmt.Sprintf("✅ Created %s %s, approved by %s.",
+			created.Emoji, created.Name, input.User.Mention())
+	default:
+		return nil, xerrors.Errorf("unexpected action: %q", input.Args[0])
+	}
+
+	data, err := c.renderRound(ctx, round, result)
+	if err != nil {
+		return nil, err
+	}
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: data,
+	}, nil
+}
+
+// renderMerge replaces the notice's components with a menu of rounds to merge
+// the new round into.
+func (c *Client) renderMerge(ctx context.Context, input *discord.ComponentInput,
+	round db.DiscoveredRound) (*discordgo.InteractionResponse, error) {
+
+	rounds, err := c.state.ListRounds(ctx)
+	if err != nil {
+		return nil, err
+	} else if len(rounds) == 0 {
+		return ephemeral("🤷 There are no rounds to merge into."), nil
+	}
+	// Most likely the new round belongs with a recent one
+	rounds = rounds[max(0, len(rounds)-selectMenuLimit):]
+
+	var options []discordgo.SelectMenuOption
+	for _, existing := range rounds {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: existing.Name,
+			Value: strconv.FormatInt(existing.ID, 10),
+			Emoji: &discordgo.ComponentEmoji{Name: existing.Emoji},
+		})
+	}
+	var id = strconv.FormatInt(round.ID, 10)
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content: input.IC.Message.Content,
+			Embeds:  input.IC.Message.Embeds,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.SelectMenu{
+						MenuType:    discordgo.StringSelectMenu,
+						CustomID:    discord.ComponentID(roundComponent, "into", id),
+						Placeholder: "Merge into...",
+						Options:     options,
+					},
+				}},
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Cancel",
+						Style:    discordgo.SecondaryButton,
+						CustomID: discord.ComponentID(roundComponent, "cancel", id),
+					},
+				}},
+			},
+		},
+	}, nil
+}
+
+// createRound creates an approved round, with the hue that goes with its emoji.
+func (c *Client) createRound(ctx context.Context, round db.DiscoveredRound) (state.Round, error) {
+	var record = newRound(round)
+	log.Printf("discovery: creating round %q with emoji %q, hue %d",
+		record.Name, record.Emoji, record.Hue)
+	// Validate first: if CreateRound fails after the Drive folder has been
+	// created, the folder is left behind.
+	err := c.validateRound(ctx, record)
+	if err != nil {
+		return state.Round{}, err
+	}
+	record.DriveFolder, err = c.syncer.CreateDriveFolder(ctx, record)
+	if err != nil {
+		return state.Round{}, err
+	}
+	created, _, err := c.state.CreateRound(ctx, record)
+	if err != nil {
+		return state.Round{}, err
+	}
+	if err := c.state.UpdateDiscoveredRound(ctx, round); err != nil {
+		return state.Round{}, err // save the emoji, in case it came from a reaction
+	}
+	return created, c.state.CompleteDiscoveredRound(ctx, round.ID, created)
+}
+
+// validateRound runs the checks CreateRound will, except for the Drive folder,
+// which hasn't been created yet.
+func (c *Client) validateRound(ctx context.Context, record state.Round) error {
+	var check = record
+	check.DriveFolder = "(not yet created)"
+	if err := state.ValidateRound(check); err != nil {
+		return err
+	}
+	return c.state.ValidateRoundUnique(ctx, record)
+}
+
+func newRound(round db.DiscoveredRound) state.Round {
+	return state.Round{
+		Name:  roundDisplayName(round),
+		Emoji: round.Emoji,
+		Hue:   emojiname.EmojiHue(round.Emoji),
+	}
+}
+
+func roundDisplayName(round db.DiscoveredRound) string {
+	if round.EditedName != "" {
+		return round.EditedName
+	}
+	return round.Name
+}
+
+func ephemeral(msg string) *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: msg,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// hueColor converts a hue to an RGB color for the embed, matching the
+// oklch(72% 0.19 <hue>) the web UI uses for rounds.
+func hueColor(hue int64) int {
+	var h = float64(hue) * math.Pi / 180
+	var l, a, b = 0.72, 0.19 * math.Cos(h), 0.19 * math.Sin(h)
+
+	// OKLab to linear sRGB, from https://bottosson.github.io/posts/oklab/
+	var lc = math.Pow(l+0.3963377774*a+0.2158037573*b, 3)
+	var mc = math.Pow(l-0.1055613458*a-0.0638541728*b, 3)
+	var sc = math.Pow(l-0.0894841775*a-1.2914855480*b, 3)
+	var rgb = []float64{
+		+4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc,
+		-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc,
+		-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc,
+	}
+	var color int
+	for _, v := range rgb {
+		v = max(0, min(1, v)) // clip to the sRGB gamut
+		if v <= 0.0031308 {
+			v *= 12.92
+		} else {
+			v = 1.055*math.Pow(v, 1/2.4) - 0.055
+		}
+		color = color<<8 | int(math.Round(v*255))
+	}
+	return color
+}
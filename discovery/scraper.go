@@ -0,0 +1,32 @@
+package discovery
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/emojihunt/emojihunt/state"
+)
+
+// A Scraper knows how to fetch and read the puzzle list from one kind of hunt
+// website. The Poller handles the rest: authentication, retries, etc.
+type Scraper interface {
+	// NewRequest builds the request for the puzzle list at the given URL.
+	NewRequest(ctx context.Context, target *url.URL) (*http.Request, error)
+
+	// Parse reads the scraped puzzles from the response. Relative puzzle URLs
+	// are resolved against the target URL.
+	Parse(body io.Reader, target *url.URL) ([]state.ScrapedPuzzle, error)
+}
+
+func NewScraper(config state.ScraperConfig) (Scraper, error) {
+	switch config.Scraper {
+	case "", state.ScraperHTML:
+		return NewHTMLScraper(config)
+	case state.ScraperJSON:
+		return NewJSONScraper(config)
+	default:
+		return nil, state.ValidationError{Field: "scraper", Message: `must be "html" or "json"`}
+	}
+}
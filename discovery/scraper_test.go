@@ -0,0 +1,245 @@
+package discovery
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emojihunt/emojihunt/state"
+)
+
+var target, _ = url.Parse("https://example.com/hunt/puzzles")
+
+// Parses the fixture in testdata with a scraper built from the config.
+func parseFixture(t *testing.T, config state.ScraperConfig, name string) ([]state.ScrapedPuzzle, error) {
+	t.Helper()
+	scraper, err := NewScraper(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	return scraper.Parse(file, target)
+}
+
+func TestJSONScraper(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  state.ScraperConfig
+		fixture string
+		want    []state.ScrapedPuzzle
+	}{
+		{
+			name: "rounds",
+			config: state.ScraperConfig{
+				Scraper:        state.ScraperJSON,
+				RoundsPath:     "$.rounds[*]",
+				RoundNamePath:  "$.title",
+				PuzzlesPath:    "$.puzzles[*]",
+				PuzzleNamePath: "$.name",
+				PuzzleURLPath:  "$.url",
+			},
+			fixture: "rounds.json",
+			want: []state.ScrapedPuzzle{
+				{Name: "Apples", RoundName: "Fruit", PuzzleURL: "https://example.com/puzzles/apples"},
+				{Name: "Bananas", RoundName: "Fruit", PuzzleURL: "https://other.example.com/bananas"},
+				{Name: "17", RoundName: "Vegetables", PuzzleURL: "https://example.com/hunt/carrots"},
+			},
+		},
+		{
+			name: "puzzles",
+			config: state.ScraperConfig{
+				Scraper:        state.ScraperJSON,
+				RoundNamePath:  "round.name",
+				PuzzlesPath:    "$.data.puzzles[*]",
+				PuzzleNamePath: "$.title",
+				PuzzleURLPath:  "$['slug']",
+			},
+			fixture: "puzzles.json",
+			want: []state.ScrapedPuzzle{
+				{Name: "Apples", RoundName: "Fruit", PuzzleURL: "https://example.com/hunt/apples"},
+				{Name: "Bananas", RoundName: "Fruit", PuzzleURL: "https://example.com/hunt/bananas"},
+				{Name: "Carrots", RoundName: "Vegetables", PuzzleURL: "https://example.com/hunt/carrots"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseFixture(t, tc.config, tc.fixture)
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJSONScraperErrors(t *testing.T) {
+	var config = state.ScraperConfig{
+		Scraper:        state.ScraperJSON,
+		RoundsPath:     "$.rounds[*]",
+		RoundNamePath:  "$.title",
+		PuzzlesPath:    "$.puzzles[*]",
+		PuzzleNamePath: "$.name",
+		PuzzleURLPath:  "$.url",
+	}
+	for _, tc := range []struct {
+		name   string
+		mutate func(c *state.ScraperConfig)
+		want   string
+	}{
+		{"no rounds", func(c *state.ScraperConfig) { c.RoundsPath = "$.missing[*]" }, "no rounds found"},
+		{"no puzzles", func(c *state.ScraperConfig) { c.PuzzlesPath = "$.missing[*]" }, "no puzzles found"},
+		{"no round name", func(c *state.ScraperConfig) { c.RoundNamePath = "$.missing" }, "round name not found"},
+		{"round name not text", func(c *state.ScraperConfig) { c.RoundNamePath = "$.puzzles" }, "round name is not a string"},
+		{"no puzzle url", func(c *state.ScraperConfig) { c.PuzzleURLPath = "$.missing" }, "puzzle url not found"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var config = config
+			tc.mutate(&config)
+			_, err := parseFixture(t, config, "rounds.json")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	scraper, err := NewScraper(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scraper.Parse(strings.NewReader("<html>"), target); err == nil ||
+		!strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("HTML response: got %v, want invalid JSON", err)
+	}
+
+	config.PuzzlesPath = "$.puzzles["
+	var ve state.ValidationError
+	if _, err := NewScraper(config); !errors.As(err, &ve) || ve.Field != "puzzles_path" {
+		t.Errorf("malformed path: got %v, want ValidationError on puzzles_path", err)
+	}
+}
+
+func TestHTMLScraper(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  state.ScraperConfig
+		fixture string
+		want    []state.ScrapedPuzzle
+	}{
+		{
+			// Rounds without a puzzle list are skipped; the puzzle count is
+			// trimmed from round names
+			name: "group mode",
+			config: state.ScraperConfig{
+				GroupMode:          true,
+				GroupSelector:      ".info div section",
+				RoundNameSelector:  "a h3",
+				PuzzleListSelector: "table",
+			},
+			fixture: "grouped.html",
+			want: []state.ScrapedPuzzle{
+				{Name: "Apples", RoundName: "Fruit Salad", PuzzleURL: "https://example.com/puzzles/apples"},
+				{Name: "Bananas", RoundName: "Fruit Salad", PuzzleURL: "https://example.com/hunt/bananas"},
+			},
+		},
+		{
+			// A heading followed by another heading is a sub-round
+			name: "sequence",
+			config: state.ScraperConfig{
+				GroupSelector:      "section#main-content",
+				RoundNameSelector:  "h2",
+				PuzzleListSelector: "table",
+			},
+			fixture: "sequence.html",
+			want: []state.ScrapedPuzzle{
+				{Name: "Apples", RoundName: "Fruit", PuzzleURL: "https://example.com/puzzles/apples"},
+				{Name: "Bananas", RoundName: "Fruit", PuzzleURL: "https://example.com/puzzles/bananas"},
+				{Name: "Carrots", RoundName: "Root Vegetables", PuzzleURL: "https://example.com/puzzles/carrots"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseFixture(t, tc.config, tc.fixture)
+			if err != nil {
+				t.Fatal(err)
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v\nwant %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHTMLScraperErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  state.ScraperConfig
+		fixture string
+		want    string
+	}{
+		{
+			name: "no groups",
+			config: state.ScraperConfig{
+				GroupMode: true, GroupSelector: "article",
+				RoundNameSelector: "h3", PuzzleListSelector: "table",
+			},
+			fixture: "grouped.html",
+			want:    "no groups found",
+		},
+		{
+			name: "no round name",
+			config: state.ScraperConfig{
+				GroupMode: true, GroupSelector: "section",
+				RoundNameSelector: "h1", PuzzleListSelector: "table",
+			},
+			fixture: "grouped.html",
+			want:    "round name node not found",
+		},
+		{
+			name: "no container",
+			config: state.ScraperConfig{
+				GroupSelector: "main", RoundNameSelector: "h2", PuzzleListSelector: "table",
+			},
+			fixture: "sequence.html",
+			want:    "container not found",
+		},
+		{
+			name: "no rounds",
+			config: state.ScraperConfig{
+				GroupSelector: "section#main-content", RoundNameSelector: "h1",
+				PuzzleListSelector: "table",
+			},
+			fixture: "sequence.html",
+			want:    "no rounds found",
+		},
+		{
+			name: "no puzzle list",
+			config: state.ScraperConfig{
+				GroupSelector: "section#main-content", RoundNameSelector: "h2",
+				PuzzleListSelector: "ul",
+			},
+			fixture: "sequence.html",
+			want:    "puzzle table not found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseFixture(t, tc.config, tc.fixture)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("got %v, want %q", err, tc.want)
+			}
+		})
+	}
+
+	var ve state.ValidationError
+	_, err := NewScraper(state.ScraperConfig{GroupSelector: "section[", RoundNameSelector: "h2"})
+	if !errors.As(err, &ve) || ve.Field != "group_selector" {
+		t.Errorf("malformed selector: got %v, want ValidationError on group_selector", err)
+	}
+}
@@ -11,8 +11,8 @@ import (
 var hueData []byte
 var hues = loadHues()
 
-func loadHues() map[string]uint8 {
-	var hues = make(map[string]uint8)
+func loadHues() map[string]int64 {
+	var hues = make(map[string]int64)
 
 	var data [][]interface{}
 	err := json.Unmarshal(hueData, &data)
@@ -21,12 +21,14 @@ func loadHues() map[string]uint8 {
 	}
 	for _, line := range data {
 		for _, emoji := range line[1:] {
-			hues[emoji.(string)] = uint8(line[0].(float64))
+			hues[emoji.(string)] = int64(line[0].(float64))
 		}
 	}
 	return hues
 }
 
-func EmojiHue(emoji string) uint8 {
+// EmojiHue returns the hue, in degrees, that goes with the emoji, or 0 if it's
+// unknown.
+func EmojiHue(emoji string) int64 {
 	return hues[emoji]
 }
@@ -1,12 +1,17 @@
 package emojiname
 
 import (
+	"cmp"
 	_ "embed"
 	"encoding/json"
+	"hash/fnv"
 	"log"
 	"math/rand"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"golang.org/x/xerrors"
 )
@@ -23,6 +28,17 @@ type Emoji struct {
 	Unified string `json:"unified"`
 }
 
+// String returns the emoji itself, e.g. "👍🏻"
+func (e *Emoji) String() string {
+	var chars []rune
+	for _, hex := range strings.Split(e.Unified, "-") {
+		if n, err := strconv.ParseInt(hex, 16, 32); err == nil {
+			chars = append(chars, rune(n))
+		}
+	}
+	return string(chars)
+}
+
 func contains(haystack []string, needle string) bool {
 	for _, item := range haystack {
 		if item == needle {
@@ -84,6 +100,75 @@ func RandomEmoji(n int) ([]*Emoji, error) {
 	return ret, nil
 }
 
+// Common words that shouldn't be matched against emoji names. (Words shorter
+// than three letters are skipped anyway.)
+var stopwords = []string{
+	"and", "are", "but", "for", "from", "into", "its", "not", "off", "out",
+	"our", "over", "the", "this", "that", "with", "you", "your",
+}
+
+// SuggestEmoji picks n emoji for a round with the given name: first those that
+// share a word with the name, then random ones. Emoji in the exclude list (e.g.
+// those already used by other rounds) are skipped, as are emoji without a known
+// hue. The suggestions are the same each time for a given name.
+func SuggestEmoji(name string, exclude []string, n int) ([]*Emoji, error) {
+	allEmoji, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) >= 3 && !contains(stopwords, word) {
+			words = append(words, word, strings.TrimSuffix(word, "s"))
+		}
+	}
+	var matches, others []*Emoji
+	for _, e := range allEmoji {
+		if _, ok := hues[e.String()]; !ok || e.weight() == 0 {
+			continue
+		} else if contains(exclude, e.String()) {
+			continue
+		}
+		var tokens = strings.Fields(strings.ToLower(e.Name))
+		for _, short := range e.ShortNames {
+			tokens = append(tokens, strings.Split(short, "_")...)
+		}
+		var match = slices.ContainsFunc(words, func(w string) bool { return contains(tokens, w) })
+		if match && e.weight() < 1 {
+			// Flags, letters, etc. match lots of names by accident (e.g. "The
+			// Ocean" and 🇮🇴 British Indian Ocean Territory), so they have to
+			// match their whole name
+			for _, token := range strings.Fields(strings.ToLower(e.Name)) {
+				if token != "flag" && !contains(words, token) {
+					match = false
+				}
+			}
+		}
+		if match {
+			matches = append(matches, e)
+		} else if e.weight() == 1 {
+			others = append(others, e) // skip flags, letters, etc.
+		}
+	}
+
+	var hash = fnv.New64a()
+	hash.Write([]byte(strings.ToLower(name)))
+	var shuffle = rand.New(rand.NewSource(int64(hash.Sum64())))
+	shuffle.Shuffle(len(matches), func(i, j int) { matches[i], matches[j] = matches[j], matches[i] })
+	shuffle.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+
+	// ...and go after the other matches
+	slices.SortStableFunc(matches, func(a, b *Emoji) int {
+		return cmp.Compare(b.weight(), a.weight())
+	})
+
+	var suggestions = append(matches, others...)
+	return suggestions[:min(n, len(suggestions))], nil
+}
+
 // emoji.json from https://github.com/iamcal/emoji-data/blob/master/emoji.json
 // Copyright (c) 2013 Cal Henderson and MIT licensed.
 //
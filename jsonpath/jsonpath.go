@@ -0,0 +1,125 @@
+// Package jsonpath implements the subset of JSONPath needed to pick puzzles out
+// of a hunt's JSON API: the root `$`, child keys (`.key` or `['key']`),
+// wildcards (`.*` or `[*]`) and array indexes (`[0]`, or `[-1]` for the last
+// element). The leading `$.` is optional.
+package jsonpath
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+type Path []step
+
+type step struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func Compile(expr string) (Path, error) {
+	var s = strings.TrimSpace(expr)
+	if s == "" {
+		return nil, xerrors.New("is empty")
+	}
+	if strings.HasPrefix(s, "$") {
+		s = s[1:]
+	} else if !strings.HasPrefix(s, "[") {
+		s = "." + s
+	}
+
+	var path Path
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			return nil, xerrors.New("recursive descent (..) isn't supported")
+		case strings.HasPrefix(s, "."):
+			var end = strings.IndexAny(s[1:], ".[")
+			if end < 0 {
+				end = len(s) - 1
+			}
+			var key = s[1 : end+1]
+			if key == "" {
+				return nil, xerrors.Errorf("missing key in %q", expr)
+			} else if strings.Contains(key, "]") {
+				return nil, xerrors.Errorf("unexpected ] in %q", expr)
+			} else if key == "*" {
+				path = append(path, step{wildcard: true})
+			} else {
+				path = append(path, step{key: key})
+			}
+			s = s[end+1:]
+		case strings.HasPrefix(s, "["):
+			var end = strings.Index(s, "]")
+			if end < 0 {
+				return nil, xerrors.Errorf("unclosed [ in %q", expr)
+			}
+			var inner = strings.TrimSpace(s[1:end])
+			if inner == "*" {
+				path = append(path, step{wildcard: true})
+			} else if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') &&
+				inner[len(inner)-1] == inner[0] {
+				path = append(path, step{key: inner[1 : len(inner)-1]})
+			} else if index, err := strconv.Atoi(inner); err == nil {
+				path = append(path, step{index: index, isIndex: true})
+			} else {
+				return nil, xerrors.Errorf("invalid subscript [%s]", inner)
+			}
+			s = s[end+1:]
+		default:
+			return nil, xerrors.Errorf("unexpected %q in %q", s[:1], expr)
+		}
+	}
+	return path, nil
+}
+
+func MustCompile(expr string) Path {
+	path, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// Eval returns the values matched by the path, in document order. The value
+// should be decoded by encoding/json into an `any`, so objects are maps and
+// arrays are slices. Keys and indexes that don't exist are skipped.
+func (p Path) Eval(value any) []any {
+	var values = []any{value}
+	for _, step := range p {
+		var next []any
+		for _, value := range values {
+			switch v := value.(type) {
+			case map[string]any:
+				if step.wildcard {
+					// Go maps are unordered, so this can't preserve the order of the
+					// keys in the document; go in key order instead.
+					for _, key := range slices.Sorted(maps.Keys(v)) {
+						next = append(next, v[key])
+					}
+				} else if item, ok := v[step.key]; ok && !step.isIndex {
+					next = append(next, item)
+				}
+			case []any:
+				if step.wildcard {
+					next = append(next, v...)
+				} else if step.isIndex {
+					var i = step.index
+					if i < 0 {
+						i += len(v)
+					}
+					if i >= 0 && i < len(v) {
+						next = append(next, v[i])
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
@@ -0,0 +1,104 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		want Path
+	}{
+		{"$", nil},
+		{"$.rounds", Path{{key: "rounds"}}},
+		{"rounds", Path{{key: "rounds"}}},
+		{" $.rounds ", Path{{key: "rounds"}}},
+		{"$.rounds[*].puzzles", Path{{key: "rounds"}, {wildcard: true}, {key: "puzzles"}}},
+		{"$.rounds.*", Path{{key: "rounds"}, {wildcard: true}}},
+		{"$['round name']", Path{{key: "round name"}}},
+		{`$["round name"]`, Path{{key: "round name"}}},
+		{"[0]", Path{{index: 0, isIndex: true}}},
+		{"$.rounds[-1]", Path{{key: "rounds"}, {index: -1, isIndex: true}}},
+		{"$.rounds[ 2 ]", Path{{key: "rounds"}, {index: 2, isIndex: true}}},
+	} {
+		got, err := Compile(tc.expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tc.expr, err)
+		} else if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Compile(%q): got %+v, want %+v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"   ",
+		"$..name",
+		"$.",
+		"$.rounds.",
+		"$.rounds[0",
+		"$.rounds[x]",
+		"$.rounds['x\"]",
+		"$.rounds[]",
+		"$rounds",
+		"$.rounds]",
+	} {
+		if path, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q): got %+v, want error", expr, path)
+		}
+	}
+}
+
+const document = `{
+	"rounds": [
+		{"title": "Fruit", "puzzles": [
+			{"name": "Apples", "url": "/apples", "number": 1},
+			{"name": "Bananas", "url": "/bananas", "number": 2}
+		]},
+		{"title": "Vegetables", "puzzles": [
+			{"name": "Carrots", "url": "/carrots", "number": 3}
+		]}
+	],
+	"meta": {"b": "second", "a": "first"},
+	"round name": "spaces"
+}`
+
+func TestEval(t *testing.T) {
+	var root any
+	if err := json.Unmarshal([]byte(document), &root); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		expr string
+		want []any
+	}{
+		{"$.rounds[*].title", []any{"Fruit", "Vegetables"}},
+		{"$.rounds[*].puzzles[*].name", []any{"Apples", "Bananas", "Carrots"}},
+		{"$.rounds[0].puzzles[-1].url", []any{"/bananas"}},
+		{"$.rounds[-1].puzzles[0].number", []any{3.0}},
+		{"$['round name']", []any{"spaces"}},
+		{"$.meta.*", []any{"first", "second"}}, // in key order
+		{"$.meta[*]", []any{"first", "second"}},
+
+		// Things that don't exist are skipped
+		{"$.missing", nil},
+		{"$.rounds[5]", nil},
+		{"$.rounds[-5]", nil},
+		{"$.rounds[*].missing", nil},
+		{"$.rounds.title", nil},  // key on an array
+		{"$.meta[0]", nil},       // index on an object
+		{"$.round name[0]", nil}, // index on a string
+	} {
+		var got = MustCompile(tc.expr).Eval(root)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Eval(%q): got %#v, want %#v", tc.expr, got, tc.want)
+		}
+	}
+
+	if got := MustCompile("$").Eval(root); len(got) != 1 || !reflect.DeepEqual(got[0], root) {
+		t.Errorf("Eval($): got %#v, want the root", got)
+	}
+}
@@ -129,11 +129,11 @@ func (c *Client) watch(ctx context.Context) error {
 	})
 
 	// Forward current global state
-	config, err := c.state.DiscoveryConfig(ctx)
+	meta, err := c.LoadMeta(ctx)
 	if err != nil {
 		return err
 	}
-	err = WriteMessage(ws, c.ComputeMeta(config))
+	err = WriteMessage(ws, meta)
 	if err != nil {
 		return err
 	}
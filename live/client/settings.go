@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"time"
 
 	"github.com/emojihunt/emojihunt/huntyet"
@@ -19,16 +20,29 @@ type SettingsMessage struct {
 	VoiceRooms map[string]string `json:"voice_rooms"`
 }
 
-func (c *Client) ComputeMeta(discovery state.DiscoveryConfig) SettingsMessage {
+// LoadMeta computes the SettingsMessage for the active hunt.
+func (c *Client) LoadMeta(ctx context.Context) (SettingsMessage, error) {
+	hunt, err := c.state.GetHunt(ctx, c.state.ActiveHunt())
+	if err != nil {
+		return SettingsMessage{}, err
+	}
+	info, err := c.state.HuntInfo(ctx)
+	if err != nil {
+		return SettingsMessage{}, err
+	}
+	return c.ComputeMeta(hunt, info), nil
+}
+
+func (c *Client) ComputeMeta(hunt state.Hunt, info state.HuntInfo) SettingsMessage {
 	var nextHunt string
 	if raw, _ := huntyet.NextHunt(time.Now()); raw != nil {
 		nextHunt = raw.Format(time.RFC3339)
 	}
 	return SettingsMessage{
-		HuntName:        discovery.HuntName,
-		HuntURL:         discovery.HuntURL,
-		HuntCredentials: discovery.HuntCredentials,
-		LogisticsURL:    discovery.LogisticsURL,
+		HuntName:        hunt.Name,
+		HuntURL:         info.HuntURL,
+		HuntCredentials: info.HuntCredentials,
+		LogisticsURL:    info.LogisticsURL,
 
 		DiscordGuild: c.discord.Guild.ID,
 		NextHunt:     nextHunt,
@@ -26,7 +26,15 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-var prod = flag.Bool("prod", false, "selects development or production")
+var (
+	prod    = flag.Bool("prod", false, "selects development or production")
+	restore = flag.String("restore", "",
+		"replaces the database with the given snapshot (or \"latest\") before starting")
+
+	snapshotDir      = flag.String("snapshot-dir", "snapshots", "directory for database snapshots")
+	snapshotInterval = flag.Duration("snapshot-interval", 15*time.Minute, "time between database snapshots")
+	snapshotKeep     = flag.Int("snapshot-keep", 96, "number of database snapshots to keep")
+)
 
 func init() { flag.Parse() }
 
@@ -50,7 +58,23 @@ func main() {
 	// Set up the main context, which is cancelled on Ctrl-C
 	ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
 
-	// Open database connection
+	// Open database connection, restoring from a snapshot if requested
+	if *restore != "" {
+		var snapshot = *restore
+		if snapshot == "latest" {
+			snapshots, err := state.ListSnapshots(*snapshotDir)
+			if err != nil {
+				log.Panicf("state.ListSnapshots: %s", err)
+			} else if len(snapshots) == 0 {
+				log.Panicf("no snapshots found in %q", *snapshotDir)
+			}
+			snapshot = snapshots[len(snapshots)-1]
+		}
+		log.Printf("restoring database from %q", snapshot)
+		if err := state.Restore(ctx, "db.sqlite", snapshot); err != nil {
+			log.Panicf("state.Restore: %s", err)
+		}
+	}
 	var state = state.New(ctx, "db.sqlite")
 
 	// Set up clients
@@ -81,6 +105,7 @@ func main() {
 	log.Printf("starting discord bots and handlers")
 	discord.RegisterBots(
 		bot.NewEmojiNameBot(),
+		bot.NewFindBot(state),
 		bot.NewHuntYetBot(),
 		bot.NewPuzzleBot(discord, state),
 		bot.NewQMBot(discord, state),
@@ -90,9 +115,11 @@ func main() {
 
 	go live.Watch(ctx)
 	go syncer.Watch(ctx)
+	go syncer.SolverWorker(ctx)
 	go discovery.SyncWorker(ctx)
 	go discovery.Watch(ctx)
 	go state.HandleMetrics()
+	go state.Snapshotter(ctx, *snapshotDir, *snapshotInterval, *snapshotKeep)
 
 	log.Printf("starting web server")
 	var server = server.Start(ctx, *prod, ably, discord, live, state, syncer)
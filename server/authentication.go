@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/emojihunt/emojihunt/state"
 	"github.com/emojihunt/emojihunt/util"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/xerrors"
@@ -104,6 +105,20 @@ func (s *Server) oauth2TokenExchange(code, redirectURI string) (string, error) {
 	}
 }
 
+// ActorMiddleware attributes changes made during the request to the logged-in
+// user, if any.
+func (s *Server) ActorMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if user, ok := s.cookie.GetUserID(c); ok {
+			var req = c.Request()
+			c.SetRequest(req.WithContext(
+				state.WithActor(req.Context(), state.Actor(user)),
+			))
+		}
+		return next(c)
+	}
+}
+
 func (s *Server) Logout(c echo.Context) error {
 	c.SetCookie(&http.Cookie{
 		Name:     util.SessionCookieName,
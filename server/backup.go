@@ -0,0 +1,29 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+)
+
+// DownloadBackup streams a consistent copy of the database. The copy is
+// written to a temporary directory first, since the online backup API can't
+// write to an arbitrary io.Writer.
+func (s *Server) DownloadBackup(c echo.Context) error {
+	dir, err := os.MkdirTemp("", "backup")
+	if err != nil {
+		return xerrors.Errorf("MkdirTemp: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var path = filepath.Join(dir, "db.sqlite")
+	if err := s.state.Backup(c.Request().Context(), path); err != nil {
+		return err
+	}
+	var name = fmt.Sprintf("emojihunt-%s.sqlite", time.Now().UTC().Format("20060102T150405Z"))
+	return c.Attachment(path, name)
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/emojihunt/emojihunt/discovery"
@@ -9,21 +11,75 @@ import (
 )
 
 type DiscoveryParams struct {
-	PuzzlesURL         string `form:"puzzles_url"`
-	CookieName         string `form:"cookie_name"`
-	CookieValue        string `form:"cookie_value"`
+	PuzzlesURL  string `form:"puzzles_url"`
+	CookieName  string `form:"cookie_name"`
+	CookieValue string `form:"cookie_value"`
+	ScraperParams
+	WebsocketURL   string        `form:"websocket_url"`
+	WebsocketToken string        `form:"websocket_token"`
+	Sources        SourcesParams `form:"sources"`
+}
+
+type ScraperParams struct {
+	Scraper            string `form:"scraper"`
 	GroupMode          bool   `form:"group_mode"`
 	GroupSelector      string `form:"group_selector"`
 	RoundNameSelector  string `form:"round_name_selector"`
 	PuzzleListSelector string `form:"puzzle_list_selector"`
 	PuzzleItemSelector string `form:"puzzle_item_selector"`
-	WebsocketURL       string `form:"websocket_url"`
-	WebsocketToken     string `form:"websocket_token"`
+	RoundsPath         string `form:"rounds_path"`
+	RoundNamePath      string `form:"round_name_path"`
+	PuzzlesPath        string `form:"puzzles_path"`
+	PuzzleNamePath     string `form:"puzzle_name_path"`
+	PuzzleURLPath      string `form:"puzzle_url_path"`
+	GraphQLQuery       string `form:"graphql_query"`
+}
 
-	HuntName        string `form:"hunt_name"`
-	HuntURL         string `form:"hunt_url"`
-	HuntCredentials string `form:"hunt_credentials"`
-	LogisticsURL    string `form:"logistics_url"`
+// SourcesParams is the list of additional sources, encoded as JSON since it
+// doesn't fit in a form field.
+type SourcesParams []state.DiscoverySource
+
+func (p *SourcesParams) UnmarshalParam(param string) error {
+	if param == "" {
+		*p = nil
+		return nil
+	}
+	return json.Unmarshal([]byte(param), p)
+}
+
+// Binds the request's params onto the config. Fields that aren't in the
+// request are left unchanged.
+func bindDiscoveryParams(c echo.Context, config *state.DiscoveryConfig) error {
+	var params = DiscoveryParams{
+		PuzzlesURL:     config.PuzzlesURL,
+		CookieName:     config.CookieName,
+		CookieValue:    config.CookieValue,
+		ScraperParams:  ScraperParams(config.ScraperConfig),
+		WebsocketURL:   config.WebsocketURL,
+		WebsocketToken: config.WebsocketToken,
+		Sources:        config.Sources,
+	}
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	*config = state.DiscoveryConfig{
+		PuzzlesURL:     params.PuzzlesURL,
+		CookieName:     params.CookieName,
+		CookieValue:    params.CookieValue,
+		ScraperConfig:  state.ScraperConfig(params.ScraperParams),
+		WebsocketURL:   params.WebsocketURL,
+		WebsocketToken: params.WebsocketToken,
+		Sources:        params.Sources,
+	}
+	return nil
+}
+
+type TestDiscoveryResponse struct {
+	Puzzles []state.ScrapedPuzzle `json:"puzzles"`
+	Errors  map[string]string     `json:"errors"` // by source URL
+
+	// What discovery would do with the puzzles, given the current database
+	Preview discovery.Preview `json:"preview"`
 }
 
 func (s *Server) GetDiscovery(c echo.Context) error {
@@ -37,8 +93,7 @@ func (s *Server) GetDiscovery(c echo.Context) error {
 func (s *Server) UpdateDiscovery(c echo.Context) error {
 	config, err := s.state.UpdateDiscoveryConfig(c.Request().Context(),
 		func(config *state.DiscoveryConfig) error {
-			var params = (*DiscoveryParams)(config)
-			err := c.Bind(params)
+			err := bindDiscoveryParams(c, config)
 			if err != nil {
 				return err
 			}
@@ -59,8 +114,7 @@ func (s *Server) TestDiscovery(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	var params = (*DiscoveryParams)(&config)
-	err = c.Bind(params)
+	err = bindDiscoveryParams(c, &config)
 	if err != nil {
 		return err
 	}
@@ -68,9 +122,25 @@ func (s *Server) TestDiscovery(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	puzzles, err := poller.Scrape(c.Request().Context())
-	if err != nil {
+	// Failed sources are listed alongside the others' puzzles, unless they all
+	// failed
+	var response = TestDiscoveryResponse{Errors: map[string]string{}}
+	response.Puzzles, err = poller.Scrape(c.Request().Context())
+	var se discovery.SourceError
+	for _, err := range discovery.Unjoin(err) {
+		if !errors.As(err, &se) {
+			return err
+		}
+		response.Errors[se.URL] = se.Err.Error()
+	}
+	if len(response.Puzzles) == 0 && err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	return c.JSON(http.StatusOK, puzzles)
+	response.Preview, err = discovery.PreviewScrape(
+		c.Request().Context(), s.state, response.Puzzles,
+	)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
+)
+
+// Export returns a zip file containing the hunt archive as JSON (the canonical
+// format, suitable for re-importing) plus a CSV file per table, for use in
+// spreadsheets.
+func (s *Server) Export(c echo.Context) error {
+	archive, err := s.state.Export(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	var name = fmt.Sprintf("emojihunt-%s", archive.ExportedAt.UTC().Format("20060102T150405Z"))
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", name+".zip"))
+	c.Response().WriteHeader(http.StatusOK)
+
+	var w = zip.NewWriter(c.Response())
+	f, err := w.Create(name + "/archive.json")
+	if err != nil {
+		return xerrors.Errorf("zip.Create: %w", err)
+	}
+	var encoder = json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return xerrors.Errorf("Encode: %w", err)
+	}
+
+	var tables = []struct {
+		name string
+		rows any
+	}{
+		{"rounds", archive.Rounds},
+		{"puzzles", archive.Puzzles},
+		{"feeds", archive.Feeds},
+		{"guesses", archive.Guesses},
+		{"history", archive.History},
+		{"reminders", archive.Reminders},
+		{"scheduled_reminders", archive.ScheduledReminders},
+		{"discovered_rounds", archive.DiscoveredRounds},
+		{"discovered_puzzles", archive.DiscoveredPuzzles},
+	}
+	for _, table := range tables {
+		f, err := w.Create(name + "/" + table.name + ".csv")
+		if err != nil {
+			return xerrors.Errorf("zip.Create: %w", err)
+		}
+		if err := writeCSV(f, table.rows); err != nil {
+			return xerrors.Errorf("%s: %w", table.name, err)
+		}
+	}
+	return w.Close()
+}
+
+// Writes a slice of structs as CSV. Columns are named after the struct's JSON
+// tags, and fields of embedded structs are flattened.
+func writeCSV(w io.Writer, rows any) error {
+	var v = reflect.ValueOf(rows)
+	var fields = csvFields(v.Type().Elem(), nil)
+
+	var cw = csv.NewWriter(w)
+	var record = make([]string, len(fields))
+	for i, field := range fields {
+		record[i] = field.name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		for j, field := range fields {
+			record[j] = csvValue(v.Index(i).FieldByIndex(field.index).Interface())
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+func csvFields(t reflect.Type, prefix []int) []csvField {
+	var fields []csvField
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+		var index = append(append([]int{}, prefix...), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, csvFields(field.Type, index)...)
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		} else if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, csvField{name, index})
+	}
+	return fields
+}
+
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return csvValue(*v)
+	case sql.NullTime:
+		if !v.Valid {
+			return ""
+		}
+		return csvValue(v.Time)
+	case sql.NullInt64:
+		if !v.Valid {
+			return ""
+		}
+		return strconv.FormatInt(v.Int64, 10)
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
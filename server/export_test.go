@@ -0,0 +1,32 @@
+package server
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestCSVValue(t *testing.T) {
+	var when = time.Date(2026, 1, 16, 12, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value any
+		want  string
+	}{
+		{"text", "text"},
+		{int64(42), "42"},
+		{true, "true"},
+		{time.Time{}, ""},
+		{when, "2026-01-16T12:30:00Z"},
+		{(*time.Time)(nil), ""},
+		{&when, "2026-01-16T12:30:00Z"},
+		{sql.NullTime{}, ""},
+		{sql.NullTime{Time: when, Valid: true}, "2026-01-16T12:30:00Z"},
+		{sql.NullInt64{}, ""},
+		{sql.NullInt64{Int64: 7, Valid: true}, "7"},
+		{[]string{"a", "b"}, "a,b"},
+	} {
+		if got := csvValue(tc.value); got != tc.want {
+			t.Errorf("csvValue(%#v) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
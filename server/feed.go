@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/labstack/echo/v4"
+)
+
+type FeedParams struct {
+	Puzzle int64 `param:"id"`
+	Meta   int64 `param:"meta" form:"meta"`
+}
+
+func (s *Server) ListFeeds(c echo.Context) error {
+	feeds, err := s.state.ListFeeds(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, feeds)
+}
+
+func (s *Server) ListPuzzleFeeds(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	var ctx = c.Request().Context()
+	if _, err := s.state.GetPuzzle(ctx, id.ID); err != nil {
+		return err
+	}
+	metas, err := s.state.ListFedMetas(ctx, id.ID)
+	if err != nil {
+		return err
+	}
+	feeders, err := s.state.ListFeeders(ctx, id.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"feeds_into": metas,
+		"feeders":    feeders,
+	})
+}
+
+func (s *Server) CreateFeed(c echo.Context) error {
+	var params FeedParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	var feed = state.PuzzleFeed(params)
+	chid, err := s.state.CreateFeed(c.Request().Context(), feed)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, feed)
+}
+
+func (s *Server) DeleteFeed(c echo.Context) error {
+	var params FeedParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	var feed = state.PuzzleFeed(params)
+	chid, err := s.state.DeleteFeed(c.Request().Context(), feed)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, feed)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/emojihunt/emojihunt/state/status"
+	"github.com/labstack/echo/v4"
+)
+
+type GuessParams struct {
+	Puzzle int64              `param:"id"`
+	Guess  string             `form:"guess"`
+	Result status.GuessResult `form:"result"`
+	Note   string             `form:"note"`
+}
+
+func (s *Server) ListGuesses(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	var ctx = c.Request().Context()
+	if _, err := s.state.GetPuzzle(ctx, id.ID); err != nil {
+		return err
+	}
+	guesses, err := s.state.ListGuesses(ctx, id.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, guesses)
+}
+
+func (s *Server) CreateGuess(c echo.Context) error {
+	var params GuessParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	guess, chid, err := s.state.CreateGuess(c.Request().Context(), state.Guess{
+		Puzzle: params.Puzzle,
+		Guess:  params.Guess,
+		Result: params.Result,
+		Note:   params.Note,
+	})
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, guess)
+}
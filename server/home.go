@@ -8,18 +8,18 @@ import (
 )
 
 func (s *Server) ListHome(c echo.Context) error {
-	puzzles, rounds, changeID, discovery, err := s.state.ListHome(c.Request().Context())
+	puzzles, rounds, changeID, hunt, info, err := s.state.ListHome(c.Request().Context())
 	if err != nil {
 		return err
 	}
-	var rawPuzzles = make([]state.RawPuzzle, len(puzzles))
+	var ablyPuzzles = make([]state.AblyPuzzle, len(puzzles))
 	for i, puzzle := range puzzles {
-		rawPuzzles[i] = puzzle.RawPuzzle()
+		ablyPuzzles[i] = puzzle.AblyPuzzle()
 	}
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"change_id": changeID,
-		"puzzles":   rawPuzzles,
+		"puzzles":   ablyPuzzles,
 		"rounds":    rounds,
-		"settings":  s.live.ComputeMeta(discovery),
+		"settings":  s.live.ComputeMeta(hunt, info),
 	})
 }
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/labstack/echo/v4"
+)
+
+type HuntParams struct {
+	Hunt     int64  `param:"hunt"`
+	Name     string `form:"name"`
+	Archived bool   `form:"archived"`
+}
+
+// HuntMiddleware scopes the request to the hunt in the URL, so that the
+// handlers under /hunts/:hunt/... read (and write) that hunt instead of the
+// active one.
+func (s *Server) HuntMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var params HuntParams
+		if err := (&echo.DefaultBinder{}).BindPathParams(c, &params); err != nil {
+			return err
+		}
+		var req = c.Request()
+		hunt, err := s.state.GetHunt(req.Context(), params.Hunt)
+		if err != nil {
+			return err
+		}
+		c.SetRequest(req.WithContext(state.WithHunt(req.Context(), hunt.ID)))
+		return next(c)
+	}
+}
+
+func (s *Server) ListHunts(c echo.Context) error {
+	hunts, err := s.state.ListHunts(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hunts)
+}
+
+func (s *Server) GetHunt(c echo.Context) error {
+	var params HuntParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	hunt, err := s.state.GetHunt(c.Request().Context(), params.Hunt)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hunt)
+}
+
+func (s *Server) CreateHunt(c echo.Context) error {
+	var params HuntParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	hunt, err := s.state.CreateHunt(c.Request().Context(), params.Name)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hunt)
+}
+
+// UpdateHunt renames a hunt or (un)archives it. Fields missing from the
+// request are left unchanged.
+func (s *Server) UpdateHunt(c echo.Context) error {
+	var params HuntParams
+	if err := (&echo.DefaultBinder{}).BindPathParams(c, &params); err != nil {
+		return err
+	}
+	var ctx = c.Request().Context()
+	hunt, err := s.state.GetHunt(ctx, params.Hunt)
+	if err != nil {
+		return err
+	}
+	params.Name, params.Archived = hunt.Name, hunt.Archived
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	hunt, err = s.state.UpdateHunt(ctx, params.Hunt, params.Name, params.Archived)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hunt)
+}
+
+// ActivateHunt makes the hunt the active one: the bots, discovery and the
+// syncer switch over to it.
+func (s *Server) ActivateHunt(c echo.Context) error {
+	var params HuntParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	hunt, err := s.state.SetActiveHunt(c.Request().Context(), params.Hunt)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hunt)
+}
@@ -0,0 +1,480 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/emojihunt/emojihunt/emojiname"
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/xerrors"
+)
+
+// How many puzzles to create at once. Each one may create a spreadsheet and a
+// Discord channel, and both APIs are rate-limited.
+const importConcurrency = 4
+
+// ImportRow is a puzzle to import. Rounds are referenced by name, and rounds
+// that don't exist yet are created with the given emoji.
+type ImportRow struct {
+	Round      string `json:"round"`
+	RoundEmoji string `json:"round_emoji"`
+	Name       string `json:"name"`
+	PuzzleURL  string `json:"url"`
+	Meta       bool   `json:"meta"`
+}
+
+type ImportParams struct {
+	// Create spreadsheets and Discord channels for the new puzzles (and Drive
+	// folders and Discord categories for the new rounds).
+	Create bool `query:"create"`
+
+	// Validate the rows, but don't import anything.
+	DryRun bool `query:"dry_run"`
+}
+
+type ImportError struct {
+	Row     int    `json:"row"` // 1-indexed, excluding the CSV header
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportResponse struct {
+	Rounds  []state.Round      `json:"rounds"`
+	Puzzles []state.AblyPuzzle `json:"puzzles"`
+	Errors  []ImportError      `json:"errors"`
+}
+
+// Import creates puzzles (and rounds) in bulk from a CSV or JSON request body.
+// Every row is validated before anything is written; if any row is invalid,
+// nothing is imported and the errors are returned with status 422.
+func (s *Server) Import(c echo.Context) error {
+	// The body holds the rows, so only bind the query string
+	var params ImportParams
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &params); err != nil {
+		return err
+	}
+	rows, err := parseImport(c.Request())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if len(rows) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "no rows to import")
+	}
+
+	plan, errs, err := s.planImport(c, rows, params.Create)
+	if err != nil {
+		return err
+	} else if errs == nil {
+		errs = []ImportError{}
+	}
+	var response = ImportResponse{
+		Rounds: []state.Round{}, Puzzles: []state.AblyPuzzle{}, Errors: errs,
+	}
+	if len(errs) > 0 {
+		return c.JSON(http.StatusUnprocessableEntity, response)
+	} else if params.DryRun {
+		return c.JSON(http.StatusOK, response)
+	}
+
+	response, chid := s.runImport(c, plan, params.Create)
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, response)
+}
+
+// Rows are parsed from JSON (an array of ImportRow) or CSV (with a header row
+// naming the columns), depending on the content type.
+func parseImport(r *http.Request) ([]ImportRow, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		var rows []ImportRow
+		if err := json.NewDecoder(r.Body).Decode(&rows); err != nil {
+			return nil, xerrors.Errorf("invalid JSON: %w", err)
+		}
+		return rows, nil
+	case "text/csv":
+		return parseImportCSV(r.Body)
+	default:
+		return nil, xerrors.Errorf("unsupported content type %q (want text/csv or application/json)", mediaType)
+	}
+}
+
+func parseImportCSV(r io.Reader) ([]ImportRow, error) {
+	var reader = csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	} else if err != nil {
+		return nil, xerrors.Errorf("invalid CSV: %w", err)
+	}
+	var columns = make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "round", "round_emoji", "name", "url", "meta":
+			columns[name] = i
+		default:
+			return nil, xerrors.Errorf("unknown column %q", name)
+		}
+	}
+	for _, name := range []string{"round", "name", "url"} {
+		if _, ok := columns[name]; !ok {
+			return nil, xerrors.Errorf("missing column %q", name)
+		}
+	}
+
+	var rows []ImportRow
+	for i := 1; ; i++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		} else if err != nil {
+			return nil, xerrors.Errorf("invalid CSV: %w", err)
+		}
+		var get = func(name string) string {
+			if j, ok := columns[name]; ok {
+				return record[j]
+			}
+			return ""
+		}
+		var row = ImportRow{
+			Round:      get("round"),
+			RoundEmoji: get("round_emoji"),
+			Name:       get("name"),
+			PuzzleURL:  get("url"),
+		}
+		if meta := strings.TrimSpace(get("meta")); meta != "" {
+			row.Meta, err = strconv.ParseBool(meta)
+			if err != nil {
+				return nil, xerrors.Errorf("row %d: invalid value for meta: %q", i, meta)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+type importPlan struct {
+	rounds  []*importRound
+	puzzles []importPuzzle
+}
+
+type importRound struct {
+	state.Round
+	row     int // the first row that refers to this round, or 0 if it exists
+	created bool
+	invalid bool
+}
+
+type importPuzzle struct {
+	state.RawPuzzle
+	row   int
+	round *importRound
+}
+
+// Validates every row and works out which rounds need to be created. Returns
+// one ImportError per invalid row.
+func (s *Server) planImport(c echo.Context, rows []ImportRow, create bool) (
+	importPlan, []ImportError, error) {
+
+	var ctx = c.Request().Context()
+	existing, err := s.state.ListRounds(ctx)
+	if err != nil {
+		return importPlan{}, nil, err
+	}
+	var rounds = make(map[string]*importRound)
+	var emojis = make(map[string]bool)
+	for _, round := range existing {
+		rounds[strings.ToLower(round.Name)] = &importRound{Round: round, created: true}
+		emojis[round.Emoji] = true
+	}
+
+	var plan importPlan
+	var errs []ImportError
+	var names = make(map[string]bool)
+	var urls = make(map[string]bool)
+	for i, row := range rows {
+		var n = i + 1
+		var fail = func(field, message string) {
+			errs = append(errs, ImportError{n, field, message})
+		}
+		row.Round = strings.TrimSpace(row.Round)
+		row.RoundEmoji = strings.TrimSpace(row.RoundEmoji)
+		row.Name = strings.TrimSpace(row.Name)
+		row.PuzzleURL = strings.TrimSpace(row.PuzzleURL)
+
+		if row.Round == "" {
+			fail("round", "is required")
+			continue
+		}
+		round, ok := rounds[strings.ToLower(row.Round)]
+		if !ok {
+			round = &importRound{Round: state.Round{
+				Name:  row.Round,
+				Emoji: row.RoundEmoji,
+				Hue:   emojiname.EmojiHue(row.RoundEmoji),
+			}, row: n}
+			if create {
+				round.DriveFolder = "+"
+				round.DiscordCategory = "+"
+			}
+			rounds[strings.ToLower(row.Round)] = round
+			round.invalid = true
+			if !create {
+				fail("round", "does not exist (set create to add new rounds)")
+				continue
+			} else if err := state.ValidateRound(round.Round); err != nil {
+				var ve state.ValidationError
+				if errors.As(err, &ve) {
+					fail("round_"+ve.Field, ve.Message)
+				} else {
+					fail("round", err.Error())
+				}
+				continue
+			} else if emojis[round.Emoji] {
+				fail("round_emoji", "is already in use")
+				continue
+			}
+			round.invalid = false
+			emojis[round.Emoji] = true
+			plan.rounds = append(plan.rounds, round)
+		} else if round.invalid {
+			fail("round", fmt.Sprintf("is invalid (see row %d)", round.row))
+			continue
+		} else if row.RoundEmoji != "" && row.RoundEmoji != round.Emoji {
+			if round.row == 0 {
+				fail("round_emoji", fmt.Sprintf("doesn't match the existing round (%s)", round.Emoji))
+			} else {
+				fail("round_emoji", fmt.Sprintf("conflicts with %s (from row %d)", round.Emoji, round.row))
+			}
+			continue
+		}
+
+		var puzzle = state.RawPuzzle{
+			Name:      row.Name,
+			Round:     round.ID,
+			PuzzleURL: row.PuzzleURL,
+			Meta:      row.Meta,
+		}
+		if !round.created {
+			puzzle.Round = -1 // placeholder; validation just checks it's set
+		}
+		if err := s.state.ValidatePuzzle(ctx, puzzle); err != nil {
+			var ve state.ValidationError
+			if errors.As(err, &ve) && ve.Field == "puzzle_url" {
+				fail("url", ve.Message)
+			} else if errors.As(err, &ve) {
+				fail(ve.Field, ve.Message)
+			} else {
+				fail("", err.Error())
+			}
+			continue
+		}
+
+		var key = strings.ToLower(round.Name + "\x00" + puzzle.Name)
+		var url = strings.ToLower(puzzle.PuzzleURL)
+		if names[key] {
+			fail("name", "is duplicated in this import")
+			continue
+		} else if urls[url] {
+			fail("url", "is duplicated in this import")
+			continue
+		}
+		names[key], urls[url] = true, true
+		exists, err := s.state.IsPuzzleCreated(ctx, state.ScrapedPuzzle{
+			Name: puzzle.Name, RoundName: round.Name, PuzzleURL: puzzle.PuzzleURL,
+		})
+		if err != nil {
+			return importPlan{}, nil, err
+		} else if exists {
+			fail("name", "already exists")
+			continue
+		}
+		plan.puzzles = append(plan.puzzles, importPuzzle{puzzle, n, round})
+	}
+	return plan, errs, nil
+}
+
+// Creates the planned rounds, then the puzzles. Rounds are created one at a
+// time, since each one may create a Discord category; puzzles are created in
+// parallel. Failures are reported per-row and don't stop the import.
+func (s *Server) runImport(c echo.Context, plan importPlan, create bool) (ImportResponse, int64) {
+	var ctx = c.Request().Context()
+	var response = ImportResponse{
+		Rounds: []state.Round{}, Puzzles: []state.AblyPuzzle{}, Errors: []ImportError{},
+	}
+	var mu sync.Mutex
+	var chid int64
+	var fail = func(row int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var ve state.ValidationError
+		if errors.As(err, &ve) {
+			response.Errors = append(response.Errors, ImportError{row, ve.Field, ve.Message})
+		} else {
+			response.Errors = append(response.Errors, ImportError{row, "", err.Error()})
+		}
+	}
+
+	for _, round := range plan.rounds {
+		var err error
+		if round.DriveFolder == "+" {
+			round.DriveFolder, err = s.syncer.CreateDriveFolder(ctx, round.Round)
+			if err != nil {
+				fail(round.row, err)
+				continue
+			}
+		}
+		if round.DiscordCategory == "+" {
+			round.DiscordCategory, err = s.syncer.CreateDiscordCategory(ctx, round.Round)
+			if err != nil {
+				fail(round.row, err)
+				continue
+			}
+		}
+		created, id, err := s.state.CreateRound(ctx, round.Round)
+		if err != nil {
+			fail(round.row, err)
+			continue
+		}
+		round.Round, round.created = created, true
+		response.Rounds = append(response.Rounds, created)
+		chid = max(chid, id)
+	}
+
+	var created = make([]*state.Puzzle, len(plan.puzzles))
+	var group errgroup.Group
+	group.SetLimit(importConcurrency)
+	for i, puzzle := range plan.puzzles {
+		if !puzzle.round.created {
+			fail(puzzle.row, xerrors.Errorf("round %q could not be created", puzzle.round.Name))
+			continue
+		}
+		group.Go(func() error {
+			var err error
+			var raw = puzzle.RawPuzzle
+			raw.Round = puzzle.round.ID
+			if create {
+				raw.SpreadsheetID, err = s.syncer.CreateSpreadsheet(ctx, raw, puzzle.round.Round)
+				if err != nil {
+					fail(puzzle.row, err)
+					return nil
+				}
+				raw.DiscordChannel, err = s.syncer.CreateDiscordChannel(ctx, raw, puzzle.round.Round)
+				if err != nil {
+					fail(puzzle.row, err)
+					return nil
+				}
+			}
+			result, id, err := s.state.CreatePuzzle(ctx, raw)
+			if err != nil {
+				fail(puzzle.row, err)
+				return nil
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			created[i] = &result
+			chid = max(chid, id)
+			return nil
+		})
+	}
+	group.Wait()
+	sort.SliceStable(response.Errors, func(i, j int) bool {
+		return response.Errors[i].Row < response.Errors[j].Row
+	})
+
+	for _, puzzle := range created {
+		if puzzle != nil {
+			response.Puzzles = append(response.Puzzles, puzzle.AblyPuzzle())
+		}
+	}
+	return response, chid
+}
+
+// ImportArchive recreates the rounds and puzzles from a hunt archive, as
+// produced by Export. The body is either archive.json or the whole zip file.
+// Drive folders, spreadsheets and Discord channels are reused, not recreated.
+func (s *Server) ImportArchive(c echo.Context) error {
+	archive, err := parseArchive(c.Request())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	// The archived Drive folders belong to the old hunt. Validate the rounds
+	// before creating new ones, as in CreateRound.
+	var ctx = c.Request().Context()
+	for _, round := range archive.Rounds {
+		if err := state.ValidateRound(round); err != nil {
+			return xerrors.Errorf("round %q: %w", round.Name, err)
+		}
+	}
+	for i, round := range archive.Rounds {
+		round.DriveFolder = ""
+		archive.Rounds[i].DriveFolder, err = s.syncer.CreateDriveFolder(ctx, round)
+		if err != nil {
+			return err
+		}
+	}
+	rounds, puzzles, chid, err := s.state.ImportArchive(ctx, archive)
+	if err != nil {
+		return err
+	}
+	var response = ImportResponse{
+		Rounds: rounds, Puzzles: []state.AblyPuzzle{}, Errors: []ImportError{},
+	}
+	for _, puzzle := range puzzles {
+		response.Puzzles = append(response.Puzzles, puzzle.AblyPuzzle())
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, response)
+}
+
+func parseArchive(r *http.Request) (state.Archive, error) {
+	var archive state.Archive
+	var body io.Reader = r.Body
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+	case "application/zip":
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return state.Archive{}, err
+		}
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return state.Archive{}, xerrors.Errorf("invalid zip file: %w", err)
+		}
+		var i = slices.IndexFunc(zr.File, func(f *zip.File) bool {
+			return path.Base(f.Name) == "archive.json"
+		})
+		if i < 0 {
+			return state.Archive{}, xerrors.Errorf("zip file doesn't contain archive.json")
+		}
+		f, err := zr.File[i].Open()
+		if err != nil {
+			return state.Archive{}, xerrors.Errorf("invalid zip file: %w", err)
+		}
+		defer f.Close()
+		body = f
+	default:
+		return state.Archive{}, xerrors.Errorf("unsupported content type %q (want application/json or application/zip)", mediaType)
+	}
+	if err := json.NewDecoder(body).Decode(&archive); err != nil {
+		return state.Archive{}, xerrors.Errorf("invalid JSON: %w", err)
+	}
+	return archive, nil
+}
@@ -0,0 +1,123 @@
+// Command importer uploads a CSV or JSON file of puzzles to the huntbot API's
+// bulk import endpoint and prints the results. It can also upload a hunt
+// archive from /export (the zip file, or archive.json with -archive) to
+// recreate its rounds and puzzles.
+//
+// Usage: SESSION_COOKIE=... go run ./server/importer [-prod] [-create] [-dry-run] [-archive] FILE
+//
+// The session cookie can be copied from a logged-in browser.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/emojihunt/emojihunt/server"
+	"github.com/emojihunt/emojihunt/util"
+)
+
+var (
+	prod      = flag.Bool("prod", false, "selects development or production")
+	create    = flag.Bool("create", false, "create spreadsheets, channels, folders and categories")
+	dryRun    = flag.Bool("dry-run", false, "validate the file without importing anything")
+	isArchive = flag.Bool("archive", false, "the file is an archive.json from /export")
+)
+
+func init() {
+	flag.Parse()
+}
+
+func main() {
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: importer [-prod] [-create] [-dry-run] [-archive] FILE\n")
+		os.Exit(2)
+	}
+	cookie, ok := os.LookupEnv("SESSION_COOKIE")
+	if !ok {
+		panic("SESSION_COOKIE is required")
+	}
+
+	var path = flag.Arg(0)
+	var contentType string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		contentType = "text/csv"
+	case ".json":
+		contentType = "application/json"
+	case ".zip":
+		contentType = "application/zip"
+		*isArchive = true
+	default:
+		panic("file must end in .csv, .json or .zip")
+	}
+	if *isArchive && (*create || *dryRun) {
+		panic("-create and -dry-run can't be used with archives")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	var base = "http://localhost:8080"
+	if *prod {
+		base = "https://api.emojihunt.org"
+	}
+	var query = url.Values{}
+	query.Set("create", fmt.Sprint(*create))
+	query.Set("dry_run", fmt.Sprint(*dryRun))
+	var endpoint = base + "/import?" + query.Encode()
+	if *isArchive {
+		endpoint = base + "/import/archive"
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, file)
+	if err != nil {
+		panic(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.AddCookie(&http.Cookie{Name: util.SessionCookieName, Value: cookie})
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnprocessableEntity {
+		var body map[string]any
+		json.NewDecoder(resp.Body).Decode(&body)
+		fmt.Fprintf(os.Stderr, "import failed: %s: %v\n", resp.Status, body["message"])
+		os.Exit(1)
+	}
+
+	var result server.ImportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		panic(err)
+	}
+	for _, round := range result.Rounds {
+		fmt.Printf("created round %s %q\n", round.Emoji, round.Name)
+	}
+	for _, puzzle := range result.Puzzles {
+		fmt.Printf("created puzzle %q\n", puzzle.Name)
+	}
+	for _, e := range result.Errors {
+		if e.Field != "" {
+			fmt.Printf("row %d: %s %s\n", e.Row, e.Field, e.Message)
+		} else {
+			fmt.Printf("row %d: %s\n", e.Row, e.Message)
+		}
+	}
+	if len(result.Errors) > 0 {
+		if resp.StatusCode == http.StatusUnprocessableEntity {
+			fmt.Printf("nothing was imported; fix the errors above and try again\n")
+		}
+		os.Exit(1)
+	} else if *dryRun {
+		fmt.Printf("ok: file is valid\n")
+	}
+}
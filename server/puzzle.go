@@ -1,36 +1,60 @@
 package server
 
 import (
+	"database/sql"
 	"net/http"
 	"time"
 
 	"github.com/emojihunt/emojihunt/state"
 	"github.com/emojihunt/emojihunt/state/status"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/xerrors"
 )
 
 type PuzzleParams struct {
-	ID             int64         `param:"id"`
-	Name           string        `form:"name"`
-	Answer         string        `form:"answer"`
-	Round          int64         `form:"round"`
-	Status         status.Status `form:"status"`
-	Note           string        `form:"note"`
-	Location       string        `form:"location"`
-	PuzzleURL      string        `form:"puzzle_url"`
-	SpreadsheetID  string        `form:"spreadsheet_id"`
-	DiscordChannel string        `form:"discord_channel"`
-	Meta           bool          `form:"meta"`
-	VoiceRoom      string        `form:"voice_room"`
-	Reminder       time.Time     `form:"reminder"`
+	ID             int64           `param:"id"`
+	Name           string          `form:"name"`
+	Answer         string          `form:"answer"`
+	Round          int64           `form:"round"`
+	Status         status.Status   `form:"status"`
+	Note           string          `form:"note"`
+	Location       string          `form:"location"`
+	PuzzleURL      string          `form:"puzzle_url"`
+	SpreadsheetID  string          `form:"spreadsheet_id"`
+	DiscordChannel string          `form:"discord_channel"`
+	Meta           bool            `form:"meta"`
+	VoiceRoom      string          `form:"voice_room"`
+	Reminder       time.Time       `form:"reminder"`
+	Version        int64           `json:"-"` // see IfMatch
+	DeletedAt      sql.NullTime    `json:"-"` // see RestorePuzzle
+	Priority       status.Priority `form:"priority"`
+	NeedsHelp      bool            `form:"needs_help"`
+}
+
+// ListPuzzlesResponse expands Puzzle.Tags and Puzzle.Solvers, which are
+// stored as strings
+type ListPuzzlesResponse struct {
+	state.Puzzle
+	Tags    []string `json:"tags"`
+	Solvers []string `json:"solvers"`
 }
 
 func (s *Server) ListPuzzles(c echo.Context) error {
-	puzzles, err := s.state.ListPuzzles(c.Request().Context())
+	var err error
+	var puzzles []state.Puzzle
+	if tag := c.QueryParam("tag"); tag != "" {
+		puzzles, err = s.state.ListPuzzlesByTag(c.Request().Context(), tag)
+	} else {
+		puzzles, err = s.state.ListPuzzles(c.Request().Context())
+	}
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, puzzles)
+	var response = make([]ListPuzzlesResponse, len(puzzles))
+	for i, puzzle := range puzzles {
+		response[i] = ListPuzzlesResponse{puzzle, puzzle.TagList(), puzzle.SolverList()}
+	}
+	return c.JSON(http.StatusOK, response)
 }
 
 func (s *Server) GetPuzzle(c echo.Context) error {
@@ -42,7 +66,21 @@ func (s *Server) GetPuzzle(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, puzzle.RawPuzzle())
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
+}
+
+func (s *Server) ListPuzzleHistory(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	// History is kept after a puzzle is deleted, so don't check that the puzzle
+	// exists.
+	history, err := s.state.ListPuzzleHistory(c.Request().Context(), id.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, history)
 }
 
 func (s *Server) CreatePuzzle(c echo.Context) error {
@@ -83,7 +121,7 @@ func (s *Server) CreatePuzzle(c echo.Context) error {
 		return err
 	}
 	SetChangeIDHeader(c, chid)
-	return c.JSON(http.StatusOK, puzzle.RawPuzzle())
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
 }
 
 func (s *Server) UpdatePuzzle(c echo.Context) error {
@@ -91,17 +129,25 @@ func (s *Server) UpdatePuzzle(c echo.Context) error {
 	if err := c.Bind(&id); err != nil {
 		return err
 	}
-	updated, chid, err := s.state.UpdatePuzzle(c.Request().Context(), id.ID,
+	version, err := IfMatch(c)
+	if err != nil {
+		return err
+	}
+	apply, err := BindUpdate[PuzzleParams](c)
+	if err != nil {
+		return err
+	}
+	updated, chid, err := s.state.UpdatePuzzle(c.Request().Context(), id.ID, version,
 		func(puzzle *state.RawPuzzle) error {
-			var params = (*PuzzleParams)(puzzle)
-			return c.Bind(params)
+			apply((*PuzzleParams)(puzzle))
+			return nil
 		},
 	)
 	if err != nil {
 		return err
 	}
 	SetChangeIDHeader(c, chid)
-	return c.JSON(http.StatusOK, updated.RawPuzzle())
+	return c.JSON(http.StatusOK, updated.AblyPuzzle())
 }
 
 func (s *Server) DeletePuzzle(c echo.Context) error {
@@ -118,5 +164,83 @@ func (s *Server) DeletePuzzle(c echo.Context) error {
 		return err
 	}
 	SetChangeIDHeader(c, chid)
-	return c.JSON(http.StatusOK, puzzle.RawPuzzle())
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
+}
+
+type TagParams struct {
+	ID  int64  `param:"id"`
+	Tag string `param:"tag" form:"tag"`
+}
+
+func (s *Server) AddPuzzleTag(c echo.Context) error {
+	var params TagParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	puzzle, chid, err := s.state.AddPuzzleTag(c.Request().Context(), params.ID, params.Tag)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
+}
+
+func (s *Server) RemovePuzzleTag(c echo.Context) error {
+	var params TagParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	puzzle, chid, err := s.state.RemovePuzzleTag(c.Request().Context(), params.ID, params.Tag)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
+}
+
+func (s *Server) ListPuzzleSolvers(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	solvers, err := s.state.ListPuzzleSolvers(c.Request().Context(), id.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, solvers)
+}
+
+// JoinPuzzle and LeavePuzzle act on behalf of the logged-in user.
+func (s *Server) JoinPuzzle(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	user, ok := s.cookie.GetUserID(c)
+	if !ok {
+		return xerrors.Errorf("unauthenticated user")
+	}
+	puzzle, chid, err := s.state.JoinPuzzle(c.Request().Context(), id.ID, user)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
+}
+
+func (s *Server) LeavePuzzle(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	user, ok := s.cookie.GetUserID(c)
+	if !ok {
+		return xerrors.Errorf("unauthenticated user")
+	}
+	puzzle, chid, err := s.state.LeavePuzzle(c.Request().Context(), id.ID, user)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
 }
@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/xerrors"
@@ -26,5 +27,9 @@ func (s *Server) SendMessage(c echo.Context) error {
 		return xerrors.Errorf("unauthenticated user")
 	}
 	s.discord.RelayMessage(puzzle.DiscordChannel, user, params.Message)
+	err = s.state.TouchPuzzleSolver(c.Request().Context(), puzzle.DiscordChannel, user, time.Now())
+	if err != nil {
+		return err
+	}
 	return c.JSON(http.StatusOK, "")
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/emojihunt/emojihunt/state"
@@ -8,14 +9,17 @@ import (
 )
 
 type RoundParams struct {
-	ID              int64  `param:"id"`
-	Name            string `form:"name"`
-	Emoji           string `form:"emoji"`
-	Hue             int64  `form:"hue"`
-	Sort            int64  `form:"sort"`
-	Special         bool   `form:"special"`
-	DriveFolder     string `form:"drive_folder"`
-	DiscordCategory string `form:"discord_category"`
+	ID              int64        `param:"id"`
+	Name            string       `form:"name"`
+	Emoji           string       `form:"emoji"`
+	Hue             int64        `form:"hue"`
+	Sort            int64        `form:"sort"`
+	Special         bool         `form:"special"`
+	DriveFolder     string       `form:"drive_folder"`
+	DiscordCategory string       `form:"discord_category"`
+	Version         int64        `json:"-"` // see IfMatch
+	DeletedAt       sql.NullTime `json:"-"` // see RestoreRound
+	Hunt            int64        `json:"-"` // see HuntMiddleware
 }
 
 func (s *Server) ListRounds(c echo.Context) error {
@@ -51,6 +55,16 @@ func (s *Server) CreateRound(c echo.Context) error {
 	// Run validations before handling folder and category creation
 	if err = state.ValidateRound(round); err != nil {
 		return err
+	} else if err = s.state.ValidateRoundUnique(ctx, round); err != nil {
+		return err
+	}
+	if !s.state.IsActive(ctx) {
+		// Other hunts don't get a Drive folder or Discord category
+		if round.DriveFolder == "+" {
+			return state.ValidationError{Field: "drive_folder", Message: "can't be created outside the active hunt"}
+		} else if round.DiscordCategory == "+" {
+			return state.ValidationError{Field: "discord_category", Message: "can't be created outside the active hunt"}
+		}
 	}
 	if round.DriveFolder == "+" {
 		round.DriveFolder = ""
@@ -80,10 +94,18 @@ func (s *Server) UpdateRound(c echo.Context) error {
 	if err := c.Bind(&id); err != nil {
 		return err
 	}
-	updated, chid, err := s.state.UpdateRound(c.Request().Context(), id.ID,
+	version, err := IfMatch(c)
+	if err != nil {
+		return err
+	}
+	apply, err := BindUpdate[RoundParams](c)
+	if err != nil {
+		return err
+	}
+	updated, chid, err := s.state.UpdateRound(c.Request().Context(), id.ID, version,
 		func(round *state.Round) error {
-			var params = (*RoundParams)(round)
-			return c.Bind(params)
+			apply((*RoundParams)(round))
+			return nil
 		},
 	)
 	if err != nil {
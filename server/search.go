@@ -0,0 +1,28 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/labstack/echo/v4"
+)
+
+const searchLimit = 25
+
+type SearchParams struct {
+	Query string `query:"q"`
+}
+
+func (s *Server) Search(c echo.Context) error {
+	var params SearchParams
+	if err := c.Bind(&params); err != nil {
+		return err
+	}
+	results, err := s.state.Search(c.Request().Context(), params.Query, searchLimit)
+	if err != nil {
+		return err
+	} else if results == nil {
+		results = []state.SearchResult{}
+	}
+	return c.JSON(http.StatusOK, results)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -69,6 +70,7 @@ func Start(ctx context.Context, prod bool, ably *ably.Realtime,
 
 	e.HideBanner = true
 	e.Use(util.SentryMiddleware)
+	e.Use(s.ActorMiddleware)
 	s.echo.Use(echoprometheus.NewMiddleware("echo"))
 	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		DisablePrintStack: true,
@@ -100,7 +102,19 @@ func Start(ctx context.Context, prod bool, ably *ably.Realtime,
 	pg.POST("", s.CreatePuzzle)
 	pg.POST("/:id", s.UpdatePuzzle)
 	pg.DELETE("/:id", s.DeletePuzzle)
-
+	pg.POST("/:id/restore", s.RestorePuzzle)
+
+	pg.GET("/:id/history", s.ListPuzzleHistory)
+	pg.GET("/:id/feeds", s.ListPuzzleFeeds)
+	pg.POST("/:id/feeds", s.CreateFeed)
+	pg.DELETE("/:id/feeds/:meta", s.DeleteFeed)
+	pg.GET("/:id/guesses", s.ListGuesses)
+	pg.POST("/:id/tags", s.AddPuzzleTag)
+	pg.DELETE("/:id/tags/:tag", s.RemovePuzzleTag)
+	pg.GET("/:id/solvers", s.ListPuzzleSolvers)
+	pg.POST("/:id/solvers", s.JoinPuzzle)
+	pg.DELETE("/:id/solvers", s.LeavePuzzle)
+	pg.POST("/:id/guesses", s.CreateGuess)
 	pg.POST("/:id/messages", s.SendMessage)
 
 	var rg = e.Group("/rounds", s.cookie.AuthenticationMiddleware)
@@ -109,12 +123,46 @@ func Start(ctx context.Context, prod bool, ably *ably.Realtime,
 	rg.POST("", s.CreateRound)
 	rg.POST("/:id", s.UpdateRound)
 	rg.DELETE("/:id", s.DeleteRound)
+	rg.POST("/:id/restore", s.RestoreRound)
 
+	e.GET("/feeds", s.ListFeeds, s.cookie.AuthenticationMiddleware)
 	e.GET("/home", s.ListHome, s.cookie.AuthenticationMiddleware)
+	e.GET("/search", s.Search, s.cookie.AuthenticationMiddleware)
+	e.GET("/trash", s.ListTrash, s.cookie.AuthenticationMiddleware)
+	e.GET("/stats", s.GetStats, s.cookie.AuthenticationMiddleware)
+	e.GET("/export", s.Export, s.cookie.AuthenticationMiddleware)
+	e.POST("/import", s.Import, s.cookie.AuthenticationMiddleware)
+	e.POST("/import/archive", s.ImportArchive, s.cookie.AuthenticationMiddleware)
 	e.POST("/ably", s.RequestAblyToken, s.cookie.AuthenticationMiddleware)
 	e.GET("/discovery", s.GetDiscovery, s.cookie.AuthenticationMiddleware)
 	e.POST("/discovery", s.UpdateDiscovery, s.cookie.AuthenticationMiddleware)
 	e.POST("/discovery/test", s.TestDiscovery, s.cookie.AuthenticationMiddleware)
+	e.GET("/settings", s.ListSettings, s.cookie.AuthenticationMiddleware)
+	e.POST("/settings", s.UpdateSettings, s.cookie.AuthenticationMiddleware)
+	e.GET("/admin/backup", s.DownloadBackup, s.cookie.AuthenticationMiddleware)
+
+	var hg = e.Group("/hunts", s.cookie.AuthenticationMiddleware)
+	hg.GET("", s.ListHunts)
+	hg.POST("", s.CreateHunt)
+	hg.GET("/:hunt", s.GetHunt)
+	hg.POST("/:hunt", s.UpdateHunt)
+	hg.POST("/:hunt/activate", s.ActivateHunt)
+
+	// The routes above operate on the active hunt. These ones take a hunt ID,
+	// for browsing archived hunts and running practice hunts alongside the
+	// active one.
+	var sg = hg.Group("/:hunt", s.HuntMiddleware)
+	sg.GET("/puzzles", s.ListPuzzles)
+	sg.GET("/rounds", s.ListRounds)
+	sg.POST("/rounds", s.CreateRound)
+	sg.GET("/feeds", s.ListFeeds)
+	sg.GET("/home", s.ListHome)
+	sg.GET("/search", s.Search)
+	sg.GET("/trash", s.ListTrash)
+	sg.GET("/stats", s.GetStats)
+	sg.GET("/export", s.Export)
+	sg.GET("/settings", s.ListSettings)
+	sg.POST("/settings", s.UpdateSettings)
 
 	go func() {
 		err := e.Start(":8080")
@@ -137,12 +185,58 @@ func SetChangeIDHeader(c echo.Context, id int64) {
 	c.Response().Header().Set("X-Change-ID", strconv.FormatInt(id, 10))
 }
 
+// IfMatch parses the If-Match header, which clients set to the version of the
+// object they're updating. If the header is absent, returns state.AnyVersion.
+func IfMatch(c echo.Context) (int64, error) {
+	var header = c.Request().Header.Get("If-Match")
+	if header == "" || header == "*" {
+		return state.AnyVersion, nil
+	}
+	header = strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid If-Match header")
+	}
+	return version, nil
+}
+
+// BindUpdate binds a partial update from the request body. Call it before
+// taking the state lock, so that the body isn't read inside a transaction. The
+// returned function copies just the fields present in the request onto the
+// object being updated.
+func BindUpdate[T any](c echo.Context) (func(*T), error) {
+	var params T
+	if err := c.Bind(&params); err != nil {
+		return nil, err
+	}
+	if _, err := c.FormParams(); err != nil {
+		return nil, err
+	}
+	var form = c.Request().PostForm // excludes query params, which Bind ignores
+	return func(dst *T) {
+		var src, out = reflect.ValueOf(&params).Elem(), reflect.ValueOf(dst).Elem()
+		for i := 0; i < src.NumField(); i++ {
+			var tag = src.Type().Field(i).Tag.Get("form")
+			if _, ok := form[tag]; tag != "" && ok {
+				out.Field(i).Set(src.Field(i))
+			}
+		}
+	}, nil
+}
+
 func (s *Server) ErrorHandler(err error, c echo.Context) {
 	var ve state.ValidationError
+	var ce state.ConflictError
+	var ae state.ArchivedError
 	var se sqlite3.Error
 	if _, ok := err.(*echo.HTTPError); ok {
 	} else if ok := errors.As(err, &ve); ok {
 		err = echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	} else if ok := errors.As(err, &ce); ok {
+		// Send back the current object so the client can reconcile
+		err = echo.NewHTTPError(http.StatusConflict, ce.Current)
+	} else if ok := errors.As(err, &ae); ok {
+		err = echo.NewHTTPError(http.StatusForbidden, err.Error())
 	} else if ok := errors.As(err, &se); ok && se.Code == sqlite3.ErrConstraint {
 		err = echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	} else if errors.Is(err, sql.ErrNoRows) {
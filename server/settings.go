@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) ListSettings(c echo.Context) error {
+	settings, err := s.state.ListSettings(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings takes a JSON object mapping setting keys to their new values.
+// Form-encoded requests are also accepted, with each value encoded as JSON.
+func (s *Server) UpdateSettings(c echo.Context) error {
+	var values = make(map[string]json.RawMessage)
+	var req = c.Request()
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if err := json.NewDecoder(req.Body).Decode(&values); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	} else {
+		form, err := c.FormParams()
+		if err != nil {
+			return err
+		}
+		for key := range form {
+			values[key] = json.RawMessage(form.Get(key))
+		}
+	}
+	settings, err := s.state.UpdateSettings(req.Context(), values)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/labstack/echo/v4"
+)
+
+type TrashedPuzzle struct {
+	state.AblyPuzzle
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedRound struct {
+	state.Round
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Puzzles []TrashedPuzzle `json:"puzzles"`
+	Rounds  []TrashedRound  `json:"rounds"`
+}
+
+func (s *Server) ListTrash(c echo.Context) error {
+	puzzles, rounds, err := s.state.ListTrash(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	var response = TrashResponse{
+		Puzzles: make([]TrashedPuzzle, len(puzzles)),
+		Rounds:  make([]TrashedRound, len(rounds)),
+	}
+	for i, puzzle := range puzzles {
+		response.Puzzles[i] = TrashedPuzzle{puzzle.AblyPuzzle(), puzzle.DeletedAt}
+	}
+	for i, round := range rounds {
+		response.Rounds[i] = TrashedRound{round.Round, round.DeletedAt}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// RestorePuzzle takes a puzzle out of the trash. The syncer moves its channel
+// and spreadsheet back into place when it sees the upsert.
+func (s *Server) RestorePuzzle(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	puzzle, chid, err := s.state.RestorePuzzle(c.Request().Context(), id.ID)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, puzzle.AblyPuzzle())
+}
+
+func (s *Server) RestoreRound(c echo.Context) error {
+	var id IDParams
+	if err := c.Bind(&id); err != nil {
+		return err
+	}
+	round, chid, err := s.state.RestoreRound(c.Request().Context(), id.ID)
+	if err != nil {
+		return err
+	}
+	SetChangeIDHeader(c, chid)
+	return c.JSON(http.StatusOK, round)
+}
@@ -0,0 +1,30 @@
+package state
+
+import "context"
+
+// An Actor identifies who made a change: either the Discord user ID of a
+// human, or one of the automated actors below.
+type Actor string
+
+const (
+	ActorDiscovery Actor = "discovery"
+	ActorSync      Actor = "sync"
+)
+
+type actorKey struct{}
+
+// WithActor attaches an actor to the context. Mutations made with the returned
+// context are attributed to that actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
+
+// IsUser returns true if the actor is a human (a Discord user).
+func (a Actor) IsUser() bool {
+	return a != "" && a != ActorDiscovery && a != ActorSync
+}
@@ -0,0 +1,153 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/mattn/go-sqlite3"
+	"golang.org/x/xerrors"
+)
+
+const snapshotPattern = "db-*.sqlite"
+
+// Backup writes a consistent copy of the database to the given path. Writes are
+// paused while the copy is made.
+func (c *Client) Backup(ctx context.Context, path string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.storage.Backup(ctx, path)
+}
+
+// Snapshotter backs up the database into dir every interval, keeping the most
+// recent snapshots and deleting the rest. Snapshots are named by their UTC
+// timestamp, so they sort chronologically.
+func (c *Client) Snapshotter(ctx context.Context, dir string, interval time.Duration, keep int) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("state: failed to create snapshot directory: %v", err)
+		return
+	}
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var name = "db-" + time.Now().UTC().Format("20060102T150405Z") + ".sqlite"
+		if err := c.Backup(ctx, filepath.Join(dir, name)); err != nil {
+			log.Printf("state: failed to write snapshot: %v", err)
+			continue
+		}
+		snapshots, err := ListSnapshots(dir)
+		if err != nil {
+			log.Printf("state: failed to list snapshots: %v", err)
+			continue
+		}
+		for len(snapshots) > keep {
+			if err := os.Remove(snapshots[0]); err != nil {
+				log.Printf("state: failed to remove snapshot: %v", err)
+			}
+			snapshots = snapshots[1:]
+		}
+	}
+}
+
+// ListSnapshots returns the paths of the snapshots in dir, oldest first.
+func ListSnapshots(dir string) ([]string, error) {
+	snapshots, err := filepath.Glob(filepath.Join(dir, snapshotPattern))
+	if err != nil {
+		return nil, xerrors.Errorf("Glob: %w", err)
+	}
+	sort.Strings(snapshots)
+	return snapshots, nil
+}
+
+// Restore replaces the database at path with a copy of the given snapshot. It
+// must be called before the database is opened with New. The existing
+// database, if any, is moved aside rather than deleted.
+func Restore(ctx context.Context, path, snapshot string) error {
+	src, err := sql.Open("sqlite3", "file:"+snapshot+"?mode=ro")
+	if err != nil {
+		return xerrors.Errorf("sql.Open: %w", err)
+	}
+	defer src.Close()
+
+	var result string
+	err = src.QueryRowContext(ctx, "PRAGMA quick_check").Scan(&result)
+	if err != nil {
+		return xerrors.Errorf("quick_check %q: %w", snapshot, err)
+	} else if result != "ok" {
+		return xerrors.Errorf("snapshot %q is corrupt: %s", snapshot, result)
+	}
+
+	var suffix = ".pre-restore-" + time.Now().UTC().Format("20060102T150405Z")
+	for _, file := range []string{path, path + "-journal", path + "-wal", path + "-shm"} {
+		if err := os.Rename(file, file+suffix); err != nil && !os.IsNotExist(err) {
+			return xerrors.Errorf("Rename: %w", err)
+		}
+	}
+	return copyDatabase(ctx, src, path)
+}
+
+// Copies the main database from src into a new file at dest. The copy is
+// written to a temporary file and renamed into place, so a partial copy is
+// never left behind.
+func copyDatabase(ctx context.Context, src *sql.DB, dest string) error {
+	var tmp = dest + ".tmp"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return xerrors.Errorf("Remove: %w", err)
+	}
+	if err := backup(ctx, src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return xerrors.Errorf("Rename: %w", err)
+	}
+	return nil
+}
+
+func backup(ctx context.Context, src *sql.DB, dest string) error {
+	dst, err := sql.Open("sqlite3", dest)
+	if err != nil {
+		return xerrors.Errorf("sql.Open: %w", err)
+	}
+	defer dst.Close()
+
+	srcConn, err := src.Conn(ctx)
+	if err != nil {
+		return xerrors.Errorf("Conn: %w", err)
+	}
+	defer srcConn.Close()
+	dstConn, err := dst.Conn(ctx)
+	if err != nil {
+		return xerrors.Errorf("Conn: %w", err)
+	}
+	defer dstConn.Close()
+
+	return dstConn.Raw(func(dc any) error {
+		return srcConn.Raw(func(sc any) error {
+			backup, err := dc.(*sqlite3.SQLiteConn).Backup("main", sc.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return xerrors.Errorf("Backup: %w", err)
+			}
+			// Copy all pages in a single step, which holds a read lock on the
+			// source for the duration and guarantees a consistent copy.
+			if _, err := backup.Step(-1); err != nil {
+				backup.Finish()
+				return xerrors.Errorf("Step: %w", err)
+			}
+			if err := backup.Finish(); err != nil {
+				return xerrors.Errorf("Finish: %w", err)
+			}
+			return nil
+		})
+	})
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func (c *Client) Changes(ctx context.Context) ([]AblySyncMessage, error) {
-	changes, err := c.queries.ListChangelog(ctx)
+	changes, err := c.queries(ctx).ListChangelog(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf("ListChangelog: %w", err)
 	}
@@ -18,6 +18,7 @@ func (c *Client) Changes(ctx context.Context) ([]AblySyncMessage, error) {
 		var msg = AblySyncMessage{
 			ChangeID: change.ID,
 			Kind:     change.Kind,
+			Actor:    Actor(change.Actor),
 		}
 		if change.Puzzle != nil {
 			err := json.Unmarshal(change.Puzzle, &msg.Puzzle)
@@ -31,65 +32,109 @@ func (c *Client) Changes(ctx context.Context) ([]AblySyncMessage, error) {
 				return nil, err
 			}
 		}
+		if change.Feed != nil {
+			err := json.Unmarshal(change.Feed, &msg.Feed)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if change.Guess != nil {
+			err := json.Unmarshal(change.Guess, &msg.Guess)
+			if err != nil {
+				return nil, err
+			}
+		}
 		result = append(result, msg)
 	}
 	return result, nil
 }
 
+// The changelog, the syncer and the Ably channel all serve the active hunt, so
+// changes to other hunts aren't published. They're returned with a ChangeID of
+// zero.
+func (c *Client) publishes(hunt int64) bool {
+	return hunt == c.ActiveHunt()
+}
+
 func (c *Client) LogPuzzleChange(ctx context.Context, before *Puzzle,
 	after *Puzzle, complete chan error) (PuzzleChange, error) {
 
+	return c.logPuzzleChange(ctx, PuzzleChange{
+		Before: before, After: after, BotComplete: complete,
+	})
+}
+
+// Fills in the change's Actor and ChangeID, records it in the changelog and
+// sends it to the syncer once the transaction commits.
+func (c *Client) logPuzzleChange(ctx context.Context, change PuzzleChange) (PuzzleChange, error) {
+	change.Actor = ActorFromContext(ctx)
+	var puzzle = change.After
+	if puzzle == nil {
+		puzzle = change.Before
+	}
+	if !c.publishes(puzzle.Round.Hunt) {
+		return change, nil
+	}
 	c.changeID += 1
+	change.ChangeID = c.changeID
 
-	var change = PuzzleChange{before, after, c.changeID, complete}
 	var msg = change.SyncMessage()
 
 	encoded, err := json.Marshal(msg.Puzzle)
 	if err != nil {
 		return PuzzleChange{}, xerrors.Errorf("Marshal: %w", err)
 	}
-	err = c.queries.CreateChangelog(ctx, db.CreateChangelogParams{
+	err = c.queries(ctx).CreateChangelog(ctx, db.CreateChangelogParams{
 		ID:     change.ChangeID,
 		Kind:   msg.Kind,
 		Puzzle: encoded,
+		Actor:  string(change.Actor),
 	})
 	if err != nil {
 		return PuzzleChange{}, xerrors.Errorf("CreateChangelog: %w", err)
 	}
-	err = c.queries.PruneChangelog(ctx)
+	err = c.queries(ctx).PruneChangelog(ctx)
 	if err != nil {
 		return PuzzleChange{}, xerrors.Errorf("PruneChangelog: %w", err)
 	}
 
-	c.PuzzleRoundChange <- change
+	c.notify(ctx, change)
 	return change, nil
 }
 
 func (c *Client) LogRoundChange(ctx context.Context, before *Round,
 	after *Round) (RoundChange, error) {
 
+	var round = after
+	if round == nil {
+		round = before
+	}
+	if !c.publishes(round.Hunt) {
+		return RoundChange{before, after, ActorFromContext(ctx), 0}, nil
+	}
 	c.changeID += 1
 
-	var change = RoundChange{before, after, c.changeID}
+	var change = RoundChange{before, after, ActorFromContext(ctx), c.changeID}
 	var msg = change.SyncMessage()
 
 	encoded, err := json.Marshal(msg.Puzzle)
 	if err != nil {
 		return RoundChange{}, xerrors.Errorf("Marshal: %w", err)
 	}
-	err = c.queries.CreateChangelog(ctx, db.CreateChangelogParams{
+	err = c.queries(ctx).CreateChangelog(ctx, db.CreateChangelogParams{
 		ID:    change.ChangeID,
 		Kind:  msg.Kind,
 		Round: encoded,
+		Actor: string(change.Actor),
 	})
 	if err != nil {
 		return RoundChange{}, xerrors.Errorf("CreateChangelog: %w", err)
 	}
-	err = c.queries.PruneChangelog(ctx)
+	err = c.queries(ctx).PruneChangelog(ctx)
 	if err != nil {
 		return RoundChange{}, xerrors.Errorf("PruneChangelog: %w", err)
 	}
 
-	c.PuzzleRoundChange <- change
+	c.notify(ctx, change)
 	return change, nil
 }
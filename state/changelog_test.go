@@ -0,0 +1,163 @@
+package state
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChangeIDs(t *testing.T) {
+	ctx, c, round := newTestHunt(t) // change 3001
+	puzzle, first, err := c.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if first != 3002 {
+		t.Errorf("CreatePuzzle: got change ID %d, want 3002", first)
+	}
+
+	// A failed write doesn't use up a change ID
+	if _, _, err := c.UpdatePuzzle(ctx, puzzle.ID, AnyVersion,
+		func(p *RawPuzzle) error { p.Name = ""; return nil }); err == nil {
+		t.Fatal("UpdatePuzzle: want ValidationError")
+	}
+	_, second, err := c.UpdatePuzzle(ctx, puzzle.ID, AnyVersion,
+		func(p *RawPuzzle) error { p.Note = "note"; return nil })
+	if err != nil {
+		t.Fatal(err)
+	} else if second != first+1 {
+		t.Errorf("UpdatePuzzle: got change ID %d, want %d", second, first+1)
+	}
+
+	// A new client picks up where the last one left off
+	if last, err := c.storage.GetLastChangeID(ctx); err != nil {
+		t.Fatal(err)
+	} else if last != second {
+		t.Errorf("GetLastChangeID: got %v, want %d", last, second)
+	}
+	var reopened = NewWithStorage(ctx, c.storage)
+	if third, err := reopened.DeletePuzzle(ctx, puzzle.ID); err != nil {
+		t.Fatal(err)
+	} else if third != second+1 {
+		t.Errorf("DeletePuzzle: got change ID %d, want %d", third, second+1)
+	}
+}
+
+func TestPuzzleRoundChange(t *testing.T) {
+	ctx, c, round := newTestHunt(t)
+	puzzle, _, err := c.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.UpdatePuzzle(ctx, puzzle.ID, AnyVersion,
+		func(p *RawPuzzle) error { p.Note = "note"; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	// Failed writes aren't announced
+	if _, _, err := c.UpdatePuzzle(ctx, puzzle.ID, AnyVersion,
+		func(p *RawPuzzle) error { p.Name = ""; return nil }); err == nil {
+		t.Fatal("UpdatePuzzle: want ValidationError")
+	}
+	if _, err := c.DeletePuzzle(ctx, puzzle.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []PuzzleRoundChange
+	for len(c.PuzzleRoundChange) > 0 {
+		changes = append(changes, <-c.PuzzleRoundChange)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("got %d changes, want 4: %#v", len(changes), changes)
+	}
+	if chg, ok := changes[0].(RoundChange); !ok || chg.Before != nil ||
+		chg.After == nil || chg.After.Name != "Fruit" || chg.Actor != ActorSync {
+		t.Errorf("create round: got %#v", changes[0])
+	}
+	if chg, ok := changes[1].(PuzzleChange); !ok || chg.Before != nil ||
+		chg.After == nil || chg.After.Name != "Apples" {
+		t.Errorf("create puzzle: got %#v", changes[1])
+	}
+	if chg, ok := changes[2].(PuzzleChange); !ok || chg.Before == nil ||
+		chg.Before.Note != "" || chg.After == nil || chg.After.Note != "note" {
+		t.Errorf("update puzzle: got %#v", changes[2])
+	}
+	if chg, ok := changes[3].(PuzzleChange); !ok || chg.Before == nil || chg.After != nil {
+		t.Errorf("delete puzzle: got %#v", changes[3])
+	}
+	var last int64
+	for i, change := range changes {
+		var id int64
+		switch chg := change.(type) {
+		case RoundChange:
+			id = chg.ChangeID
+		case PuzzleChange:
+			id = chg.ChangeID
+		}
+		if id <= last {
+			t.Errorf("change %d: got ID %d, want more than %d", i, id, last)
+		}
+		last = id
+	}
+}
+
+func TestOtherHuntsNotPublished(t *testing.T) {
+	ctx, c, _ := newTestHunt(t)
+	<-c.PuzzleRoundChange // the active hunt's round
+
+	practice, err := c.CreateHunt(ctx, "Practice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = WithHunt(ctx, practice.ID)
+	if c.IsActive(ctx) {
+		t.Fatal("IsActive: got true for the practice hunt")
+	}
+	round, chid, err := c.CreateRound(ctx, Round{Name: "Fruit", Emoji: "🍎", DriveFolder: "folder"})
+	if err != nil {
+		t.Fatal(err)
+	} else if chid != 0 {
+		t.Errorf("CreateRound: got change ID %d, want 0", chid)
+	}
+	puzzle, chid, err := c.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if chid != 0 {
+		t.Errorf("CreatePuzzle: got change ID %d, want 0", chid)
+	}
+	meta, _, err := c.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Orchard", Round: round.ID, PuzzleURL: "https://example.com/orchard", Meta: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chid, err := c.CreateFeed(ctx, PuzzleFeed{Puzzle: puzzle.ID, Meta: meta.ID}); err != nil {
+		t.Fatal(err)
+	} else if chid != 0 {
+		t.Errorf("CreateFeed: got change ID %d, want 0", chid)
+	}
+	if _, _, err := c.UpdateRound(ctx, round.ID, AnyVersion,
+		func(r *Round) error { r.Hue = 10; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UpdateSettings(ctx, map[string]json.RawMessage{
+		"discovery_enabled":   json.RawMessage("true"),
+		"solver_idle_minutes": json.RawMessage("10"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(c.PuzzleRoundChange); n > 0 {
+		t.Errorf("PuzzleRoundChange: got %d changes, want none", n)
+	} else if n := len(c.DiscoveryChange) + len(c.SettingsChange); n > 0 {
+		t.Errorf("DiscoveryChange, SettingsChange: got %d signals, want none", n)
+	}
+	if changes, err := c.Changes(ctx); err != nil {
+		t.Fatal(err)
+	} else if len(changes) != 1 {
+		t.Errorf("Changes: got %d entries, want 1", len(changes))
+	}
+}
@@ -2,16 +2,13 @@ package state
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
-	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "embed"
 
-	"github.com/emojihunt/emojihunt/state/db"
 	"github.com/labstack/gommon/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -37,37 +34,36 @@ type Client struct {
 	// Important: to avoid deadlocks, do not send to this channel while holding
 	// the lock below.
 	DiscoveryChange   chan bool
+	SettingsChange    chan bool
 	PuzzleRoundChange chan PuzzleRoundChange
 	LiveMessage       chan LiveMessage
 
-	queries  *db.Queries
+	storage  Storage
 	mutex    sync.Mutex // used to serialize database writes
 	changeID int64      // must hold mutex when reading/writing
+
+	activeHunt atomic.Int64
 }
 
+// New opens the SQLite database at the given path. It panics if the database
+// can't be opened or migrated.
 func New(ctx context.Context, path string) *Client {
-	_, err := os.Stat(path)
-	shouldInitialize := errors.Is(err, os.ErrNotExist)
-
-	dbx, err := sql.Open("sqlite3", path+"?_fk=on")
+	storage, err := OpenSQLite(ctx, path)
 	if err != nil {
-		panic(xerrors.Errorf("sql.Open: %w", err))
-	}
-	if err := dbx.PingContext(ctx); err != nil {
-		panic(xerrors.Errorf("PingContext: %w", err))
-	}
-	if shouldInitialize {
-		if _, err := dbx.ExecContext(ctx, db.DDL); err != nil {
-			panic(xerrors.Errorf("ExecContext(ctx, ddl): %w", err))
-		}
+		panic(err)
 	}
+	return NewWithStorage(ctx, storage)
+}
+
+func NewWithStorage(ctx context.Context, storage Storage) *Client {
 	var client = Client{
 		DiscoveryChange:   make(chan bool, 8),
+		SettingsChange:    make(chan bool, 8),
 		PuzzleRoundChange: make(chan PuzzleRoundChange, 256),
 		LiveMessage:       make(chan LiveMessage, 256),
-		queries:           db.New(dbx),
+		storage:           storage,
 	}
-	raw, err := client.queries.GetLastChangeID(ctx)
+	raw, err := client.storage.GetLastChangeID(ctx)
 	if err != nil {
 		panic(xerrors.Errorf("GetLastChangeID"))
 	} else if raw == nil {
@@ -75,6 +71,11 @@ func New(ctx context.Context, path string) *Client {
 	} else {
 		client.changeID = raw.(int64)
 	}
+	hunt, err := client.storage.GetActiveHunt(ctx)
+	if err != nil {
+		panic(xerrors.Errorf("GetActiveHunt: %w", err))
+	}
+	client.activeHunt.Store(hunt.ID)
 
 	return &client
 }
@@ -88,12 +89,27 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("%s %s", e.Field, e.Message)
 }
 
+// AnyVersion can be passed to UpdatePuzzle and UpdateRound to skip the version
+// check and apply the update unconditionally. Row versions start at 1.
+const AnyVersion int64 = 0
+
+// ConflictError is returned when an update's expected version doesn't match
+// the version in the database, i.e. someone else has changed the object since
+// the caller last read it. Current holds the latest copy of the object.
+type ConflictError struct {
+	Current any
+}
+
+func (e ConflictError) Error() string {
+	return "object has been modified concurrently"
+}
+
 func (c *Client) HandleMetrics() {
 	for {
 		c.mutex.Lock()
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 
-		stats, err := c.queries.CountPuzzles(ctx)
+		stats, err := c.queries(ctx).CountPuzzles(ctx, c.ActiveHunt())
 		if err != nil {
 			log.Printf("state: CountPuzzles: %#v", err)
 		} else {
@@ -103,7 +119,7 @@ func (c *Client) HandleMetrics() {
 			}
 		}
 
-		rounds, err := c.queries.CountRounds(ctx)
+		rounds, err := c.queries(ctx).CountRounds(ctx, c.ActiveHunt())
 		if err != nil {
 			log.Printf("state: CountRounds: %#v", err)
 		} else {
@@ -0,0 +1,1636 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Memory is an in-memory Querier, for tests. Each table is kept as a map, and
+// the schema's constraints (uniqueness, foreign keys, cascading deletes) and
+// the queries' filtering and ordering are reproduced by hand. Full-text search
+// is approximated with prefix matching on words, and ranks are made up.
+//
+// Like the SQL queries, Memory is safe for concurrent use. Transactions are
+// serialized with each other, but reads made outside of a transaction can see
+// its uncommitted writes.
+type Memory struct {
+	mutex sync.Mutex // guards tables
+	tx    sync.Mutex // held for the duration of a transaction
+	tables
+}
+
+type tables struct {
+	hunts             map[int64]Hunt
+	rounds            map[int64]Round
+	puzzles           map[int64]Puzzle
+	tags              map[PuzzleTag]bool
+	solvers           map[solverKey]PuzzleSolver
+	feeds             map[PuzzleFeed]bool
+	guesses           map[int64]Guess
+	reminders         map[int64]Reminder
+	history           map[int64]PuzzleHistory
+	changelog         map[int64]Changelog
+	settings          map[settingKey][]byte
+	messages          map[int64]SearchMessage
+	discoveredRounds  map[int64]DiscoveredRound
+	discoveredPuzzles map[int64]DiscoveredPuzzle
+	discoveredChanges map[int64]DiscoveredChange
+}
+
+type solverKey struct {
+	puzzle int64
+	solver string
+}
+
+type settingKey struct {
+	hunt int64
+	key  string
+}
+
+var _ Querier = (*Memory)(nil)
+
+// NewMemory returns an empty database in the state left by the migrations: it
+// holds a single, active hunt.
+func NewMemory() *Memory {
+	return &Memory{tables: tables{
+		hunts: map[int64]Hunt{
+			1: {ID: 1, Name: "Mystery Hunt", Active: true, CreatedAt: time.Now()},
+		},
+		rounds:            make(map[int64]Round),
+		puzzles:           make(map[int64]Puzzle),
+		tags:              make(map[PuzzleTag]bool),
+		solvers:           make(map[solverKey]PuzzleSolver),
+		feeds:             make(map[PuzzleFeed]bool),
+		guesses:           make(map[int64]Guess),
+		reminders:         make(map[int64]Reminder),
+		history:           make(map[int64]PuzzleHistory),
+		changelog:         make(map[int64]Changelog),
+		settings:          make(map[settingKey][]byte),
+		messages:          make(map[int64]SearchMessage),
+		discoveredRounds:  make(map[int64]DiscoveredRound),
+		discoveredPuzzles: make(map[int64]DiscoveredPuzzle),
+		discoveredChanges: make(map[int64]DiscoveredChange),
+	}}
+}
+
+// Transaction runs fn with exclusive use of the database. If fn returns an
+// error, the tables are restored to their state from before the call.
+func (m *Memory) Transaction(ctx context.Context, fn func(q Querier) error) error {
+	m.tx.Lock()
+	defer m.tx.Unlock()
+
+	m.mutex.Lock()
+	var snapshot = m.tables.clone()
+	m.mutex.Unlock()
+
+	if err := fn(m); err != nil {
+		m.mutex.Lock()
+		m.tables = snapshot
+		m.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Values are copied on write, so the rows can be shared.
+func (t tables) clone() tables {
+	return tables{
+		hunts:             maps.Clone(t.hunts),
+		rounds:            maps.Clone(t.rounds),
+		puzzles:           maps.Clone(t.puzzles),
+		tags:              maps.Clone(t.tags),
+		solvers:           maps.Clone(t.solvers),
+		feeds:             maps.Clone(t.feeds),
+		guesses:           maps.Clone(t.guesses),
+		reminders:         maps.Clone(t.reminders),
+		history:           maps.Clone(t.history),
+		changelog:         maps.Clone(t.changelog),
+		settings:          maps.Clone(t.settings),
+		messages:          maps.Clone(t.messages),
+		discoveredRounds:  maps.Clone(t.discoveredRounds),
+		discoveredPuzzles: maps.Clone(t.discoveredPuzzles),
+		discoveredChanges: maps.Clone(t.discoveredChanges),
+	}
+}
+
+// The errors SQLite would return, so that callers can inspect them the same
+// way.
+var (
+	errUnique     = sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}
+	errPrimaryKey = sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintPrimaryKey}
+	errForeignKey = sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintForeignKey}
+)
+
+// Rows get the next ID after the largest one in use, like an INTEGER PRIMARY
+// KEY without AUTOINCREMENT.
+func nextID[V any](table map[int64]V) int64 {
+	var id int64
+	for key := range table {
+		id = max(id, key)
+	}
+	return id + 1
+}
+
+func sorted[K comparable, V any](table map[K]V, cmp func(a, b V) int) []V {
+	var rows = slices.Collect(maps.Values(table))
+	slices.SortFunc(rows, cmp)
+	return rows
+}
+
+func byID[V any](id func(V) int64) func(a, b V) int {
+	return func(a, b V) int { return cmp.Compare(id(a), id(b)) }
+}
+
+// Implements SQLite's NOCASE collation, which only folds ASCII letters.
+func nocase(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return r
+	}, s)
+}
+
+func (m *Memory) ActivateHunt(ctx context.Context, id int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hunt, ok := m.hunts[id]
+	if !ok || hunt.Archived {
+		return 0, nil
+	}
+	for _, other := range m.hunts {
+		if other.Active && other.ID != id {
+			return 0, errUnique
+		}
+	}
+	hunt.Active = true
+	m.hunts[id] = hunt
+	return 1, nil
+}
+
+func (m *Memory) CreateHunt(ctx context.Context, arg CreateHuntParams) (Hunt, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var hunt = Hunt{
+		ID:        nextID(m.hunts),
+		Name:      arg.Name,
+		CreatedAt: arg.CreatedAt,
+	}
+	m.hunts[hunt.ID] = hunt
+	return hunt, nil
+}
+
+func (m *Memory) DeactivateHunts(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for id, hunt := range m.hunts {
+		hunt.Active = false
+		m.hunts[id] = hunt
+	}
+	return nil
+}
+
+func (m *Memory) GetActiveHunt(ctx context.Context) (Hunt, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, hunt := range m.hunts {
+		if hunt.Active {
+			return hunt, nil
+		}
+	}
+	return Hunt{}, sql.ErrNoRows
+}
+
+func (m *Memory) GetHunt(ctx context.Context, id int64) (Hunt, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hunt, ok := m.hunts[id]
+	if !ok {
+		return Hunt{}, sql.ErrNoRows
+	}
+	return hunt, nil
+}
+
+func (m *Memory) ListHunts(ctx context.Context) ([]Hunt, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return sorted(m.hunts, byID(func(h Hunt) int64 { return h.ID })), nil
+}
+
+func (m *Memory) UpdateHunt(ctx context.Context, arg UpdateHuntParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hunt, ok := m.hunts[arg.ID]
+	if !ok {
+		return nil
+	}
+	hunt.Name, hunt.Archived = arg.Name, arg.Archived
+	m.hunts[arg.ID] = hunt
+	return nil
+}
+
+func (m *Memory) checkRound(round Round) error {
+	if _, ok := m.hunts[round.Hunt]; !ok {
+		return errForeignKey
+	}
+	if round.DeletedAt.Valid {
+		return nil
+	}
+	for _, other := range m.rounds {
+		if other.ID == round.ID || other.Hunt != round.Hunt || other.DeletedAt.Valid {
+			continue
+		} else if other.Name == round.Name || other.Emoji == round.Emoji {
+			return errUnique
+		}
+	}
+	return nil
+}
+
+// Implements ORDER BY special DESC, sort, id
+func compareRounds(a, b Round) int {
+	if a.Special != b.Special {
+		if a.Special {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Or(cmp.Compare(a.Sort, b.Sort), cmp.Compare(a.ID, b.ID))
+}
+
+func (m *Memory) CountRounds(ctx context.Context, hunt int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for _, round := range m.rounds {
+		if !round.Special && !round.DeletedAt.Valid && round.Hunt == hunt {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) CreateRound(ctx context.Context, arg CreateRoundParams) (Round, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var round = Round{
+		ID:              nextID(m.rounds),
+		Name:            arg.Name,
+		Emoji:           arg.Emoji,
+		Hue:             arg.Hue,
+		Sort:            arg.Sort,
+		Special:         arg.Special,
+		DriveFolder:     arg.DriveFolder,
+		DiscordCategory: arg.DiscordCategory,
+		Version:         1,
+		Hunt:            arg.Hunt,
+	}
+	if err := m.checkRound(round); err != nil {
+		return Round{}, err
+	}
+	m.rounds[round.ID] = round
+	return round, nil
+}
+
+func (m *Memory) GetCreatedRound(ctx context.Context, arg GetCreatedRoundParams) (Round, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, round := range sorted(m.rounds, byID(func(r Round) int64 { return r.ID })) {
+		if round.Hunt == arg.Hunt && nocase(round.Name) == nocase(arg.Name) &&
+			!round.DeletedAt.Valid {
+			return round, nil
+		}
+	}
+	return Round{}, sql.ErrNoRows
+}
+
+func (m *Memory) GetRound(ctx context.Context, id int64) (Round, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	round, ok := m.rounds[id]
+	if !ok || round.DeletedAt.Valid {
+		return Round{}, sql.ErrNoRows
+	}
+	return round, nil
+}
+
+func (m *Memory) ListRounds(ctx context.Context, hunt int64) ([]Round, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rounds []Round
+	for _, round := range sorted(m.rounds, compareRounds) {
+		if !round.DeletedAt.Valid && round.Hunt == hunt {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds, nil
+}
+
+func (m *Memory) ListTrashedRounds(ctx context.Context, hunt int64) ([]Round, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rounds []Round
+	for _, round := range sorted(m.rounds, func(a, b Round) int {
+		return b.DeletedAt.Time.Compare(a.DeletedAt.Time)
+	}) {
+		if round.DeletedAt.Valid && round.Hunt == hunt {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds, nil
+}
+
+func (m *Memory) PurgeTrashedRounds(ctx context.Context, hunt int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var inUse = make(map[int64]bool)
+	for _, puzzle := range m.puzzles {
+		inUse[puzzle.Round] = true
+	}
+	var count int64
+	for id, round := range m.rounds {
+		if round.DeletedAt.Valid && round.Hunt == hunt && !inUse[id] {
+			delete(m.rounds, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) RestoreRound(ctx context.Context, id int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	round, ok := m.rounds[id]
+	if !ok || !round.DeletedAt.Valid {
+		return 0, nil
+	}
+	round.DeletedAt = sql.NullTime{}
+	if err := m.checkRound(round); err != nil {
+		return 0, err
+	}
+	round.Version++
+	m.rounds[id] = round
+	return 1, nil
+}
+
+func (m *Memory) TrashRound(ctx context.Context, arg TrashRoundParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	round, ok := m.rounds[arg.ID]
+	if !ok || round.DeletedAt.Valid {
+		return 0, nil
+	}
+	round.DeletedAt = arg.DeletedAt
+	round.Version++
+	m.rounds[arg.ID] = round
+	return 1, nil
+}
+
+func (m *Memory) UpdateRound(ctx context.Context, arg UpdateRoundParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.rounds[arg.ID]; !ok {
+		return nil
+	}
+	var round = Round(arg)
+	if err := m.checkRound(round); err != nil {
+		return err
+	}
+	m.rounds[arg.ID] = round
+	return nil
+}
+
+// The columns returned by the puzzle queries; each query has its own
+// (identical) row type.
+type puzzleRow = ListPuzzlesRow
+
+func (m *Memory) checkPuzzle(puzzle Puzzle) error {
+	if _, ok := m.rounds[puzzle.Round]; !ok {
+		return errForeignKey
+	}
+	if puzzle.DeletedAt.Valid {
+		return nil
+	}
+	for _, other := range m.puzzles {
+		if other.ID != puzzle.ID && other.Round == puzzle.Round &&
+			!other.DeletedAt.Valid && nocase(other.Name) == nocase(puzzle.Name) {
+			return errUnique
+		}
+	}
+	return nil
+}
+
+func (m *Memory) puzzleRow(puzzle Puzzle) puzzleRow {
+	var tags []string
+	for tag := range m.tags {
+		if tag.Puzzle == puzzle.ID {
+			tags = append(tags, tag.Tag)
+		}
+	}
+	slices.Sort(tags)
+	var solvers []string
+	for _, solver := range sorted(m.solvers, compareSolvers) {
+		if solver.Puzzle == puzzle.ID {
+			solvers = append(solvers, solver.Solver)
+		}
+	}
+	return puzzleRow{
+		ID:             puzzle.ID,
+		Name:           puzzle.Name,
+		Answer:         puzzle.Answer,
+		Round:          m.rounds[puzzle.Round],
+		Status:         puzzle.Status,
+		Note:           puzzle.Note,
+		Location:       puzzle.Location,
+		PuzzleURL:      puzzle.PuzzleURL,
+		SpreadsheetID:  puzzle.SpreadsheetID,
+		DiscordChannel: puzzle.DiscordChannel,
+		Meta:           puzzle.Meta,
+		VoiceRoom:      puzzle.VoiceRoom,
+		Reminder:       puzzle.Reminder,
+		Priority:       puzzle.Priority,
+		NeedsHelp:      puzzle.NeedsHelp,
+		Version:        puzzle.Version,
+		Tags:           strings.Join(tags, ","),
+		Solvers:        strings.Join(solvers, ","),
+	}
+}
+
+// Implements ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta,
+// p.name COLLATE nocase
+func (m *Memory) comparePuzzles(a, b Puzzle) int {
+	if c := compareRounds(m.rounds[a.Round], m.rounds[b.Round]); c != 0 {
+		return c
+	} else if a.Meta != b.Meta {
+		if a.Meta {
+			return 1
+		}
+		return -1
+	}
+	return cmp.Compare(nocase(a.Name), nocase(b.Name))
+}
+
+// Returns the live puzzles matching the filter, in the standard order.
+func (m *Memory) listPuzzles(filter func(p Puzzle) bool) []puzzleRow {
+	var rows []puzzleRow
+	for _, puzzle := range sorted(m.puzzles, m.comparePuzzles) {
+		if !puzzle.DeletedAt.Valid && filter(puzzle) {
+			rows = append(rows, m.puzzleRow(puzzle))
+		}
+	}
+	return rows
+}
+
+func (m *Memory) CheckPuzzleIsCreated(ctx context.Context, arg CheckPuzzleIsCreatedParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for _, puzzle := range m.puzzles {
+		if m.rounds[puzzle.Round].Hunt == arg.Hunt && (puzzle.Name == arg.Name ||
+			nocase(puzzle.PuzzleURL) == nocase(arg.PuzzleURL)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) ClearPuzzleVoiceRoom(ctx context.Context, voiceRoom string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for id, puzzle := range m.puzzles {
+		if puzzle.VoiceRoom == voiceRoom && !puzzle.DeletedAt.Valid {
+			puzzle.VoiceRoom = ""
+			puzzle.Version++
+			m.puzzles[id] = puzzle
+		}
+	}
+	return nil
+}
+
+func (m *Memory) CountPuzzles(ctx context.Context, hunt int64) (CountPuzzlesRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var result CountPuzzlesRow
+	for _, puzzle := range m.puzzles {
+		if !puzzle.DeletedAt.Valid && m.rounds[puzzle.Round].Hunt == hunt {
+			result.Total++
+			if puzzle.Answer != "" {
+				result.Solved.Float64++
+			}
+		}
+	}
+	result.Solved.Valid = result.Total > 0 // SUM() of no rows is NULL
+	return result, nil
+}
+
+func (m *Memory) CountPuzzlesInRound(ctx context.Context, round int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for _, puzzle := range m.puzzles {
+		if puzzle.Round == round && !puzzle.DeletedAt.Valid {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) CreatePuzzle(ctx context.Context, arg CreatePuzzleParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var puzzle = Puzzle{
+		ID:             nextID(m.puzzles),
+		Name:           arg.Name,
+		Answer:         arg.Answer,
+		Round:          arg.Round,
+		Status:         arg.Status,
+		Note:           arg.Note,
+		Location:       arg.Location,
+		PuzzleURL:      arg.PuzzleURL,
+		SpreadsheetID:  arg.SpreadsheetID,
+		DiscordChannel: arg.DiscordChannel,
+		Meta:           arg.Meta,
+		VoiceRoom:      arg.VoiceRoom,
+		Reminder:       arg.Reminder,
+		Priority:       arg.Priority,
+		NeedsHelp:      arg.NeedsHelp,
+		Version:        1,
+	}
+	if err := m.checkPuzzle(puzzle); err != nil {
+		return 0, err
+	}
+	m.puzzles[puzzle.ID] = puzzle
+	return puzzle.ID, nil
+}
+
+func (m *Memory) GetPuzzle(ctx context.Context, id int64) (GetPuzzleRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	puzzle, ok := m.puzzles[id]
+	if !ok || puzzle.DeletedAt.Valid {
+		return GetPuzzleRow{}, sql.ErrNoRows
+	}
+	return GetPuzzleRow(m.puzzleRow(puzzle)), nil
+}
+
+func (m *Memory) GetPuzzleByChannel(ctx context.Context, discordChannel string) (GetPuzzleByChannelRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows = m.listPuzzles(func(p Puzzle) bool {
+		return p.DiscordChannel == discordChannel
+	})
+	if len(rows) == 0 {
+		return GetPuzzleByChannelRow{}, sql.ErrNoRows
+	}
+	return GetPuzzleByChannelRow(rows[0]), nil
+}
+
+func (m *Memory) GetPuzzlesByVoiceRoom(ctx context.Context, voiceRoom string) ([]GetPuzzlesByVoiceRoomRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []GetPuzzlesByVoiceRoomRow
+	for _, row := range m.listPuzzles(func(p Puzzle) bool {
+		return p.VoiceRoom == voiceRoom
+	}) {
+		rows = append(rows, GetPuzzlesByVoiceRoomRow(row))
+	}
+	return rows, nil
+}
+
+func (m *Memory) ListPuzzles(ctx context.Context, hunt int64) ([]ListPuzzlesRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.listPuzzles(func(p Puzzle) bool {
+		return m.rounds[p.Round].Hunt == hunt
+	}), nil
+}
+
+func (m *Memory) ListPuzzlesByRound(ctx context.Context, round int64) ([]ListPuzzlesByRoundRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []ListPuzzlesByRoundRow
+	for _, row := range m.listPuzzles(func(p Puzzle) bool {
+		return p.Round == round
+	}) {
+		rows = append(rows, ListPuzzlesByRoundRow(row))
+	}
+	return rows, nil
+}
+
+func (m *Memory) ListPuzzlesByTag(ctx context.Context, arg ListPuzzlesByTagParams) ([]ListPuzzlesByTagRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []ListPuzzlesByTagRow
+	for _, row := range m.listPuzzles(func(p Puzzle) bool {
+		return m.tags[PuzzleTag{p.ID, arg.Tag}] && m.rounds[p.Round].Hunt == arg.Hunt
+	}) {
+		rows = append(rows, ListPuzzlesByTagRow(row))
+	}
+	return rows, nil
+}
+
+func (m *Memory) ListPuzzlesByVoiceRoom(ctx context.Context) ([]ListPuzzlesByVoiceRoomRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []ListPuzzlesByVoiceRoomRow
+	for _, row := range m.listPuzzles(func(p Puzzle) bool {
+		return p.VoiceRoom != ""
+	}) {
+		rows = append(rows, ListPuzzlesByVoiceRoomRow{
+			ID: row.ID, Name: row.Name, VoiceRoom: row.VoiceRoom,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b ListPuzzlesByVoiceRoomRow) int {
+		return cmp.Compare(a.VoiceRoom, b.VoiceRoom)
+	})
+	return rows, nil
+}
+
+func (m *Memory) ListTrashedPuzzles(ctx context.Context, hunt int64) ([]ListTrashedPuzzlesRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []ListTrashedPuzzlesRow
+	for _, puzzle := range sorted(m.puzzles, func(a, b Puzzle) int {
+		return b.DeletedAt.Time.Compare(a.DeletedAt.Time)
+	}) {
+		if !puzzle.DeletedAt.Valid || m.rounds[puzzle.Round].Hunt != hunt {
+			continue
+		}
+		var row = m.puzzleRow(puzzle)
+		rows = append(rows, ListTrashedPuzzlesRow{
+			ID:             row.ID,
+			Name:           row.Name,
+			Answer:         row.Answer,
+			Round:          row.Round,
+			Status:         row.Status,
+			Note:           row.Note,
+			Location:       row.Location,
+			PuzzleURL:      row.PuzzleURL,
+			SpreadsheetID:  row.SpreadsheetID,
+			DiscordChannel: row.DiscordChannel,
+			Meta:           row.Meta,
+			VoiceRoom:      row.VoiceRoom,
+			Reminder:       row.Reminder,
+			Priority:       row.Priority,
+			NeedsHelp:      row.NeedsHelp,
+			Version:        row.Version,
+			Tags:           row.Tags,
+			Solvers:        row.Solvers,
+			DeletedAt:      puzzle.DeletedAt,
+		})
+	}
+	return rows, nil
+}
+
+func (m *Memory) PurgeTrashedPuzzles(ctx context.Context, hunt int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for id, puzzle := range m.puzzles {
+		if !puzzle.DeletedAt.Valid || m.rounds[puzzle.Round].Hunt != hunt {
+			continue
+		}
+		delete(m.puzzles, id)
+		count++
+
+		// ON DELETE CASCADE
+		for tag := range m.tags {
+			if tag.Puzzle == id {
+				delete(m.tags, tag)
+			}
+		}
+		for key := range m.solvers {
+			if key.puzzle == id {
+				delete(m.solvers, key)
+			}
+		}
+		for feed := range m.feeds {
+			if feed.Puzzle == id || feed.Meta == id {
+				delete(m.feeds, feed)
+			}
+		}
+		for key, guess := range m.guesses {
+			if guess.Puzzle == id {
+				delete(m.guesses, key)
+			}
+		}
+		for key, reminder := range m.reminders {
+			if reminder.Puzzle.Valid && reminder.Puzzle.Int64 == id {
+				delete(m.reminders, key)
+			}
+		}
+		for key, change := range m.discoveredChanges {
+			if change.Puzzle == id {
+				delete(m.discoveredChanges, key)
+			}
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) RestorePuzzle(ctx context.Context, id int64) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	puzzle, ok := m.puzzles[id]
+	if !ok || !puzzle.DeletedAt.Valid {
+		return 0, nil
+	}
+	puzzle.DeletedAt = sql.NullTime{}
+	if err := m.checkPuzzle(puzzle); err != nil {
+		return 0, err
+	}
+	puzzle.Version++
+	m.puzzles[id] = puzzle
+	return 1, nil
+}
+
+func (m *Memory) TrashPuzzle(ctx context.Context, arg TrashPuzzleParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	puzzle, ok := m.puzzles[arg.ID]
+	if !ok || puzzle.DeletedAt.Valid {
+		return 0, nil
+	}
+	puzzle.DeletedAt = arg.DeletedAt
+	puzzle.Version++
+	m.puzzles[arg.ID] = puzzle
+	return 1, nil
+}
+
+func (m *Memory) UpdatePuzzle(ctx context.Context, arg UpdatePuzzleParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.puzzles[arg.ID]; !ok {
+		return nil
+	}
+	var puzzle = Puzzle{
+		ID:             arg.ID,
+		Name:           arg.Name,
+		Answer:         arg.Answer,
+		Round:          arg.Round,
+		Status:         arg.Status,
+		Note:           arg.Note,
+		Location:       arg.Location,
+		PuzzleURL:      arg.PuzzleURL,
+		SpreadsheetID:  arg.SpreadsheetID,
+		DiscordChannel: arg.DiscordChannel,
+		Meta:           arg.Meta,
+		VoiceRoom:      arg.VoiceRoom,
+		Reminder:       arg.Reminder,
+		Version:        arg.Version,
+		DeletedAt:      arg.DeletedAt,
+		Priority:       arg.Priority,
+		NeedsHelp:      arg.NeedsHelp,
+	}
+	if err := m.checkPuzzle(puzzle); err != nil {
+		return err
+	}
+	m.puzzles[arg.ID] = puzzle
+	return nil
+}
+
+//
+// Tags, Solvers, Feeds & Guesses
+//
+
+func (m *Memory) CreatePuzzleTag(ctx context.Context, arg CreatePuzzleTagParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var tag = PuzzleTag(arg)
+	if _, ok := m.puzzles[tag.Puzzle]; !ok {
+		return 0, errForeignKey
+	} else if m.tags[tag] {
+		return 0, nil
+	}
+	m.tags[tag] = true
+	return 1, nil
+}
+
+func (m *Memory) DeletePuzzleTag(ctx context.Context, arg DeletePuzzleTagParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var tag = PuzzleTag(arg)
+	if !m.tags[tag] {
+		return 0, nil
+	}
+	delete(m.tags, tag)
+	return 1, nil
+}
+
+func (m *Memory) CreatePuzzleSolver(ctx context.Context, arg CreatePuzzleSolverParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var key = solverKey{arg.Puzzle, arg.Solver}
+	if _, ok := m.puzzles[arg.Puzzle]; !ok {
+		return 0, errForeignKey
+	} else if _, ok := m.solvers[key]; ok {
+		return 0, nil
+	}
+	m.solvers[key] = PuzzleSolver(arg)
+	return 1, nil
+}
+
+func (m *Memory) DeletePuzzleSolver(ctx context.Context, arg DeletePuzzleSolverParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var key = solverKey{arg.Puzzle, arg.Solver}
+	if _, ok := m.solvers[key]; !ok {
+		return 0, nil
+	}
+	delete(m.solvers, key)
+	return 1, nil
+}
+
+// Implements ORDER BY joined_at, solver
+func compareSolvers(a, b PuzzleSolver) int {
+	if c := a.JoinedAt.Compare(b.JoinedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Solver, b.Solver)
+}
+
+func (m *Memory) ListPuzzleSolvers(ctx context.Context, puzzle int64) ([]PuzzleSolver, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var solvers []PuzzleSolver
+	for _, solver := range sorted(m.solvers, compareSolvers) {
+		if solver.Puzzle == puzzle {
+			solvers = append(solvers, solver)
+		}
+	}
+	return solvers, nil
+}
+
+func (m *Memory) ListIdlePuzzleSolvers(ctx context.Context, arg ListIdlePuzzleSolversParams) ([]PuzzleSolver, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var solvers []PuzzleSolver
+	for _, solver := range sorted(m.solvers, func(a, b PuzzleSolver) int {
+		return a.ActiveAt.Compare(b.ActiveAt)
+	}) {
+		var puzzle = m.puzzles[solver.Puzzle]
+		if solver.ActiveAt.Before(arg.ActiveBefore) && !puzzle.DeletedAt.Valid &&
+			m.rounds[puzzle.Round].Hunt == arg.Hunt {
+			solvers = append(solvers, solver)
+		}
+	}
+	return solvers, nil
+}
+
+func (m *Memory) TouchPuzzleSolver(ctx context.Context, arg TouchPuzzleSolverParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.touchSolvers(arg.ActiveAt, func(solver PuzzleSolver) bool {
+		return solver.Solver == arg.Solver &&
+			m.puzzles[solver.Puzzle].DiscordChannel == arg.DiscordChannel
+	})
+	return nil
+}
+
+func (m *Memory) TouchPuzzleSolversBySpreadsheet(ctx context.Context, arg TouchPuzzleSolversBySpreadsheetParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.touchSolvers(arg.ActiveAt, func(solver PuzzleSolver) bool {
+		return m.puzzles[solver.Puzzle].SpreadsheetID == arg.SpreadsheetID
+	})
+	return nil
+}
+
+// Moves active_at forward to the given time on the matching solvers.
+func (m *Memory) touchSolvers(at time.Time, filter func(solver PuzzleSolver) bool) {
+	for key, solver := range m.solvers {
+		if solver.ActiveAt.Before(at) && filter(solver) {
+			solver.ActiveAt = at
+			m.solvers[key] = solver
+		}
+	}
+}
+
+func (m *Memory) CreatePuzzleFeed(ctx context.Context, arg CreatePuzzleFeedParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var feed = PuzzleFeed(arg)
+	if _, ok := m.puzzles[feed.Puzzle]; !ok {
+		return 0, errForeignKey
+	} else if _, ok := m.puzzles[feed.Meta]; !ok {
+		return 0, errForeignKey
+	} else if m.feeds[feed] {
+		return 0, nil
+	}
+	m.feeds[feed] = true
+	return 1, nil
+}
+
+func (m *Memory) DeletePuzzleFeed(ctx context.Context, arg DeletePuzzleFeedParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var feed = PuzzleFeed(arg)
+	if !m.feeds[feed] {
+		return 0, nil
+	}
+	delete(m.feeds, feed)
+	return 1, nil
+}
+
+func (m *Memory) ListPuzzleFeeds(ctx context.Context, hunt int64) ([]PuzzleFeed, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var feeds []PuzzleFeed
+	for feed := range m.feeds {
+		var puzzle, meta = m.puzzles[feed.Puzzle], m.puzzles[feed.Meta]
+		if !puzzle.DeletedAt.Valid && !meta.DeletedAt.Valid &&
+			m.rounds[meta.Round].Hunt == hunt {
+			feeds = append(feeds, feed)
+		}
+	}
+	slices.SortFunc(feeds, func(a, b PuzzleFeed) int {
+		return cmp.Or(cmp.Compare(a.Meta, b.Meta), cmp.Compare(a.Puzzle, b.Puzzle))
+	})
+	return feeds, nil
+}
+
+// Returns the live puzzles on the other end of the feeds, by name.
+func (m *Memory) listFeeds(other func(f PuzzleFeed) (int64, bool)) []ListFeedersRow {
+	var rows []ListFeedersRow
+	for feed := range m.feeds {
+		id, ok := other(feed)
+		if puzzle := m.puzzles[id]; ok && !puzzle.DeletedAt.Valid {
+			rows = append(rows, ListFeedersRow{
+				ID:             puzzle.ID,
+				Name:           puzzle.Name,
+				Answer:         puzzle.Answer,
+				DiscordChannel: puzzle.DiscordChannel,
+			})
+		}
+	}
+	slices.SortFunc(rows, func(a, b ListFeedersRow) int {
+		return cmp.Or(cmp.Compare(nocase(a.Name), nocase(b.Name)), cmp.Compare(a.ID, b.ID))
+	})
+	return rows
+}
+
+func (m *Memory) ListFedMetas(ctx context.Context, puzzle int64) ([]ListFedMetasRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []ListFedMetasRow
+	for _, row := range m.listFeeds(func(f PuzzleFeed) (int64, bool) {
+		return f.Meta, f.Puzzle == puzzle
+	}) {
+		rows = append(rows, ListFedMetasRow(row))
+	}
+	return rows, nil
+}
+
+func (m *Memory) ListFeeders(ctx context.Context, meta int64) ([]ListFeedersRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.listFeeds(func(f PuzzleFeed) (int64, bool) {
+		return f.Puzzle, f.Meta == meta
+	}), nil
+}
+
+func (m *Memory) CreateGuess(ctx context.Context, arg CreateGuessParams) (Guess, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.puzzles[arg.Puzzle]; !ok {
+		return Guess{}, errForeignKey
+	}
+	var guess = Guess{
+		ID:          nextID(m.guesses),
+		Puzzle:      arg.Puzzle,
+		Guess:       arg.Guess,
+		Result:      arg.Result,
+		Note:        arg.Note,
+		Actor:       arg.Actor,
+		SubmittedAt: arg.SubmittedAt,
+	}
+	m.guesses[guess.ID] = guess
+	return guess, nil
+}
+
+func (m *Memory) ListAllGuesses(ctx context.Context, hunt int64) ([]Guess, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var guesses []Guess
+	for _, guess := range sorted(m.guesses, byID(func(g Guess) int64 { return g.ID })) {
+		if puzzle, ok := m.puzzles[guess.Puzzle]; ok && m.rounds[puzzle.Round].Hunt == hunt {
+			guesses = append(guesses, guess)
+		}
+	}
+	return guesses, nil
+}
+
+func (m *Memory) ListGuesses(ctx context.Context, puzzle int64) ([]Guess, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var guesses []Guess
+	for _, guess := range sorted(m.guesses, byID(func(g Guess) int64 { return g.ID })) {
+		if guess.Puzzle == puzzle {
+			guesses = append(guesses, guess)
+		}
+	}
+	return guesses, nil
+}
+
+func (m *Memory) CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.hunts[arg.Hunt]; !ok {
+		return Reminder{}, errForeignKey
+	} else if _, ok := m.puzzles[arg.Puzzle.Int64]; arg.Puzzle.Valid && !ok {
+		return Reminder{}, errForeignKey
+	}
+	var reminder = Reminder{
+		ID:             nextID(m.reminders),
+		Hunt:           arg.Hunt,
+		Puzzle:         arg.Puzzle,
+		DiscordChannel: arg.DiscordChannel,
+		Message:        arg.Message,
+		RemindAt:       arg.RemindAt,
+		LeadMinutes:    arg.LeadMinutes,
+		EveryMinutes:   arg.EveryMinutes,
+		CreatedBy:      arg.CreatedBy,
+		CreatedAt:      arg.CreatedAt,
+	}
+	m.reminders[reminder.ID] = reminder
+	return reminder, nil
+}
+
+// Implements ORDER BY remind_at, id
+func (m *Memory) ListReminders(ctx context.Context, hunt int64) ([]Reminder, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var reminders []Reminder
+	for _, reminder := range sorted(m.reminders, func(a, b Reminder) int {
+		return cmp.Or(a.RemindAt.Compare(b.RemindAt), cmp.Compare(a.ID, b.ID))
+	}) {
+		if reminder.Hunt != hunt {
+			continue
+		} else if reminder.Puzzle.Valid && m.puzzles[reminder.Puzzle.Int64].DeletedAt.Valid {
+			continue // puzzle is in the trash
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, nil
+}
+
+func (m *Memory) DeleteReminder(ctx context.Context, arg DeleteReminderParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if reminder, ok := m.reminders[arg.ID]; !ok || reminder.Hunt != arg.Hunt {
+		return 0, nil
+	}
+	delete(m.reminders, arg.ID)
+	return 1, nil
+}
+
+func (m *Memory) CreateChangelog(ctx context.Context, arg CreateChangelogParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.changelog[arg.ID]; ok {
+		return errPrimaryKey
+	}
+	m.changelog[arg.ID] = Changelog{
+		ID:     arg.ID,
+		Kind:   arg.Kind,
+		Puzzle: arg.Puzzle,
+		Round:  arg.Round,
+		Actor:  arg.Actor,
+		Feed:   arg.Feed,
+		Guess:  arg.Guess,
+	}
+	return nil
+}
+
+func (m *Memory) GetLastChangeID(ctx context.Context) (interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.changelog) == 0 {
+		return nil, nil // max() of no rows is NULL
+	}
+	return nextID(m.changelog) - 1, nil
+}
+
+func (m *Memory) ListChangelog(ctx context.Context) ([]Changelog, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return sorted(m.changelog, byID(func(c Changelog) int64 { return c.ID })), nil
+}
+
+func (m *Memory) PruneChangelog(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var entries = sorted(m.changelog, byID(func(c Changelog) int64 { return c.ID }))
+	for len(entries) > 256 {
+		delete(m.changelog, entries[0].ID)
+		entries = entries[1:]
+	}
+	return nil
+}
+
+func (m *Memory) CreatePuzzleHistory(ctx context.Context, arg CreatePuzzleHistoryParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var id = nextID(m.history)
+	m.history[id] = PuzzleHistory{
+		ID:        id,
+		Puzzle:    arg.Puzzle,
+		Field:     arg.Field,
+		OldValue:  arg.OldValue,
+		NewValue:  arg.NewValue,
+		ChangedAt: arg.ChangedAt,
+		Actor:     arg.Actor,
+	}
+	return nil
+}
+
+func (m *Memory) ListAllPuzzleHistory(ctx context.Context, hunt int64) ([]PuzzleHistory, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var history []PuzzleHistory
+	for _, entry := range sorted(m.history, byID(func(h PuzzleHistory) int64 { return h.ID })) {
+		if puzzle, ok := m.puzzles[entry.Puzzle]; ok && m.rounds[puzzle.Round].Hunt == hunt {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
+
+func (m *Memory) ListPuzzleHistory(ctx context.Context, puzzle int64) ([]PuzzleHistory, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var history []PuzzleHistory
+	for _, entry := range sorted(m.history, byID(func(h PuzzleHistory) int64 { return h.ID })) {
+		if entry.Puzzle == puzzle {
+			history = append(history, entry)
+		}
+	}
+	return history, nil
+}
+
+var searchTerm = regexp.MustCompile(`"([^"]*)"\*`)
+
+// Splits text into lowercase words, roughly like FTS5's unicode61 tokenizer.
+func searchTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Counts the matches of each of the query's prefix phrases in the columns of a
+// single row. If any phrase is missing, the row doesn't match and zero is
+// returned.
+func searchHits(phrases [][]string, columns ...string) int {
+	var total int
+	for _, phrase := range phrases {
+		var hits int
+		for _, column := range columns {
+			var tokens = searchTokens(column)
+			for i := 0; i+len(phrase) <= len(tokens); i++ {
+				var last = len(phrase) - 1
+				if slices.Equal(tokens[i:i+last], phrase[:last]) &&
+					strings.HasPrefix(tokens[i+last], phrase[last]) {
+					hits++
+				}
+			}
+		}
+		if hits == 0 {
+			return 0
+		}
+		total += hits
+	}
+	return total
+}
+
+// Supports the expressions built by state.searchExpression: a list of quoted
+// prefix phrases, all of which must match.
+func (m *Memory) SearchPuzzles(ctx context.Context, arg SearchPuzzlesParams) ([]SearchPuzzlesRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var phrases [][]string
+	for _, match := range searchTerm.FindAllStringSubmatch(arg.Query, -1) {
+		if phrase := searchTokens(match[1]); len(phrase) > 0 {
+			phrases = append(phrases, phrase)
+		}
+	}
+	if len(phrases) == 0 {
+		return nil, nil
+	}
+
+	var ranks = make(map[int64]float64)
+	var hit = func(puzzle int64, rank float64) {
+		if current, ok := ranks[puzzle]; !ok || rank < current {
+			ranks[puzzle] = rank
+		}
+	}
+	for _, puzzle := range m.puzzles {
+		var round = m.rounds[puzzle.Round]
+		if hits := searchHits(phrases, puzzle.Name, puzzle.Note, puzzle.Answer, round.Name); hits > 0 {
+			hit(puzzle.ID, -float64(hits))
+		}
+	}
+	for _, message := range m.messages {
+		var hits = searchHits(phrases, message.Content)
+		if hits == 0 {
+			continue
+		}
+		for _, puzzle := range m.puzzles {
+			if puzzle.DiscordChannel == message.Channel {
+				// discussion is a weaker signal than the puzzle's own fields
+				hit(puzzle.ID, -float64(hits)*0.5)
+			}
+		}
+	}
+
+	var rows []SearchPuzzlesRow
+	for id, rank := range ranks {
+		var puzzle = m.puzzles[id]
+		var round = m.rounds[puzzle.Round]
+		if puzzle.DeletedAt.Valid || round.Hunt != arg.Hunt {
+			continue
+		}
+		rows = append(rows, SearchPuzzlesRow{
+			ID:             puzzle.ID,
+			Name:           puzzle.Name,
+			Answer:         puzzle.Answer,
+			DiscordChannel: puzzle.DiscordChannel,
+			RoundName:      round.Name,
+			RoundEmoji:     round.Emoji,
+			Rank:           rank,
+		})
+	}
+	slices.SortFunc(rows, func(a, b SearchPuzzlesRow) int {
+		return cmp.Or(cmp.Compare(a.Rank, b.Rank), cmp.Compare(a.ID, b.ID))
+	})
+	if int64(len(rows)) > arg.MaxResults {
+		rows = rows[:max(arg.MaxResults, 0)]
+	}
+	return rows, nil
+}
+
+func (m *Memory) CreateSearchMessage(ctx context.Context, arg CreateSearchMessageParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.messages[arg.Rowid] = SearchMessage{Content: arg.Content, Channel: arg.Channel}
+	return nil
+}
+
+func (m *Memory) DeleteSearchMessage(ctx context.Context, rowid int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.messages, rowid)
+	return nil
+}
+
+func (m *Memory) GetSetting(ctx context.Context, arg GetSettingParams) ([]byte, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	value, ok := m.settings[settingKey{arg.Hunt, arg.Key}]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return slices.Clone(value), nil
+}
+
+func (m *Memory) UpdateSetting(ctx context.Context, arg UpdateSettingParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.settings[settingKey{arg.Hunt, arg.Key}] = slices.Clone(arg.Value)
+	return nil
+}
+
+func (m *Memory) CheckPuzzleIsDiscovered(ctx context.Context, arg CheckPuzzleIsDiscoveredParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for _, puzzle := range m.discoveredPuzzles {
+		if puzzle.Hunt == arg.Hunt && (puzzle.Name == arg.Name ||
+			nocase(puzzle.PuzzleURL) == nocase(arg.PuzzleURL)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) CompleteDiscoveredPuzzle(ctx context.Context, id int64) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if puzzle, ok := m.discoveredPuzzles[id]; ok {
+		puzzle.DiscoveredRound = sql.NullInt64{}
+		m.discoveredPuzzles[id] = puzzle
+	}
+	return nil
+}
+
+func (m *Memory) CompleteDiscoveredRound(ctx context.Context, arg CompleteDiscoveredRoundParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if round, ok := m.discoveredRounds[arg.ID]; ok {
+		round.CreatedAs = arg.CreatedAs
+		m.discoveredRounds[arg.ID] = round
+	}
+	return nil
+}
+
+func (m *Memory) CreateDiscoveredPuzzle(ctx context.Context, arg CreateDiscoveredPuzzleParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if arg.DiscoveredRound.Valid {
+		if _, ok := m.discoveredRounds[arg.DiscoveredRound.Int64]; !ok {
+			return errForeignKey
+		}
+	}
+	var id = nextID(m.discoveredPuzzles)
+	m.discoveredPuzzles[id] = DiscoveredPuzzle{
+		ID:              id,
+		PuzzleURL:       arg.PuzzleURL,
+		Name:            arg.Name,
+		DiscoveredRound: arg.DiscoveredRound,
+		Hunt:            arg.Hunt,
+	}
+	return nil
+}
+
+func (m *Memory) CreateDiscoveredRound(ctx context.Context, arg CreateDiscoveredRoundParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var id = nextID(m.discoveredRounds)
+	m.discoveredRounds[id] = DiscoveredRound{
+		ID:         id,
+		Name:       arg.Name,
+		MessageID:  arg.MessageID,
+		NotifiedAt: arg.NotifiedAt,
+		CreatedAs:  arg.CreatedAs,
+		Hunt:       arg.Hunt,
+	}
+	return id, nil
+}
+
+func (m *Memory) GetDiscoveredRound(ctx context.Context, arg GetDiscoveredRoundParams) (DiscoveredRound, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, round := range sorted(m.discoveredRounds, byID(func(r DiscoveredRound) int64 { return r.ID })) {
+		if round.Hunt == arg.Hunt && nocase(round.Name) == nocase(arg.Name) {
+			return round, nil
+		}
+	}
+	return DiscoveredRound{}, sql.ErrNoRows
+}
+
+func (m *Memory) GetDiscoveredRoundByID(ctx context.Context, id int64) (DiscoveredRound, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if round, ok := m.discoveredRounds[id]; ok {
+		return round, nil
+	}
+	return DiscoveredRound{}, sql.ErrNoRows
+}
+
+func (m *Memory) ListCreatablePuzzles(ctx context.Context, hunt int64) ([]ListCreatablePuzzlesRow, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rows []ListCreatablePuzzlesRow
+	for _, puzzle := range sorted(m.discoveredPuzzles, byID(func(p DiscoveredPuzzle) int64 { return p.ID })) {
+		if !puzzle.DiscoveredRound.Valid {
+			continue
+		}
+		round, ok := m.discoveredRounds[puzzle.DiscoveredRound.Int64]
+		if !ok || round.Hunt != hunt || round.CreatedAs == 0 {
+			continue
+		}
+		rows = append(rows, ListCreatablePuzzlesRow{
+			ID:              puzzle.ID,
+			PuzzleURL:       puzzle.PuzzleURL,
+			Name:            puzzle.Name,
+			DiscoveredRound: puzzle.DiscoveredRound,
+			Hunt:            puzzle.Hunt,
+			ID_2:            round.ID,
+			Name_2:          round.Name,
+			MessageID:       round.MessageID,
+			NotifiedAt:      round.NotifiedAt,
+			CreatedAs:       round.CreatedAs,
+			Hunt_2:          round.Hunt,
+			Emoji:           round.Emoji,
+			EditedName:      round.EditedName,
+			Ignored:         round.Ignored,
+		})
+	}
+	return rows, nil
+}
+
+func (m *Memory) ListDiscoveredPuzzles(ctx context.Context, hunt int64) ([]DiscoveredPuzzle, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var puzzles []DiscoveredPuzzle
+	for _, puzzle := range sorted(m.discoveredPuzzles, byID(func(p DiscoveredPuzzle) int64 { return p.ID })) {
+		if puzzle.Hunt == hunt {
+			puzzles = append(puzzles, puzzle)
+		}
+	}
+	return puzzles, nil
+}
+
+func (m *Memory) ListDiscoveredPuzzlesForRound(ctx context.Context, discoveredRound sql.NullInt64) ([]DiscoveredPuzzle, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var puzzles []DiscoveredPuzzle
+	for _, puzzle := range sorted(m.discoveredPuzzles, byID(func(p DiscoveredPuzzle) int64 { return p.ID })) {
+		// NULL never compares equal
+		if discoveredRound.Valid && puzzle.DiscoveredRound == discoveredRound {
+			puzzles = append(puzzles, puzzle)
+		}
+	}
+	return puzzles, nil
+}
+
+func (m *Memory) ListDiscoveredRounds(ctx context.Context, hunt int64) ([]DiscoveredRound, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rounds []DiscoveredRound
+	for _, round := range sorted(m.discoveredRounds, byID(func(r DiscoveredRound) int64 { return r.ID })) {
+		if round.Hunt == hunt {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds, nil
+}
+
+func (m *Memory) ListPendingDiscoveredRounds(ctx context.Context, hunt int64) ([]DiscoveredRound, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var rounds []DiscoveredRound
+	for _, round := range sorted(m.discoveredRounds, byID(func(r DiscoveredRound) int64 { return r.ID })) {
+		if round.Hunt == hunt && round.CreatedAs == 0 && !round.Ignored {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds, nil
+}
+
+func (m *Memory) UpdateDiscoveredRound(ctx context.Context, arg UpdateDiscoveredRoundParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.discoveredRounds[arg.ID]; ok {
+		m.discoveredRounds[arg.ID] = DiscoveredRound(arg)
+	}
+	return nil
+}
+
+func (m *Memory) CheckDiscoveredChange(ctx context.Context, arg CheckDiscoveredChangeParams) (int64, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var count int64
+	for _, change := range m.discoveredChanges {
+		if change.Puzzle == arg.Puzzle && change.Name == arg.Name &&
+			change.RoundName == arg.RoundName && change.Removed == arg.Removed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *Memory) CreateDiscoveredChange(ctx context.Context, arg CreateDiscoveredChangeParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.hunts[arg.Hunt]; !ok {
+		return errForeignKey
+	} else if _, ok := m.puzzles[arg.Puzzle]; !ok {
+		return errForeignKey
+	}
+	var id = nextID(m.discoveredChanges)
+	m.discoveredChanges[id] = DiscoveredChange{
+		ID:         id,
+		Hunt:       arg.Hunt,
+		Puzzle:     arg.Puzzle,
+		Name:       arg.Name,
+		RoundName:  arg.RoundName,
+		Removed:    arg.Removed,
+		MessageID:  arg.MessageID,
+		NotifiedAt: arg.NotifiedAt,
+	}
+	return nil
+}
+
+func (m *Memory) GetDiscoveredChange(ctx context.Context, id int64) (DiscoveredChange, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if change, ok := m.discoveredChanges[id]; ok {
+		return change, nil
+	}
+	return DiscoveredChange{}, sql.ErrNoRows
+}
+
+func (m *Memory) ListPendingDiscoveredChanges(ctx context.Context, hunt int64) ([]DiscoveredChange, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var changes []DiscoveredChange
+	for _, change := range sorted(m.discoveredChanges, byID(func(c DiscoveredChange) int64 { return c.ID })) {
+		if change.Hunt == hunt && change.MessageID == "" {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (m *Memory) UpdateDiscoveredChange(ctx context.Context, arg UpdateDiscoveredChangeParams) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if change, ok := m.discoveredChanges[arg.ID]; ok {
+		change.MessageID = arg.MessageID
+		change.NotifiedAt = arg.NotifiedAt
+		m.discoveredChanges[arg.ID] = change
+	}
+	return nil
+}
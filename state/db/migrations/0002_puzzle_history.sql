@@ -0,0 +1,13 @@
+CREATE TABLE puzzle_history (
+    id              INTEGER PRIMARY KEY,
+    puzzle          INTEGER NOT NULL,
+    field           TEXT    NOT NULL,
+    old_value       TEXT    NOT NULL,
+    new_value       TEXT    NOT NULL,
+    changed_at      DATETIME NOT NULL,
+
+    -- a Discord user ID, "discovery" or "sync"
+    actor           TEXT    NOT NULL
+);
+
+CREATE INDEX idx_puzzle_history_puzzle ON puzzle_history(puzzle, id);
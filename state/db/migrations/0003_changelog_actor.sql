@@ -0,0 +1,2 @@
+-- a Discord user ID, "discovery" or "sync"; blank for older entries
+ALTER TABLE changelog ADD COLUMN actor TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- Incremented on every write, so clients can detect that they're about to
+-- overwrite someone else's changes.
+ALTER TABLE puzzles ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE rounds ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
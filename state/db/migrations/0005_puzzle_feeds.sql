@@ -0,0 +1,14 @@
+-- Records which puzzles feed into which metas. A puzzle can feed into several
+-- metas, and metas can themselves feed into other metas.
+CREATE TABLE puzzle_feeds (
+    puzzle          INTEGER NOT NULL,
+    meta            INTEGER NOT NULL,
+
+    PRIMARY KEY (puzzle, meta),
+    FOREIGN KEY (puzzle) REFERENCES puzzles(id) ON DELETE CASCADE,
+    FOREIGN KEY (meta) REFERENCES puzzles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_puzzle_feeds_meta ON puzzle_feeds(meta);
+
+ALTER TABLE changelog ADD COLUMN feed BLOB;
@@ -0,0 +1,21 @@
+-- Every answer we've submitted to the hunt site, so we don't waste submissions
+-- (and rate limits) on answers that have already been tried.
+CREATE TABLE guesses (
+    id              INTEGER PRIMARY KEY,
+    puzzle          INTEGER NOT NULL,
+    guess           TEXT    NOT NULL,
+    result          TEXT    NOT NULL,
+
+    -- the hunt site's response, if it said anything interesting
+    note            TEXT    NOT NULL,
+
+    -- a Discord user ID, "discovery" or "sync"
+    actor           TEXT    NOT NULL,
+    submitted_at    DATETIME NOT NULL,
+
+    FOREIGN KEY (puzzle) REFERENCES puzzles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_guesses_puzzle ON guesses(puzzle, id);
+
+ALTER TABLE changelog ADD COLUMN guess BLOB;
@@ -0,0 +1,11 @@
+-- Free-form labels like "logic" or "needs-fresh-eyes". Tags are normalized to
+-- lowercase words separated by hyphens; see state.NormalizeTag.
+CREATE TABLE puzzle_tags (
+    puzzle          INTEGER NOT NULL,
+    tag             TEXT    NOT NULL,
+
+    PRIMARY KEY (puzzle, tag),
+    FOREIGN KEY (puzzle) REFERENCES puzzles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_puzzle_tags_tag ON puzzle_tags(tag);
@@ -0,0 +1,40 @@
+-- Full-text search over puzzles and the Discord messages sent in their
+-- channels. Requires FTS5: build with `-tags sqlite_fts5`.
+
+-- rowid is the puzzle ID. Kept up to date by the triggers below.
+CREATE VIRTUAL TABLE search_puzzles USING fts5(
+    name, note, answer, round_name,
+    tokenize = 'porter unicode61'
+);
+
+-- rowid is the Discord message ID
+CREATE VIRTUAL TABLE search_messages USING fts5(
+    content, channel UNINDEXED,
+    tokenize = 'porter unicode61'
+);
+
+CREATE TRIGGER search_puzzles_insert AFTER INSERT ON puzzles BEGIN
+    INSERT INTO search_puzzles (rowid, name, note, answer, round_name)
+    SELECT NEW.id, NEW.name, NEW.note, NEW.answer, rounds.name
+    FROM rounds WHERE rounds.id = NEW.round;
+END;
+
+CREATE TRIGGER search_puzzles_update AFTER UPDATE ON puzzles BEGIN
+    DELETE FROM search_puzzles WHERE rowid = OLD.id;
+    INSERT INTO search_puzzles (rowid, name, note, answer, round_name)
+    SELECT NEW.id, NEW.name, NEW.note, NEW.answer, rounds.name
+    FROM rounds WHERE rounds.id = NEW.round;
+END;
+
+CREATE TRIGGER search_puzzles_delete AFTER DELETE ON puzzles BEGIN
+    DELETE FROM search_puzzles WHERE rowid = OLD.id;
+END;
+
+CREATE TRIGGER search_rounds_update AFTER UPDATE OF name ON rounds BEGIN
+    UPDATE search_puzzles SET round_name = NEW.name
+    WHERE rowid IN (SELECT id FROM puzzles WHERE round = NEW.id);
+END;
+
+INSERT INTO search_puzzles (rowid, name, note, answer, round_name)
+SELECT puzzles.id, puzzles.name, puzzles.note, puzzles.answer, rounds.name
+FROM puzzles INNER JOIN rounds ON puzzles.round = rounds.id;
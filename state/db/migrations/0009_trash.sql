@@ -0,0 +1,4 @@
+-- Deleting a puzzle or round moves it to the trash by setting deleted_at. It
+-- can be restored until the trash is purged.
+ALTER TABLE puzzles ADD COLUMN deleted_at DATETIME;
+ALTER TABLE rounds ADD COLUMN deleted_at DATETIME;
@@ -0,0 +1,85 @@
+-- A database can hold several hunts: previous years, for comparison, and
+-- practice events running alongside the real thing. Exactly one hunt is
+-- active; the bots, discovery and the syncer work on the active hunt. Archived
+-- hunts are read-only.
+CREATE TABLE hunts (
+    id              INTEGER PRIMARY KEY,
+    name            TEXT    NOT NULL,
+    active          BOOLEAN NOT NULL,
+    archived        BOOLEAN NOT NULL,
+    created_at      DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_hunts_active ON hunts(active) WHERE active;
+
+-- Existing data belongs to the first hunt, which is named after the hunt in
+-- the discovery config.
+INSERT INTO hunts (id, name, active, archived, created_at)
+VALUES (1, COALESCE((
+    SELECT NULLIF(json_extract(CAST(value AS TEXT), '$.hunt_name'), '')
+    FROM settings WHERE key = 'discovery_config'
+), 'Mystery Hunt'), TRUE, FALSE, CURRENT_TIMESTAMP);
+
+-- Rounds belong to a hunt, and puzzles belong to a hunt through their round.
+-- Round names and emoji only need to be unique within a hunt, so the table is
+-- rebuilt with new constraints. (The rename is done in legacy mode so that
+-- SQLite doesn't try to re-resolve the triggers on puzzles, which refer to
+-- rounds, while the table is missing.)
+CREATE TABLE rounds_new (
+    id              INTEGER PRIMARY KEY,
+    name            TEXT    NOT NULL,
+    emoji           TEXT    NOT NULL,
+    hue             INTEGER NOT NULL,
+
+    sort            INTEGER NOT NULL,
+    special         BOOLEAN NOT NULL,
+
+    drive_folder    TEXT    NOT NULL,
+    discord_category TEXT   NOT NULL,
+
+    version         INTEGER NOT NULL DEFAULT 1,
+    deleted_at      DATETIME,
+    hunt            INTEGER NOT NULL,
+
+    FOREIGN KEY (hunt) REFERENCES hunts(id),
+    CONSTRAINT uc_name  UNIQUE(hunt, name),
+    CONSTRAINT uc_emoji UNIQUE(hunt, emoji)
+);
+
+INSERT INTO rounds_new (
+    id, name, emoji, hue, sort, special, drive_folder, discord_category,
+    version, deleted_at, hunt
+)
+SELECT
+    id, name, emoji, hue, sort, special, drive_folder, discord_category,
+    version, deleted_at, 1
+FROM rounds;
+
+DROP TABLE rounds;
+PRAGMA legacy_alter_table = ON;
+ALTER TABLE rounds_new RENAME TO rounds;
+PRAGMA legacy_alter_table = OFF;
+
+-- (dropped along with the old table)
+CREATE TRIGGER search_rounds_update AFTER UPDATE OF name ON rounds BEGIN
+    UPDATE search_puzzles SET round_name = NEW.name
+    WHERE rowid IN (SELECT id FROM puzzles WHERE round = NEW.id);
+END;
+
+ALTER TABLE discovered_rounds ADD COLUMN hunt INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE discovered_puzzles ADD COLUMN hunt INTEGER NOT NULL DEFAULT 1;
+
+-- Settings are per-hunt, too.
+CREATE TABLE settings_new (
+    hunt            INTEGER NOT NULL,
+    key             TEXT    NOT NULL,
+    value           BLOB,
+
+    PRIMARY KEY (hunt, key)
+);
+
+INSERT INTO settings_new (hunt, key, value)
+SELECT 1, key, value FROM settings;
+
+DROP TABLE settings;
+ALTER TABLE settings_new RENAME TO settings;
@@ -0,0 +1,14 @@
+-- The hunt metadata used to live in the discovery config. Move it into its own
+-- setting. (The hunt name moved to the hunts table in the previous migration.)
+INSERT INTO settings (hunt, key, value)
+SELECT hunt, 'hunt_info', json_object(
+    'hunt_url', COALESCE(json_extract(CAST(value AS TEXT), '$.hunt_url'), ''),
+    'hunt_credentials', COALESCE(json_extract(CAST(value AS TEXT), '$.hunt_credentials'), ''),
+    'logistics_url', COALESCE(json_extract(CAST(value AS TEXT), '$.logistics_url'), '')
+)
+FROM settings WHERE key = 'discovery_config';
+
+UPDATE settings
+SET value = json_remove(CAST(value AS TEXT),
+    '$.hunt_name', '$.hunt_url', '$.hunt_credentials', '$.logistics_url')
+WHERE key = 'discovery_config';
@@ -0,0 +1,4 @@
+-- Priority orders puzzles within a round (see status.Priority), and needs_help
+-- flags puzzles that could use more solvers.
+ALTER TABLE puzzles ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE puzzles ADD COLUMN needs_help BOOLEAN NOT NULL DEFAULT FALSE;
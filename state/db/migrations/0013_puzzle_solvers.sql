@@ -0,0 +1,12 @@
+-- Who has committed to working on each puzzle. active_at is bumped whenever
+-- the solver shows signs of life (see state.TouchPuzzleSolver), and claims that
+-- have been idle for too long expire.
+CREATE TABLE puzzle_solvers (
+    puzzle          INTEGER  NOT NULL,
+    solver          TEXT     NOT NULL, -- Discord user ID
+    joined_at       DATETIME NOT NULL,
+    active_at       DATETIME NOT NULL,
+
+    PRIMARY KEY (puzzle, solver),
+    FOREIGN KEY (puzzle) REFERENCES puzzles(id) ON DELETE CASCADE
+);
@@ -0,0 +1,22 @@
+-- Reminders set with /remind. A reminder either belongs to a puzzle or is a
+-- free-standing team reminder, like "lunch order". Besides going off at
+-- remind_at, it sends a heads-up at each of the lead times, and repeats every
+-- every_minutes if that's set. (Puzzles also have a single reminder of their
+-- own, in puzzles.reminder, which is set from the puzzle tracker.)
+CREATE TABLE reminders (
+    id              INTEGER  PRIMARY KEY,
+    hunt            INTEGER  NOT NULL,
+    puzzle          INTEGER,
+    discord_channel TEXT     NOT NULL, -- where to send the reminder
+    message         TEXT     NOT NULL,
+    remind_at       DATETIME NOT NULL, -- the first occurrence, if recurring
+    lead_minutes    TEXT     NOT NULL, -- comma-separated, e.g. "60,15"
+    every_minutes   INTEGER  NOT NULL, -- 0 if not recurring
+    created_by      TEXT     NOT NULL, -- Discord user ID
+    created_at      DATETIME NOT NULL,
+
+    FOREIGN KEY (hunt) REFERENCES hunts(id),
+    FOREIGN KEY (puzzle) REFERENCES puzzles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reminders_hunt ON reminders(hunt);
@@ -0,0 +1,19 @@
+-- Changes that discovery has spotted on the hunt site for puzzles we've already
+-- created: a new name or round, or the puzzle disappearing altogether. Each
+-- change is posted to #qm once (message_id is blank until then), and a QM can
+-- apply it with the buttons on the message.
+CREATE TABLE discovered_changes (
+    id              INTEGER  PRIMARY KEY,
+    hunt            INTEGER  NOT NULL,
+    puzzle          INTEGER  NOT NULL,
+    name            TEXT     NOT NULL, -- blank if unchanged
+    round_name      TEXT     NOT NULL, -- blank if unchanged
+    removed         BOOLEAN  NOT NULL,
+    message_id      TEXT     NOT NULL,
+    notified_at     DATETIME NOT NULL,
+
+    FOREIGN KEY (hunt) REFERENCES hunts(id),
+    FOREIGN KEY (puzzle) REFERENCES puzzles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_discovered_changes_puzzle ON discovered_changes(puzzle);
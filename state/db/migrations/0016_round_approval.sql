@@ -0,0 +1,7 @@
+-- New rounds are approved from a message in #qm. While the round is pending,
+-- the emoji and edited_name columns hold the QMs' choices (blank until they
+-- make one); name remains the round's name on the hunt site, so that puzzles
+-- can be matched to it. Ignored rounds are never created.
+ALTER TABLE discovered_rounds ADD COLUMN emoji TEXT NOT NULL DEFAULT '';
+ALTER TABLE discovered_rounds ADD COLUMN edited_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE discovered_rounds ADD COLUMN ignored BOOLEAN NOT NULL DEFAULT FALSE;
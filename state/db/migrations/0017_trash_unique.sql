@@ -0,0 +1,105 @@
+-- Puzzles and rounds in the trash no longer count towards the uniqueness
+-- constraints, so a deleted puzzle or round can be re-created (and a deleted
+-- round's emoji reused) without restoring it first. The constraints become
+-- partial indexes, which means rebuilding both tables, as in 0010_hunts.sql.
+CREATE TABLE rounds_new (
+    id              INTEGER PRIMARY KEY,
+    name            TEXT    NOT NULL,
+    emoji           TEXT    NOT NULL,
+    hue             INTEGER NOT NULL,
+
+    sort            INTEGER NOT NULL,
+    special         BOOLEAN NOT NULL,
+
+    drive_folder    TEXT    NOT NULL,
+    discord_category TEXT   NOT NULL,
+
+    version         INTEGER NOT NULL DEFAULT 1,
+    deleted_at      DATETIME,
+    hunt            INTEGER NOT NULL,
+
+    FOREIGN KEY (hunt) REFERENCES hunts(id)
+);
+
+INSERT INTO rounds_new (
+    id, name, emoji, hue, sort, special, drive_folder, discord_category,
+    version, deleted_at, hunt
+)
+SELECT
+    id, name, emoji, hue, sort, special, drive_folder, discord_category,
+    version, deleted_at, hunt
+FROM rounds;
+
+CREATE TABLE puzzles_new (
+    id              INTEGER PRIMARY KEY,
+    name            TEXT    NOT NULL,
+    answer          TEXT    NOT NULL,
+    round           INTEGER NOT NULL,
+    status          TEXT    NOT NULL,
+
+    note            TEXT    NOT NULL,
+    location        TEXT    NOT NULL,
+
+    puzzle_url      TEXT    NOT NULL,
+    spreadsheet_id  TEXT    NOT NULL,
+    discord_channel TEXT    NOT NULL,
+
+    meta            BOOLEAN NOT NULL,
+    voice_room      TEXT    NOT NULL,
+    reminder        DATETIME NOT NULL,
+
+    version         INTEGER NOT NULL DEFAULT 1,
+    deleted_at      DATETIME,
+    priority        INTEGER NOT NULL DEFAULT 0,
+    needs_help      BOOLEAN NOT NULL DEFAULT FALSE,
+
+    FOREIGN KEY (round) REFERENCES rounds(id)
+);
+
+INSERT INTO puzzles_new (
+    id, name, answer, round, status, note, location, puzzle_url,
+    spreadsheet_id, discord_channel, meta, voice_room, reminder, version,
+    deleted_at, priority, needs_help
+)
+SELECT
+    id, name, answer, round, status, note, location, puzzle_url,
+    spreadsheet_id, discord_channel, meta, voice_room, reminder, version,
+    deleted_at, priority, needs_help
+FROM puzzles;
+
+DROP TABLE puzzles;
+DROP TABLE rounds;
+PRAGMA legacy_alter_table = ON;
+ALTER TABLE rounds_new RENAME TO rounds;
+ALTER TABLE puzzles_new RENAME TO puzzles;
+PRAGMA legacy_alter_table = OFF;
+
+CREATE UNIQUE INDEX idx_rounds_name ON rounds(hunt, name)
+WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_rounds_emoji ON rounds(hunt, emoji)
+WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_puzzles_name ON puzzles(name COLLATE nocase, round)
+WHERE deleted_at IS NULL;
+
+-- (dropped along with the old tables)
+CREATE TRIGGER search_puzzles_insert AFTER INSERT ON puzzles BEGIN
+    INSERT INTO search_puzzles (rowid, name, note, answer, round_name)
+    SELECT NEW.id, NEW.name, NEW.note, NEW.answer, rounds.name
+    FROM rounds WHERE rounds.id = NEW.round;
+END;
+
+CREATE TRIGGER search_puzzles_update AFTER UPDATE ON puzzles BEGIN
+    DELETE FROM search_puzzles WHERE rowid = OLD.id;
+    INSERT INTO search_puzzles (rowid, name, note, answer, round_name)
+    SELECT NEW.id, NEW.name, NEW.note, NEW.answer, rounds.name
+    FROM rounds WHERE rounds.id = NEW.round;
+END;
+
+CREATE TRIGGER search_puzzles_delete AFTER DELETE ON puzzles BEGIN
+    DELETE FROM search_puzzles WHERE rowid = OLD.id;
+END;
+
+CREATE TRIGGER search_rounds_update AFTER UPDATE OF name ON rounds BEGIN
+    UPDATE search_puzzles SET round_name = NEW.name
+    WHERE rowid IN (SELECT id FROM puzzles WHERE round = NEW.id);
+END;
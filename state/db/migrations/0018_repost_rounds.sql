@@ -0,0 +1,5 @@
+-- Rounds that were posted to #qm before approval moved to buttons can't be
+-- approved from their old (reaction-based) message. Clearing message_id makes
+-- discovery post them again.
+UPDATE discovered_rounds SET message_id = ''
+WHERE created_as = 0 AND NOT ignored AND message_id != '';
@@ -0,0 +1,3 @@
+-- Puzzle history written before changes were attributed has no actor. Those
+-- changes can't be traced back to anyone, so attribute them to the syncer.
+UPDATE puzzle_history SET actor = 'sync' WHERE actor = '';
@@ -16,6 +16,20 @@ type Changelog struct {
 	Kind   status.AblyKind `json:"kind"`
 	Puzzle []byte          `json:"puzzle"`
 	Round  []byte          `json:"round"`
+	Actor  string          `json:"actor"`
+	Feed   []byte          `json:"feed"`
+	Guess  []byte          `json:"guess"`
+}
+
+type DiscoveredChange struct {
+	ID         int64     `json:"id"`
+	Hunt       int64     `json:"hunt"`
+	Puzzle     int64     `json:"puzzle"`
+	Name       string    `json:"name"`
+	RoundName  string    `json:"round_name"`
+	Removed    bool      `json:"removed"`
+	MessageID  string    `json:"message_id"`
+	NotifiedAt time.Time `json:"notified_at"`
 }
 
 type DiscoveredPuzzle struct {
@@ -23,6 +37,7 @@ type DiscoveredPuzzle struct {
 	PuzzleURL       string        `json:"puzzle_url"`
 	Name            string        `json:"name"`
 	DiscoveredRound sql.NullInt64 `json:"discovered_round"`
+	Hunt            int64         `json:"hunt"`
 }
 
 type DiscoveredRound struct {
@@ -31,36 +46,118 @@ type DiscoveredRound struct {
 	MessageID  string    `json:"message_id"`
 	NotifiedAt time.Time `json:"notified_at"`
 	CreatedAs  int64     `json:"created_as"`
+	Hunt       int64     `json:"hunt"`
+	Emoji      string    `json:"emoji"`
+	EditedName string    `json:"edited_name"`
+	Ignored    bool      `json:"ignored"`
+}
+
+type Guess struct {
+	ID          int64              `json:"id"`
+	Puzzle      int64              `json:"puzzle"`
+	Guess       string             `json:"guess"`
+	Result      status.GuessResult `json:"result"`
+	Note        string             `json:"note"`
+	Actor       string             `json:"actor"`
+	SubmittedAt time.Time          `json:"submitted_at"`
+}
+
+type Hunt struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Active    bool      `json:"active"`
+	Archived  bool      `json:"archived"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Puzzle struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          int64           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Version        int64           `json:"version"`
+	DeletedAt      sql.NullTime    `json:"-"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+}
+
+type PuzzleFeed struct {
+	Puzzle int64 `json:"puzzle"`
+	Meta   int64 `json:"meta"`
+}
+
+type PuzzleHistory struct {
+	ID        int64     `json:"id"`
+	Puzzle    int64     `json:"puzzle"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedAt time.Time `json:"changed_at"`
+	Actor     string    `json:"actor"`
+}
+
+type PuzzleSolver struct {
+	Puzzle   int64     `json:"puzzle"`
+	Solver   string    `json:"solver"`
+	JoinedAt time.Time `json:"joined_at"`
+	ActiveAt time.Time `json:"active_at"`
+}
+
+type PuzzleTag struct {
+	Puzzle int64  `json:"puzzle"`
+	Tag    string `json:"tag"`
+}
+
+type Reminder struct {
 	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          int64         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
+	Hunt           int64         `json:"hunt"`
+	Puzzle         sql.NullInt64 `json:"puzzle"`
 	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	Message        string        `json:"message"`
+	RemindAt       time.Time     `json:"remind_at"`
+	LeadMinutes    string        `json:"lead_minutes"`
+	EveryMinutes   int64         `json:"every_minutes"`
+	CreatedBy      string        `json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
 }
 
 type Round struct {
-	ID              int64  `json:"id"`
-	Name            string `json:"name"`
-	Emoji           string `json:"emoji"`
-	Hue             int64  `json:"hue"`
-	Sort            int64  `json:"sort"`
-	Special         bool   `json:"special"`
-	DriveFolder     string `json:"drive_folder"`
-	DiscordCategory string `json:"discord_category"`
+	ID              int64        `json:"id"`
+	Name            string       `json:"name"`
+	Emoji           string       `json:"emoji"`
+	Hue             int64        `json:"hue"`
+	Sort            int64        `json:"sort"`
+	Special         bool         `json:"special"`
+	DriveFolder     string       `json:"drive_folder"`
+	DiscordCategory string       `json:"discord_category"`
+	Version         int64        `json:"version"`
+	DeletedAt       sql.NullTime `json:"-"`
+	Hunt            int64        `json:"hunt"`
+}
+
+type SearchMessage struct {
+	Content string `json:"content"`
+	Channel string `json:"channel"`
+}
+
+type SearchPuzzle struct {
+	Name      string `json:"name"`
+	Note      string `json:"note"`
+	Answer    string `json:"answer"`
+	RoundName string `json:"round_name"`
 }
 
 type Setting struct {
+	Hunt  int64  `json:"hunt"`
 	Key   string `json:"key"`
 	Value []byte `json:"value"`
 }
@@ -2,51 +2,132 @@
 SELECT
     p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.id = ?;
+WHERE p.id = ? AND p.deleted_at IS NULL;
 
 -- name: GetPuzzleByChannel :one
 SELECT
     p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.discord_channel = ?;
+WHERE p.discord_channel = ? AND p.deleted_at IS NULL;
 
 -- name: GetPuzzlesByVoiceRoom :many
 SELECT
     p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.voice_room = ?;
+WHERE p.voice_room = ? AND p.deleted_at IS NULL;
 
 -- name: ListPuzzles :many
 SELECT
     p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NULL AND rounds.hunt = ?
 ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
 COLLATE nocase;
 
 -- name: CountPuzzles :one
-SELECT COUNT(*) AS total, SUM(answer != "") AS solved
-FROM puzzles;
+SELECT COUNT(*) AS total, SUM(p.answer != "") AS solved
+FROM puzzles AS p
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NULL AND rounds.hunt = ?;
 
 -- name: ListPuzzlesByRound :many
 SELECT
     p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.round = ?
+WHERE p.round = ? AND p.deleted_at IS NULL
+ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
+COLLATE nocase;
+
+-- name: ListPuzzlesByTag :many
+SELECT
+    p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
+    p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
+FROM puzzles AS p
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.id IN (SELECT puzzle FROM puzzle_tags WHERE tag = ?)
+    AND p.deleted_at IS NULL AND rounds.hunt = ?
 ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
 COLLATE nocase;
 
@@ -54,59 +135,162 @@ COLLATE nocase;
 SELECT p.id, p.name, p.voice_room
 FROM puzzles as p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.voice_room != ""
+WHERE p.voice_room != "" AND p.deleted_at IS NULL
 ORDER BY p.voice_room, rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
 COLLATE nocase;
 
 -- name: CreatePuzzle :one
 INSERT INTO puzzles (
     name, answer, round, status, note, location, puzzle_url,
-    spreadsheet_id, discord_channel, meta, voice_room, reminder
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;
+    spreadsheet_id, discord_channel, meta, voice_room, reminder, priority,
+    needs_help
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;
 
 -- name: UpdatePuzzle :exec
 UPDATE puzzles
 SET name = ?2, answer = ?3, round = ?4, status = ?5, note = ?6,
 location = ?7, puzzle_url = ?8, spreadsheet_id = ?9, discord_channel = ?10,
-meta = ?11, voice_room = ?12, reminder = ?13
+meta = ?11, voice_room = ?12, reminder = ?13, version = ?14,
+deleted_at = ?15, priority = ?16, needs_help = ?17
 WHERE id = ?1;
 
 -- name: ClearPuzzleVoiceRoom :exec
 UPDATE puzzles
-SET voice_room = ""
-WHERE voice_room = ?;
+SET voice_room = "", version = version + 1
+WHERE voice_room = ? AND deleted_at IS NULL;
 
--- name: DeletePuzzle :exec
+-- name: TrashPuzzle :execrows
+UPDATE puzzles
+SET deleted_at = ?2, version = version + 1
+WHERE id = ?1 AND deleted_at IS NULL;
+
+-- name: RestorePuzzle :execrows
+UPDATE puzzles
+SET deleted_at = NULL, version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: ListTrashedPuzzles :many
+SELECT
+    p.id, p.name, p.answer, sqlc.embed(rounds), p.status, p.note,
+    p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers,
+    p.deleted_at
+FROM puzzles AS p
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NOT NULL AND rounds.hunt = ?
+ORDER BY p.deleted_at DESC;
+
+-- name: PurgeTrashedPuzzles :execrows
 DELETE FROM puzzles
-WHERE id = ?;
+WHERE deleted_at IS NOT NULL
+    AND round IN (SELECT id FROM rounds WHERE hunt = ?);
+
+-- name: CreatePuzzleTag :execrows
+INSERT OR IGNORE INTO puzzle_tags (puzzle, tag)
+VALUES (?, ?);
+
+-- name: DeletePuzzleTag :execrows
+DELETE FROM puzzle_tags
+WHERE puzzle = ? AND tag = ?;
+
+-- name: CreatePuzzleSolver :execrows
+INSERT OR IGNORE INTO puzzle_solvers (puzzle, solver, joined_at, active_at)
+VALUES (?, ?, ?, ?);
+
+-- name: DeletePuzzleSolver :execrows
+DELETE FROM puzzle_solvers
+WHERE puzzle = ? AND solver = ?;
+
+-- name: ListPuzzleSolvers :many
+SELECT * FROM puzzle_solvers
+WHERE puzzle = ?
+ORDER BY joined_at, solver;
+
+-- name: TouchPuzzleSolver :exec
+UPDATE puzzle_solvers
+SET active_at = sqlc.arg(active_at)
+WHERE solver = sqlc.arg(solver) AND active_at < sqlc.arg(active_at)
+    AND puzzle IN (
+        SELECT id FROM puzzles WHERE discord_channel = sqlc.arg(discord_channel)
+    );
+
+-- name: TouchPuzzleSolversBySpreadsheet :exec
+UPDATE puzzle_solvers
+SET active_at = sqlc.arg(active_at)
+WHERE active_at < sqlc.arg(active_at)
+    AND puzzle IN (
+        SELECT id FROM puzzles WHERE spreadsheet_id = sqlc.arg(spreadsheet_id)
+    );
+
+-- name: ListIdlePuzzleSolvers :many
+SELECT puzzle_solvers.* FROM puzzle_solvers
+INNER JOIN puzzles AS p ON puzzle_solvers.puzzle = p.id
+INNER JOIN rounds ON p.round = rounds.id
+WHERE puzzle_solvers.active_at < sqlc.arg(active_before)
+    AND p.deleted_at IS NULL AND rounds.hunt = sqlc.arg(hunt)
+ORDER BY puzzle_solvers.active_at;
 
 
 -- name: GetRound :one
 SELECT * FROM rounds
-WHERE id = ? LIMIT 1;
+WHERE id = ? AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListRounds :many
 SELECT * FROM rounds
+WHERE deleted_at IS NULL AND hunt = ?
 ORDER BY special DESC, sort, id
 COLLATE nocase;
 
 -- name: CountRounds :one
-SELECT COUNT(*) AS total FROM rounds WHERE NOT special;
+SELECT COUNT(*) AS total FROM rounds
+WHERE NOT special AND deleted_at IS NULL AND hunt = ?;
+
+-- name: CountPuzzlesInRound :one
+SELECT COUNT(*) FROM puzzles
+WHERE round = ? AND deleted_at IS NULL;
 
 -- name: CreateRound :one
 INSERT INTO rounds (
-    name, emoji, hue, sort, special, drive_folder, discord_category
-) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING *;
+    name, emoji, hue, sort, special, drive_folder, discord_category, hunt
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: UpdateRound :exec
 UPDATE rounds
 SET name = ?2, emoji = ?3, hue = ?4, sort = ?5, special = ?6,
-    drive_folder = ?7, discord_category = ?8
+    drive_folder = ?7, discord_category = ?8, version = ?9, deleted_at = ?10,
+    hunt = ?11
 WHERE id = ?1;
 
--- name: DeleteRound :exec
+-- name: TrashRound :execrows
+UPDATE rounds
+SET deleted_at = ?2, version = version + 1
+WHERE id = ?1 AND deleted_at IS NULL;
+
+-- name: RestoreRound :execrows
+UPDATE rounds
+SET deleted_at = NULL, version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: ListTrashedRounds :many
+SELECT * FROM rounds
+WHERE deleted_at IS NOT NULL AND hunt = ?
+ORDER BY deleted_at DESC;
+
+-- name: PurgeTrashedRounds :execrows
 DELETE FROM rounds
-WHERE id = ?;
+WHERE deleted_at IS NOT NULL AND hunt = ?
+    AND id NOT IN (SELECT round FROM puzzles);
 
 
 -- name: ListChangelog :many
@@ -115,8 +299,8 @@ ORDER BY id;
 
 -- name: CreateChangelog :exec
 INSERT INTO changelog (
-    id, kind, puzzle, round
-) VALUES (?, ?, ?, ?);
+    id, kind, puzzle, round, feed, guess, actor
+) VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: PruneChangelog :exec
 DELETE FROM changelog
@@ -130,46 +314,165 @@ WHERE id NOT IN (
 SELECT max(id) FROM changelog;
 
 
+-- name: CreatePuzzleHistory :exec
+INSERT INTO puzzle_history (
+    puzzle, field, old_value, new_value, changed_at, actor
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListAllPuzzleHistory :many
+SELECT * FROM puzzle_history
+WHERE puzzle IN (
+    SELECT puzzles.id FROM puzzles
+    INNER JOIN rounds ON puzzles.round = rounds.id
+    WHERE rounds.hunt = ?
+)
+ORDER BY id;
+
+-- name: ListPuzzleHistory :many
+SELECT * FROM puzzle_history
+WHERE puzzle = ?
+ORDER BY id;
+
+
+-- name: ListPuzzleFeeds :many
+SELECT * FROM puzzle_feeds
+WHERE puzzle NOT IN (SELECT id FROM puzzles WHERE deleted_at IS NOT NULL)
+    AND meta NOT IN (SELECT id FROM puzzles WHERE deleted_at IS NOT NULL)
+    AND meta IN (
+        SELECT puzzles.id FROM puzzles
+        INNER JOIN rounds ON puzzles.round = rounds.id
+        WHERE rounds.hunt = ?
+    )
+ORDER BY meta, puzzle;
+
+-- name: ListFeeders :many
+SELECT p.id, p.name, p.answer, p.discord_channel
+FROM puzzle_feeds AS f
+INNER JOIN puzzles AS p ON f.puzzle = p.id
+WHERE f.meta = ? AND p.deleted_at IS NULL
+ORDER BY p.name COLLATE nocase;
+
+-- name: ListFedMetas :many
+SELECT p.id, p.name, p.answer, p.discord_channel
+FROM puzzle_feeds AS f
+INNER JOIN puzzles AS p ON f.meta = p.id
+WHERE f.puzzle = ? AND p.deleted_at IS NULL
+ORDER BY p.name COLLATE nocase;
+
+-- name: CreatePuzzleFeed :execrows
+INSERT OR IGNORE INTO puzzle_feeds (puzzle, meta)
+VALUES (?, ?);
+
+-- name: DeletePuzzleFeed :execrows
+DELETE FROM puzzle_feeds
+WHERE puzzle = ? AND meta = ?;
+
+
+-- name: ListGuesses :many
+SELECT * FROM guesses
+WHERE puzzle = ?
+ORDER BY id;
+
+-- name: ListAllGuesses :many
+SELECT * FROM guesses
+WHERE puzzle IN (
+    SELECT puzzles.id FROM puzzles
+    INNER JOIN rounds ON puzzles.round = rounds.id
+    WHERE rounds.hunt = ?
+)
+ORDER BY id;
+
+-- name: CreateGuess :one
+INSERT INTO guesses (
+    puzzle, guess, result, note, actor, submitted_at
+) VALUES (?, ?, ?, ?, ?, ?) RETURNING *;
+
+
+-- name: SearchPuzzles :many
+SELECT
+    p.id, p.name, p.answer, p.discord_channel,
+    rounds.name AS round_name, rounds.emoji AS round_emoji,
+    CAST(min(hits.rank) AS REAL) AS rank
+FROM (
+    SELECT search_puzzles.rowid AS puzzle, search_puzzles.rank AS rank
+    FROM search_puzzles
+    WHERE search_puzzles MATCH sqlc.arg(query)
+    UNION ALL
+    -- discussion is a weaker signal than the puzzle's own fields
+    SELECT puzzles.id AS puzzle, search_messages.rank * 0.5 AS rank
+    FROM search_messages
+    INNER JOIN puzzles ON search_messages.channel = puzzles.discord_channel
+    WHERE search_messages MATCH sqlc.arg(query)
+) AS hits
+INNER JOIN puzzles AS p ON hits.puzzle = p.id
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NULL AND rounds.hunt = sqlc.arg(hunt)
+GROUP BY p.id
+ORDER BY rank
+LIMIT sqlc.arg(max_results);
+
+-- name: CreateSearchMessage :exec
+INSERT OR REPLACE INTO search_messages (rowid, content, channel)
+VALUES (?, ?, ?);
+
+-- name: DeleteSearchMessage :exec
+DELETE FROM search_messages
+WHERE rowid = ?;
+
+
 -- name: GetSetting :one
 SELECT value from settings
-WHERE key = ?;
+WHERE hunt = ? AND key = ?;
 
 -- name: UpdateSetting :exec
-INSERT OR REPLACE INTO settings (key, value)
-VALUES (?, ?);
+INSERT OR REPLACE INTO settings (hunt, key, value)
+VALUES (?, ?, ?);
 
 
 -- name: CheckPuzzleIsCreated :one
 SELECT COUNT(*) FROM puzzles
-WHERE name = ? OR puzzle_url = ? COLLATE nocase;
+INNER JOIN rounds ON puzzles.round = rounds.id
+WHERE rounds.hunt = ? AND (
+    puzzles.name = ? OR puzzles.puzzle_url = ? COLLATE nocase
+);
 
 -- name: CheckPuzzleIsDiscovered :one
 SELECT COUNT(*) FROM discovered_puzzles
-WHERE name = ? OR puzzle_url = ? COLLATE nocase;
+WHERE hunt = ? AND (name = ? OR puzzle_url = ? COLLATE nocase);
 
 -- name: GetCreatedRound :one
 SELECT * FROM rounds
-WHERE name = ? COLLATE nocase;
+WHERE hunt = ? AND name = ? COLLATE nocase AND deleted_at IS NULL;
 
 -- name: GetDiscoveredRound :one
 SELECT * FROM discovered_rounds
-WHERE name = ? COLLATE nocase;
+WHERE hunt = ? AND name = ? COLLATE nocase;
 
 -- name: CreateDiscoveredPuzzle :exec
-INSERT INTO discovered_puzzles (puzzle_url, name, discovered_round)
-VALUES (?, ?, ?);
+INSERT INTO discovered_puzzles (puzzle_url, name, discovered_round, hunt)
+VALUES (?, ?, ?, ?);
 
 -- name: CreateDiscoveredRound :one
-INSERT INTO discovered_rounds (name, message_id, notified_at, created_as)
-VALUES (?, ?, ?, ?) RETURNING id;
+INSERT INTO discovered_rounds (name, message_id, notified_at, created_as, hunt)
+VALUES (?, ?, ?, ?, ?) RETURNING id;
 
 -- name: UpdateDiscoveredRound :exec
 UPDATE discovered_rounds
-SET name = ?2, message_id = ?3, notified_at = ?4, created_as = ?5
+SET name = ?2, message_id = ?3, notified_at = ?4, created_as = ?5, hunt = ?6,
+    emoji = ?7, edited_name = ?8, ignored = ?9
 WHERE id = ?1;
 
+-- name: GetDiscoveredRoundByID :one
+SELECT * FROM discovered_rounds WHERE id = ?;
+
 -- name: ListPendingDiscoveredRounds :many
-SELECT * FROM discovered_rounds WHERE created_as = 0;
+SELECT * FROM discovered_rounds WHERE hunt = ? AND created_as = 0 AND NOT ignored;
+
+-- name: ListDiscoveredRounds :many
+SELECT * FROM discovered_rounds WHERE hunt = ? ORDER BY id;
+
+-- name: ListDiscoveredPuzzles :many
+SELECT * FROM discovered_puzzles WHERE hunt = ? ORDER BY id;
 
 -- name: ListDiscoveredPuzzlesForRound :many
 SELECT * FROM discovered_puzzles WHERE discovered_round = ?;
@@ -182,7 +485,74 @@ SELECT *
 FROM discovered_puzzles
 INNER JOIN discovered_rounds
 ON discovered_puzzles.discovered_round = discovered_rounds.id
-WHERE discovered_rounds.created_as != 0;
+WHERE discovered_rounds.hunt = ? AND discovered_rounds.created_as != 0;
 
 -- name: CompleteDiscoveredPuzzle :exec
 UPDATE discovered_puzzles SET discovered_round = NULL WHERE id = ?;
+
+-- name: CheckDiscoveredChange :one
+SELECT COUNT(*) FROM discovered_changes
+WHERE puzzle = ? AND name = ? AND round_name = ? AND removed = ?;
+
+-- name: CreateDiscoveredChange :exec
+INSERT INTO discovered_changes (
+    hunt, puzzle, name, round_name, removed, message_id, notified_at
+) VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetDiscoveredChange :one
+SELECT * FROM discovered_changes WHERE id = ?;
+
+-- name: ListPendingDiscoveredChanges :many
+SELECT * FROM discovered_changes WHERE hunt = ? AND message_id = '' ORDER BY id;
+
+-- name: UpdateDiscoveredChange :exec
+UPDATE discovered_changes SET message_id = ?2, notified_at = ?3 WHERE id = ?1;
+
+
+-- name: GetHunt :one
+SELECT * FROM hunts
+WHERE id = ?;
+
+-- name: GetActiveHunt :one
+SELECT * FROM hunts
+WHERE active;
+
+-- name: ListHunts :many
+SELECT * FROM hunts
+ORDER BY id;
+
+-- name: CreateHunt :one
+INSERT INTO hunts (name, active, archived, created_at)
+VALUES (?, FALSE, FALSE, ?) RETURNING *;
+
+-- name: UpdateHunt :exec
+UPDATE hunts
+SET name = ?2, archived = ?3
+WHERE id = ?1;
+
+-- name: DeactivateHunts :exec
+UPDATE hunts
+SET active = FALSE
+WHERE active;
+
+-- name: ActivateHunt :execrows
+UPDATE hunts
+SET active = TRUE
+WHERE id = ? AND NOT archived;
+
+
+-- name: CreateReminder :one
+INSERT INTO reminders (
+    hunt, puzzle, discord_channel, message, remind_at, lead_minutes,
+    every_minutes, created_by, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
+
+-- name: ListReminders :many
+SELECT * FROM reminders
+WHERE hunt = ?
+    AND (puzzle IS NULL OR puzzle NOT IN (SELECT id FROM puzzles WHERE deleted_at IS NOT NULL))
+ORDER BY remind_at, id;
+
+-- name: DeleteReminder :execrows
+DELETE FROM reminders
+WHERE id = ? AND hunt = ?;
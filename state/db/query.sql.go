@@ -13,18 +13,60 @@ import (
 	"github.com/emojihunt/emojihunt/state/status"
 )
 
+const activateHunt = `-- name: ActivateHunt :execrows
+UPDATE hunts
+SET active = TRUE
+WHERE id = ? AND NOT archived
+`
+
+func (q *Queries) ActivateHunt(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, activateHunt, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const checkDiscoveredChange = `-- name: CheckDiscoveredChange :one
+SELECT COUNT(*) FROM discovered_changes
+WHERE puzzle = ? AND name = ? AND round_name = ? AND removed = ?
+`
+
+type CheckDiscoveredChangeParams struct {
+	Puzzle    int64  `json:"puzzle"`
+	Name      string `json:"name"`
+	RoundName string `json:"round_name"`
+	Removed   bool   `json:"removed"`
+}
+
+func (q *Queries) CheckDiscoveredChange(ctx context.Context, arg CheckDiscoveredChangeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, checkDiscoveredChange,
+		arg.Puzzle,
+		arg.Name,
+		arg.RoundName,
+		arg.Removed,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const checkPuzzleIsCreated = `-- name: CheckPuzzleIsCreated :one
 SELECT COUNT(*) FROM puzzles
-WHERE name = ? OR puzzle_url = ? COLLATE nocase
+INNER JOIN rounds ON puzzles.round = rounds.id
+WHERE rounds.hunt = ? AND (
+    puzzles.name = ? OR puzzles.puzzle_url = ? COLLATE nocase
+)
 `
 
 type CheckPuzzleIsCreatedParams struct {
+	Hunt      int64  `json:"hunt"`
 	Name      string `json:"name"`
 	PuzzleURL string `json:"puzzle_url"`
 }
 
 func (q *Queries) CheckPuzzleIsCreated(ctx context.Context, arg CheckPuzzleIsCreatedParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, checkPuzzleIsCreated, arg.Name, arg.PuzzleURL)
+	row := q.db.QueryRowContext(ctx, checkPuzzleIsCreated, arg.Hunt, arg.Name, arg.PuzzleURL)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -32,16 +74,17 @@ func (q *Queries) CheckPuzzleIsCreated(ctx context.Context, arg CheckPuzzleIsCre
 
 const checkPuzzleIsDiscovered = `-- name: CheckPuzzleIsDiscovered :one
 SELECT COUNT(*) FROM discovered_puzzles
-WHERE name = ? OR puzzle_url = ? COLLATE nocase
+WHERE hunt = ? AND (name = ? OR puzzle_url = ? COLLATE nocase)
 `
 
 type CheckPuzzleIsDiscoveredParams struct {
+	Hunt      int64  `json:"hunt"`
 	Name      string `json:"name"`
 	PuzzleURL string `json:"puzzle_url"`
 }
 
 func (q *Queries) CheckPuzzleIsDiscovered(ctx context.Context, arg CheckPuzzleIsDiscoveredParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, checkPuzzleIsDiscovered, arg.Name, arg.PuzzleURL)
+	row := q.db.QueryRowContext(ctx, checkPuzzleIsDiscovered, arg.Hunt, arg.Name, arg.PuzzleURL)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -49,8 +92,8 @@ func (q *Queries) CheckPuzzleIsDiscovered(ctx context.Context, arg CheckPuzzleIs
 
 const clearPuzzleVoiceRoom = `-- name: ClearPuzzleVoiceRoom :exec
 UPDATE puzzles
-SET voice_room = ""
-WHERE voice_room = ?
+SET voice_room = "", version = version + 1
+WHERE voice_room = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) ClearPuzzleVoiceRoom(ctx context.Context, voiceRoom string) error {
@@ -82,8 +125,10 @@ func (q *Queries) CompleteDiscoveredRound(ctx context.Context, arg CompleteDisco
 }
 
 const countPuzzles = `-- name: CountPuzzles :one
-SELECT COUNT(*) AS total, SUM(answer != "") AS solved
-FROM puzzles
+SELECT COUNT(*) AS total, SUM(p.answer != "") AS solved
+FROM puzzles AS p
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NULL AND rounds.hunt = ?
 `
 
 type CountPuzzlesRow struct {
@@ -91,19 +136,32 @@ type CountPuzzlesRow struct {
 	Solved sql.NullFloat64 `json:"solved"`
 }
 
-func (q *Queries) CountPuzzles(ctx context.Context) (CountPuzzlesRow, error) {
-	row := q.db.QueryRowContext(ctx, countPuzzles)
+func (q *Queries) CountPuzzles(ctx context.Context, hunt int64) (CountPuzzlesRow, error) {
+	row := q.db.QueryRowContext(ctx, countPuzzles, hunt)
 	var i CountPuzzlesRow
 	err := row.Scan(&i.Total, &i.Solved)
 	return i, err
 }
 
+const countPuzzlesInRound = `-- name: CountPuzzlesInRound :one
+SELECT COUNT(*) FROM puzzles
+WHERE round = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) CountPuzzlesInRound(ctx context.Context, round int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPuzzlesInRound, round)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countRounds = `-- name: CountRounds :one
-SELECT COUNT(*) AS total FROM rounds WHERE NOT special
+SELECT COUNT(*) AS total FROM rounds
+WHERE NOT special AND deleted_at IS NULL AND hunt = ?
 `
 
-func (q *Queries) CountRounds(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countRounds)
+func (q *Queries) CountRounds(ctx context.Context, hunt int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRounds, hunt)
 	var total int64
 	err := row.Scan(&total)
 	return total, err
@@ -111,8 +169,8 @@ func (q *Queries) CountRounds(ctx context.Context) (int64, error) {
 
 const createChangelog = `-- name: CreateChangelog :exec
 INSERT INTO changelog (
-    id, kind, puzzle, round
-) VALUES (?, ?, ?, ?)
+    id, kind, puzzle, round, feed, guess, actor
+) VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateChangelogParams struct {
@@ -120,6 +178,9 @@ type CreateChangelogParams struct {
 	Kind   status.AblyKind `json:"kind"`
 	Puzzle []byte          `json:"puzzle"`
 	Round  []byte          `json:"round"`
+	Feed   []byte          `json:"feed"`
+	Guess  []byte          `json:"guess"`
+	Actor  string          `json:"actor"`
 }
 
 func (q *Queries) CreateChangelog(ctx context.Context, arg CreateChangelogParams) error {
@@ -128,29 +189,67 @@ func (q *Queries) CreateChangelog(ctx context.Context, arg CreateChangelogParams
 		arg.Kind,
 		arg.Puzzle,
 		arg.Round,
+		arg.Feed,
+		arg.Guess,
+		arg.Actor,
+	)
+	return err
+}
+
+const createDiscoveredChange = `-- name: CreateDiscoveredChange :exec
+INSERT INTO discovered_changes (
+    hunt, puzzle, name, round_name, removed, message_id, notified_at
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateDiscoveredChangeParams struct {
+	Hunt       int64     `json:"hunt"`
+	Puzzle     int64     `json:"puzzle"`
+	Name       string    `json:"name"`
+	RoundName  string    `json:"round_name"`
+	Removed    bool      `json:"removed"`
+	MessageID  string    `json:"message_id"`
+	NotifiedAt time.Time `json:"notified_at"`
+}
+
+func (q *Queries) CreateDiscoveredChange(ctx context.Context, arg CreateDiscoveredChangeParams) error {
+	_, err := q.db.ExecContext(ctx, createDiscoveredChange,
+		arg.Hunt,
+		arg.Puzzle,
+		arg.Name,
+		arg.RoundName,
+		arg.Removed,
+		arg.MessageID,
+		arg.NotifiedAt,
 	)
 	return err
 }
 
 const createDiscoveredPuzzle = `-- name: CreateDiscoveredPuzzle :exec
-INSERT INTO discovered_puzzles (puzzle_url, name, discovered_round)
-VALUES (?, ?, ?)
+INSERT INTO discovered_puzzles (puzzle_url, name, discovered_round, hunt)
+VALUES (?, ?, ?, ?)
 `
 
 type CreateDiscoveredPuzzleParams struct {
 	PuzzleURL       string        `json:"puzzle_url"`
 	Name            string        `json:"name"`
 	DiscoveredRound sql.NullInt64 `json:"discovered_round"`
+	Hunt            int64         `json:"hunt"`
 }
 
 func (q *Queries) CreateDiscoveredPuzzle(ctx context.Context, arg CreateDiscoveredPuzzleParams) error {
-	_, err := q.db.ExecContext(ctx, createDiscoveredPuzzle, arg.PuzzleURL, arg.Name, arg.DiscoveredRound)
+	_, err := q.db.ExecContext(ctx, createDiscoveredPuzzle,
+		arg.PuzzleURL,
+		arg.Name,
+		arg.DiscoveredRound,
+		arg.Hunt,
+	)
 	return err
 }
 
 const createDiscoveredRound = `-- name: CreateDiscoveredRound :one
-INSERT INTO discovered_rounds (name, message_id, notified_at, created_as)
-VALUES (?, ?, ?, ?) RETURNING id
+INSERT INTO discovered_rounds (name, message_id, notified_at, created_as, hunt)
+VALUES (?, ?, ?, ?, ?) RETURNING id
 `
 
 type CreateDiscoveredRoundParams struct {
@@ -158,6 +257,7 @@ type CreateDiscoveredRoundParams struct {
 	MessageID  string    `json:"message_id"`
 	NotifiedAt time.Time `json:"notified_at"`
 	CreatedAs  int64     `json:"created_as"`
+	Hunt       int64     `json:"hunt"`
 }
 
 func (q *Queries) CreateDiscoveredRound(ctx context.Context, arg CreateDiscoveredRoundParams) (int64, error) {
@@ -166,32 +266,96 @@ func (q *Queries) CreateDiscoveredRound(ctx context.Context, arg CreateDiscovere
 		arg.MessageID,
 		arg.NotifiedAt,
 		arg.CreatedAs,
+		arg.Hunt,
 	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const createGuess = `-- name: CreateGuess :one
+INSERT INTO guesses (
+    puzzle, guess, result, note, actor, submitted_at
+) VALUES (?, ?, ?, ?, ?, ?) RETURNING id, puzzle, guess, result, note, actor, submitted_at
+`
+
+type CreateGuessParams struct {
+	Puzzle      int64              `json:"puzzle"`
+	Guess       string             `json:"guess"`
+	Result      status.GuessResult `json:"result"`
+	Note        string             `json:"note"`
+	Actor       string             `json:"actor"`
+	SubmittedAt time.Time          `json:"submitted_at"`
+}
+
+func (q *Queries) CreateGuess(ctx context.Context, arg CreateGuessParams) (Guess, error) {
+	row := q.db.QueryRowContext(ctx, createGuess,
+		arg.Puzzle,
+		arg.Guess,
+		arg.Result,
+		arg.Note,
+		arg.Actor,
+		arg.SubmittedAt,
+	)
+	var i Guess
+	err := row.Scan(
+		&i.ID,
+		&i.Puzzle,
+		&i.Guess,
+		&i.Result,
+		&i.Note,
+		&i.Actor,
+		&i.SubmittedAt,
+	)
+	return i, err
+}
+
+const createHunt = `-- name: CreateHunt :one
+INSERT INTO hunts (name, active, archived, created_at)
+VALUES (?, FALSE, FALSE, ?) RETURNING id, name, active, archived, created_at
+`
+
+type CreateHuntParams struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) CreateHunt(ctx context.Context, arg CreateHuntParams) (Hunt, error) {
+	row := q.db.QueryRowContext(ctx, createHunt, arg.Name, arg.CreatedAt)
+	var i Hunt
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Active,
+		&i.Archived,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createPuzzle = `-- name: CreatePuzzle :one
 INSERT INTO puzzles (
     name, answer, round, status, note, location, puzzle_url,
-    spreadsheet_id, discord_channel, meta, voice_room, reminder
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
+    spreadsheet_id, discord_channel, meta, voice_room, reminder, priority,
+    needs_help
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id
 `
 
 type CreatePuzzleParams struct {
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          int64         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          int64           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
 }
 
 func (q *Queries) CreatePuzzle(ctx context.Context, arg CreatePuzzleParams) (int64, error) {
@@ -208,16 +372,153 @@ func (q *Queries) CreatePuzzle(ctx context.Context, arg CreatePuzzleParams) (int
 		arg.Meta,
 		arg.VoiceRoom,
 		arg.Reminder,
+		arg.Priority,
+		arg.NeedsHelp,
 	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const createPuzzleFeed = `-- name: CreatePuzzleFeed :execrows
+INSERT OR IGNORE INTO puzzle_feeds (puzzle, meta)
+VALUES (?, ?)
+`
+
+type CreatePuzzleFeedParams struct {
+	Puzzle int64 `json:"puzzle"`
+	Meta   int64 `json:"meta"`
+}
+
+func (q *Queries) CreatePuzzleFeed(ctx context.Context, arg CreatePuzzleFeedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPuzzleFeed, arg.Puzzle, arg.Meta)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createPuzzleHistory = `-- name: CreatePuzzleHistory :exec
+INSERT INTO puzzle_history (
+    puzzle, field, old_value, new_value, changed_at, actor
+) VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreatePuzzleHistoryParams struct {
+	Puzzle    int64     `json:"puzzle"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	ChangedAt time.Time `json:"changed_at"`
+	Actor     string    `json:"actor"`
+}
+
+func (q *Queries) CreatePuzzleHistory(ctx context.Context, arg CreatePuzzleHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createPuzzleHistory,
+		arg.Puzzle,
+		arg.Field,
+		arg.OldValue,
+		arg.NewValue,
+		arg.ChangedAt,
+		arg.Actor,
+	)
+	return err
+}
+
+const createPuzzleSolver = `-- name: CreatePuzzleSolver :execrows
+INSERT OR IGNORE INTO puzzle_solvers (puzzle, solver, joined_at, active_at)
+VALUES (?, ?, ?, ?)
+`
+
+type CreatePuzzleSolverParams struct {
+	Puzzle   int64     `json:"puzzle"`
+	Solver   string    `json:"solver"`
+	JoinedAt time.Time `json:"joined_at"`
+	ActiveAt time.Time `json:"active_at"`
+}
+
+func (q *Queries) CreatePuzzleSolver(ctx context.Context, arg CreatePuzzleSolverParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPuzzleSolver,
+		arg.Puzzle,
+		arg.Solver,
+		arg.JoinedAt,
+		arg.ActiveAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createPuzzleTag = `-- name: CreatePuzzleTag :execrows
+INSERT OR IGNORE INTO puzzle_tags (puzzle, tag)
+VALUES (?, ?)
+`
+
+type CreatePuzzleTagParams struct {
+	Puzzle int64  `json:"puzzle"`
+	Tag    string `json:"tag"`
+}
+
+func (q *Queries) CreatePuzzleTag(ctx context.Context, arg CreatePuzzleTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPuzzleTag, arg.Puzzle, arg.Tag)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createReminder = `-- name: CreateReminder :one
+INSERT INTO reminders (
+    hunt, puzzle, discord_channel, message, remind_at, lead_minutes,
+    every_minutes, created_by, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, hunt, puzzle, discord_channel, message, remind_at, lead_minutes, every_minutes, created_by, created_at
+`
+
+type CreateReminderParams struct {
+	Hunt           int64         `json:"hunt"`
+	Puzzle         sql.NullInt64 `json:"puzzle"`
+	DiscordChannel string        `json:"discord_channel"`
+	Message        string        `json:"message"`
+	RemindAt       time.Time     `json:"remind_at"`
+	LeadMinutes    string        `json:"lead_minutes"`
+	EveryMinutes   int64         `json:"every_minutes"`
+	CreatedBy      string        `json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+func (q *Queries) CreateReminder(ctx context.Context, arg CreateReminderParams) (Reminder, error) {
+	row := q.db.QueryRowContext(ctx, createReminder,
+		arg.Hunt,
+		arg.Puzzle,
+		arg.DiscordChannel,
+		arg.Message,
+		arg.RemindAt,
+		arg.LeadMinutes,
+		arg.EveryMinutes,
+		arg.CreatedBy,
+		arg.CreatedAt,
+	)
+	var i Reminder
+	err := row.Scan(
+		&i.ID,
+		&i.Hunt,
+		&i.Puzzle,
+		&i.DiscordChannel,
+		&i.Message,
+		&i.RemindAt,
+		&i.LeadMinutes,
+		&i.EveryMinutes,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createRound = `-- name: CreateRound :one
 INSERT INTO rounds (
-    name, emoji, hue, sort, special, drive_folder, discord_category
-) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id, name, emoji, hue, sort, special, drive_folder, discord_category
+    name, emoji, hue, sort, special, drive_folder, discord_category, hunt
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, name, emoji, hue, sort, special, drive_folder, discord_category, version, deleted_at, hunt
 `
 
 type CreateRoundParams struct {
@@ -228,6 +529,7 @@ type CreateRoundParams struct {
 	Special         bool   `json:"special"`
 	DriveFolder     string `json:"drive_folder"`
 	DiscordCategory string `json:"discord_category"`
+	Hunt            int64  `json:"hunt"`
 }
 
 func (q *Queries) CreateRound(ctx context.Context, arg CreateRoundParams) (Round, error) {
@@ -239,6 +541,7 @@ func (q *Queries) CreateRound(ctx context.Context, arg CreateRoundParams) (Round
 		arg.Special,
 		arg.DriveFolder,
 		arg.DiscordCategory,
+		arg.Hunt,
 	)
 	var i Round
 	err := row.Scan(
@@ -250,37 +553,152 @@ func (q *Queries) CreateRound(ctx context.Context, arg CreateRoundParams) (Round
 		&i.Special,
 		&i.DriveFolder,
 		&i.DiscordCategory,
+		&i.Version,
+		&i.DeletedAt,
+		&i.Hunt,
 	)
 	return i, err
 }
 
-const deletePuzzle = `-- name: DeletePuzzle :exec
-DELETE FROM puzzles
-WHERE id = ?
+const createSearchMessage = `-- name: CreateSearchMessage :exec
+INSERT OR REPLACE INTO search_messages (rowid, content, channel)
+VALUES (?, ?, ?)
 `
 
-func (q *Queries) DeletePuzzle(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deletePuzzle, id)
+type CreateSearchMessageParams struct {
+	Rowid   int64  `json:"rowid"`
+	Content string `json:"content"`
+	Channel string `json:"channel"`
+}
+
+func (q *Queries) CreateSearchMessage(ctx context.Context, arg CreateSearchMessageParams) error {
+	_, err := q.db.ExecContext(ctx, createSearchMessage, arg.Rowid, arg.Content, arg.Channel)
 	return err
 }
 
-const deleteRound = `-- name: DeleteRound :exec
-DELETE FROM rounds
-WHERE id = ?
+const deactivateHunts = `-- name: DeactivateHunts :exec
+UPDATE hunts
+SET active = FALSE
+WHERE active
+`
+
+func (q *Queries) DeactivateHunts(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deactivateHunts)
+	return err
+}
+
+const deletePuzzleFeed = `-- name: DeletePuzzleFeed :execrows
+DELETE FROM puzzle_feeds
+WHERE puzzle = ? AND meta = ?
+`
+
+type DeletePuzzleFeedParams struct {
+	Puzzle int64 `json:"puzzle"`
+	Meta   int64 `json:"meta"`
+}
+
+func (q *Queries) DeletePuzzleFeed(ctx context.Context, arg DeletePuzzleFeedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePuzzleFeed, arg.Puzzle, arg.Meta)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePuzzleSolver = `-- name: DeletePuzzleSolver :execrows
+DELETE FROM puzzle_solvers
+WHERE puzzle = ? AND solver = ?
+`
+
+type DeletePuzzleSolverParams struct {
+	Puzzle int64  `json:"puzzle"`
+	Solver string `json:"solver"`
+}
+
+func (q *Queries) DeletePuzzleSolver(ctx context.Context, arg DeletePuzzleSolverParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePuzzleSolver, arg.Puzzle, arg.Solver)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePuzzleTag = `-- name: DeletePuzzleTag :execrows
+DELETE FROM puzzle_tags
+WHERE puzzle = ? AND tag = ?
+`
+
+type DeletePuzzleTagParams struct {
+	Puzzle int64  `json:"puzzle"`
+	Tag    string `json:"tag"`
+}
+
+func (q *Queries) DeletePuzzleTag(ctx context.Context, arg DeletePuzzleTagParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePuzzleTag, arg.Puzzle, arg.Tag)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteReminder = `-- name: DeleteReminder :execrows
+DELETE FROM reminders
+WHERE id = ? AND hunt = ?
+`
+
+type DeleteReminderParams struct {
+	ID   int64 `json:"id"`
+	Hunt int64 `json:"hunt"`
+}
+
+func (q *Queries) DeleteReminder(ctx context.Context, arg DeleteReminderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteReminder, arg.ID, arg.Hunt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSearchMessage = `-- name: DeleteSearchMessage :exec
+DELETE FROM search_messages
+WHERE rowid = ?
 `
 
-func (q *Queries) DeleteRound(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteRound, id)
+func (q *Queries) DeleteSearchMessage(ctx context.Context, rowid int64) error {
+	_, err := q.db.ExecContext(ctx, deleteSearchMessage, rowid)
 	return err
 }
 
+const getActiveHunt = `-- name: GetActiveHunt :one
+SELECT id, name, active, archived, created_at FROM hunts
+WHERE active
+`
+
+func (q *Queries) GetActiveHunt(ctx context.Context) (Hunt, error) {
+	row := q.db.QueryRowContext(ctx, getActiveHunt)
+	var i Hunt
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Active,
+		&i.Archived,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getCreatedRound = `-- name: GetCreatedRound :one
-SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category FROM rounds
-WHERE name = ? COLLATE nocase
+SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category, version, deleted_at, hunt FROM rounds
+WHERE hunt = ? AND name = ? COLLATE nocase AND deleted_at IS NULL
 `
 
-func (q *Queries) GetCreatedRound(ctx context.Context, name string) (Round, error) {
-	row := q.db.QueryRowContext(ctx, getCreatedRound, name)
+type GetCreatedRoundParams struct {
+	Hunt int64  `json:"hunt"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) GetCreatedRound(ctx context.Context, arg GetCreatedRoundParams) (Round, error) {
+	row := q.db.QueryRowContext(ctx, getCreatedRound, arg.Hunt, arg.Name)
 	var i Round
 	err := row.Scan(
 		&i.ID,
@@ -291,17 +709,66 @@ func (q *Queries) GetCreatedRound(ctx context.Context, name string) (Round, erro
 		&i.Special,
 		&i.DriveFolder,
 		&i.DiscordCategory,
+		&i.Version,
+		&i.DeletedAt,
+		&i.Hunt,
+	)
+	return i, err
+}
+
+const getDiscoveredChange = `-- name: GetDiscoveredChange :one
+SELECT id, hunt, puzzle, name, round_name, removed, message_id, notified_at FROM discovered_changes WHERE id = ?
+`
+
+func (q *Queries) GetDiscoveredChange(ctx context.Context, id int64) (DiscoveredChange, error) {
+	row := q.db.QueryRowContext(ctx, getDiscoveredChange, id)
+	var i DiscoveredChange
+	err := row.Scan(
+		&i.ID,
+		&i.Hunt,
+		&i.Puzzle,
+		&i.Name,
+		&i.RoundName,
+		&i.Removed,
+		&i.MessageID,
+		&i.NotifiedAt,
 	)
 	return i, err
 }
 
 const getDiscoveredRound = `-- name: GetDiscoveredRound :one
-SELECT id, name, message_id, notified_at, created_as FROM discovered_rounds
-WHERE name = ? COLLATE nocase
+SELECT id, name, message_id, notified_at, created_as, hunt, emoji, edited_name, ignored FROM discovered_rounds
+WHERE hunt = ? AND name = ? COLLATE nocase
+`
+
+type GetDiscoveredRoundParams struct {
+	Hunt int64  `json:"hunt"`
+	Name string `json:"name"`
+}
+
+func (q *Queries) GetDiscoveredRound(ctx context.Context, arg GetDiscoveredRoundParams) (DiscoveredRound, error) {
+	row := q.db.QueryRowContext(ctx, getDiscoveredRound, arg.Hunt, arg.Name)
+	var i DiscoveredRound
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.MessageID,
+		&i.NotifiedAt,
+		&i.CreatedAs,
+		&i.Hunt,
+		&i.Emoji,
+		&i.EditedName,
+		&i.Ignored,
+	)
+	return i, err
+}
+
+const getDiscoveredRoundByID = `-- name: GetDiscoveredRoundByID :one
+SELECT id, name, message_id, notified_at, created_as, hunt, emoji, edited_name, ignored FROM discovered_rounds WHERE id = ?
 `
 
-func (q *Queries) GetDiscoveredRound(ctx context.Context, name string) (DiscoveredRound, error) {
-	row := q.db.QueryRowContext(ctx, getDiscoveredRound, name)
+func (q *Queries) GetDiscoveredRoundByID(ctx context.Context, id int64) (DiscoveredRound, error) {
+	row := q.db.QueryRowContext(ctx, getDiscoveredRoundByID, id)
 	var i DiscoveredRound
 	err := row.Scan(
 		&i.ID,
@@ -309,6 +776,28 @@ func (q *Queries) GetDiscoveredRound(ctx context.Context, name string) (Discover
 		&i.MessageID,
 		&i.NotifiedAt,
 		&i.CreatedAs,
+		&i.Hunt,
+		&i.Emoji,
+		&i.EditedName,
+		&i.Ignored,
+	)
+	return i, err
+}
+
+const getHunt = `-- name: GetHunt :one
+SELECT id, name, active, archived, created_at FROM hunts
+WHERE id = ?
+`
+
+func (q *Queries) GetHunt(ctx context.Context, id int64) (Hunt, error) {
+	row := q.db.QueryRowContext(ctx, getHunt, id)
+	var i Hunt
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Active,
+		&i.Archived,
+		&i.CreatedAt,
 	)
 	return i, err
 }
@@ -326,28 +815,44 @@ func (q *Queries) GetLastChangeID(ctx context.Context) (interface{}, error) {
 
 const getPuzzle = `-- name: GetPuzzle :one
 SELECT
-    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, p.status, p.note,
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.id = ?
+WHERE p.id = ? AND p.deleted_at IS NULL
 `
 
 type GetPuzzleRow struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          Round         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
 }
 
 func (q *Queries) GetPuzzle(ctx context.Context, id int64) (GetPuzzleRow, error) {
@@ -365,6 +870,9 @@ func (q *Queries) GetPuzzle(ctx context.Context, id int64) (GetPuzzleRow, error)
 		&i.Round.Special,
 		&i.Round.DriveFolder,
 		&i.Round.DiscordCategory,
+		&i.Round.Version,
+		&i.Round.DeletedAt,
+		&i.Round.Hunt,
 		&i.Status,
 		&i.Note,
 		&i.Location,
@@ -374,34 +882,55 @@ func (q *Queries) GetPuzzle(ctx context.Context, id int64) (GetPuzzleRow, error)
 		&i.Meta,
 		&i.VoiceRoom,
 		&i.Reminder,
+		&i.Priority,
+		&i.NeedsHelp,
+		&i.Version,
+		&i.Tags,
+		&i.Solvers,
 	)
 	return i, err
 }
 
 const getPuzzleByChannel = `-- name: GetPuzzleByChannel :one
 SELECT
-    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, p.status, p.note,
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.discord_channel = ?
+WHERE p.discord_channel = ? AND p.deleted_at IS NULL
 `
 
 type GetPuzzleByChannelRow struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          Round         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
 }
 
 func (q *Queries) GetPuzzleByChannel(ctx context.Context, discordChannel string) (GetPuzzleByChannelRow, error) {
@@ -419,6 +948,9 @@ func (q *Queries) GetPuzzleByChannel(ctx context.Context, discordChannel string)
 		&i.Round.Special,
 		&i.Round.DriveFolder,
 		&i.Round.DiscordCategory,
+		&i.Round.Version,
+		&i.Round.DeletedAt,
+		&i.Round.Hunt,
 		&i.Status,
 		&i.Note,
 		&i.Location,
@@ -428,34 +960,55 @@ func (q *Queries) GetPuzzleByChannel(ctx context.Context, discordChannel string)
 		&i.Meta,
 		&i.VoiceRoom,
 		&i.Reminder,
+		&i.Priority,
+		&i.NeedsHelp,
+		&i.Version,
+		&i.Tags,
+		&i.Solvers,
 	)
 	return i, err
 }
 
 const getPuzzlesByVoiceRoom = `-- name: GetPuzzlesByVoiceRoom :many
 SELECT
-    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, p.status, p.note,
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.voice_room = ?
+WHERE p.voice_room = ? AND p.deleted_at IS NULL
 `
 
 type GetPuzzlesByVoiceRoomRow struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          Round         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
 }
 
 func (q *Queries) GetPuzzlesByVoiceRoom(ctx context.Context, voiceRoom string) ([]GetPuzzlesByVoiceRoomRow, error) {
@@ -479,6 +1032,9 @@ func (q *Queries) GetPuzzlesByVoiceRoom(ctx context.Context, voiceRoom string) (
 			&i.Round.Special,
 			&i.Round.DriveFolder,
 			&i.Round.DiscordCategory,
+			&i.Round.Version,
+			&i.Round.DeletedAt,
+			&i.Round.Hunt,
 			&i.Status,
 			&i.Note,
 			&i.Location,
@@ -488,6 +1044,11 @@ func (q *Queries) GetPuzzlesByVoiceRoom(ctx context.Context, voiceRoom string) (
 			&i.Meta,
 			&i.VoiceRoom,
 			&i.Reminder,
+			&i.Priority,
+			&i.NeedsHelp,
+			&i.Version,
+			&i.Tags,
+			&i.Solvers,
 		); err != nil {
 			return nil, err
 		}
@@ -503,8 +1064,8 @@ func (q *Queries) GetPuzzlesByVoiceRoom(ctx context.Context, voiceRoom string) (
 }
 
 const getRound = `-- name: GetRound :one
-SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category FROM rounds
-WHERE id = ? LIMIT 1
+SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category, version, deleted_at, hunt FROM rounds
+WHERE id = ? AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetRound(ctx context.Context, id int64) (Round, error) {
@@ -519,41 +1080,57 @@ func (q *Queries) GetRound(ctx context.Context, id int64) (Round, error) {
 		&i.Special,
 		&i.DriveFolder,
 		&i.DiscordCategory,
+		&i.Version,
+		&i.DeletedAt,
+		&i.Hunt,
 	)
 	return i, err
 }
 
 const getSetting = `-- name: GetSetting :one
 SELECT value from settings
-WHERE key = ?
+WHERE hunt = ? AND key = ?
 `
 
-func (q *Queries) GetSetting(ctx context.Context, key string) ([]byte, error) {
-	row := q.db.QueryRowContext(ctx, getSetting, key)
+type GetSettingParams struct {
+	Hunt int64  `json:"hunt"`
+	Key  string `json:"key"`
+}
+
+func (q *Queries) GetSetting(ctx context.Context, arg GetSettingParams) ([]byte, error) {
+	row := q.db.QueryRowContext(ctx, getSetting, arg.Hunt, arg.Key)
 	var value []byte
 	err := row.Scan(&value)
 	return value, err
 }
 
-const listChangelog = `-- name: ListChangelog :many
-SELECT id, kind, puzzle, round FROM changelog
+const listAllGuesses = `-- name: ListAllGuesses :many
+SELECT id, puzzle, guess, result, note, actor, submitted_at FROM guesses
+WHERE puzzle IN (
+    SELECT puzzles.id FROM puzzles
+    INNER JOIN rounds ON puzzles.round = rounds.id
+    WHERE rounds.hunt = ?
+)
 ORDER BY id
 `
 
-func (q *Queries) ListChangelog(ctx context.Context) ([]Changelog, error) {
-	rows, err := q.db.QueryContext(ctx, listChangelog)
+func (q *Queries) ListAllGuesses(ctx context.Context, hunt int64) ([]Guess, error) {
+	rows, err := q.db.QueryContext(ctx, listAllGuesses, hunt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Changelog
+	var items []Guess
 	for rows.Next() {
-		var i Changelog
+		var i Guess
 		if err := rows.Scan(
 			&i.ID,
-			&i.Kind,
 			&i.Puzzle,
-			&i.Round,
+			&i.Guess,
+			&i.Result,
+			&i.Note,
+			&i.Actor,
+			&i.SubmittedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -568,45 +1145,165 @@ func (q *Queries) ListChangelog(ctx context.Context) ([]Changelog, error) {
 	return items, nil
 }
 
-const listCreatablePuzzles = `-- name: ListCreatablePuzzles :many
-SELECT discovered_puzzles.id, puzzle_url, discovered_puzzles.name, discovered_round, discovered_rounds.id, discovered_rounds.name, message_id, notified_at, created_as
-FROM discovered_puzzles
-INNER JOIN discovered_rounds
-ON discovered_puzzles.discovered_round = discovered_rounds.id
-WHERE discovered_rounds.created_as != 0
+const listAllPuzzleHistory = `-- name: ListAllPuzzleHistory :many
+SELECT id, puzzle, field, old_value, new_value, changed_at, actor FROM puzzle_history
+WHERE puzzle IN (
+    SELECT puzzles.id FROM puzzles
+    INNER JOIN rounds ON puzzles.round = rounds.id
+    WHERE rounds.hunt = ?
+)
+ORDER BY id
 `
 
-type ListCreatablePuzzlesRow struct {
-	ID              int64         `json:"id"`
-	PuzzleURL       string        `json:"puzzle_url"`
-	Name            string        `json:"name"`
-	DiscoveredRound sql.NullInt64 `json:"discovered_round"`
-	ID_2            int64         `json:"id_2"`
-	Name_2          string        `json:"name_2"`
-	MessageID       string        `json:"message_id"`
-	NotifiedAt      time.Time     `json:"notified_at"`
-	CreatedAs       int64         `json:"created_as"`
-}
-
-func (q *Queries) ListCreatablePuzzles(ctx context.Context) ([]ListCreatablePuzzlesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCreatablePuzzles)
+func (q *Queries) ListAllPuzzleHistory(ctx context.Context, hunt int64) ([]PuzzleHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listAllPuzzleHistory, hunt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListCreatablePuzzlesRow
+	var items []PuzzleHistory
 	for rows.Next() {
-		var i ListCreatablePuzzlesRow
+		var i PuzzleHistory
 		if err := rows.Scan(
 			&i.ID,
-			&i.PuzzleURL,
-			&i.Name,
+			&i.Puzzle,
+			&i.Field,
+			&i.OldValue,
+			&i.NewValue,
+			&i.ChangedAt,
+			&i.Actor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChangelog = `-- name: ListChangelog :many
+SELECT id, kind, puzzle, round, actor, feed, guess FROM changelog
+ORDER BY id
+`
+
+func (q *Queries) ListChangelog(ctx context.Context) ([]Changelog, error) {
+	rows, err := q.db.QueryContext(ctx, listChangelog)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Changelog
+	for rows.Next() {
+		var i Changelog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.Puzzle,
+			&i.Round,
+			&i.Actor,
+			&i.Feed,
+			&i.Guess,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCreatablePuzzles = `-- name: ListCreatablePuzzles :many
+SELECT discovered_puzzles.id, puzzle_url, discovered_puzzles.name, discovered_round, discovered_puzzles.hunt, discovered_rounds.id, discovered_rounds.name, message_id, notified_at, created_as, discovered_rounds.hunt, emoji, edited_name, ignored
+FROM discovered_puzzles
+INNER JOIN discovered_rounds
+ON discovered_puzzles.discovered_round = discovered_rounds.id
+WHERE discovered_rounds.hunt = ? AND discovered_rounds.created_as != 0
+`
+
+type ListCreatablePuzzlesRow struct {
+	ID              int64         `json:"id"`
+	PuzzleURL       string        `json:"puzzle_url"`
+	Name            string        `json:"name"`
+	DiscoveredRound sql.NullInt64 `json:"discovered_round"`
+	Hunt            int64         `json:"hunt"`
+	ID_2            int64         `json:"id_2"`
+	Name_2          string        `json:"name_2"`
+	MessageID       string        `json:"message_id"`
+	NotifiedAt      time.Time     `json:"notified_at"`
+	CreatedAs       int64         `json:"created_as"`
+	Hunt_2          int64         `json:"hunt_2"`
+	Emoji           string        `json:"emoji"`
+	EditedName      string        `json:"edited_name"`
+	Ignored         bool          `json:"ignored"`
+}
+
+func (q *Queries) ListCreatablePuzzles(ctx context.Context, hunt int64) ([]ListCreatablePuzzlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCreatablePuzzles, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCreatablePuzzlesRow
+	for rows.Next() {
+		var i ListCreatablePuzzlesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PuzzleURL,
+			&i.Name,
 			&i.DiscoveredRound,
+			&i.Hunt,
 			&i.ID_2,
 			&i.Name_2,
 			&i.MessageID,
 			&i.NotifiedAt,
 			&i.CreatedAs,
+			&i.Hunt_2,
+			&i.Emoji,
+			&i.EditedName,
+			&i.Ignored,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDiscoveredPuzzles = `-- name: ListDiscoveredPuzzles :many
+SELECT id, puzzle_url, name, discovered_round, hunt FROM discovered_puzzles WHERE hunt = ? ORDER BY id
+`
+
+func (q *Queries) ListDiscoveredPuzzles(ctx context.Context, hunt int64) ([]DiscoveredPuzzle, error) {
+	rows, err := q.db.QueryContext(ctx, listDiscoveredPuzzles, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DiscoveredPuzzle
+	for rows.Next() {
+		var i DiscoveredPuzzle
+		if err := rows.Scan(
+			&i.ID,
+			&i.PuzzleURL,
+			&i.Name,
+			&i.DiscoveredRound,
+			&i.Hunt,
 		); err != nil {
 			return nil, err
 		}
@@ -622,7 +1319,7 @@ func (q *Queries) ListCreatablePuzzles(ctx context.Context) ([]ListCreatablePuzz
 }
 
 const listDiscoveredPuzzlesForRound = `-- name: ListDiscoveredPuzzlesForRound :many
-SELECT id, puzzle_url, name, discovered_round FROM discovered_puzzles WHERE discovered_round = ?
+SELECT id, puzzle_url, name, discovered_round, hunt FROM discovered_puzzles WHERE discovered_round = ?
 `
 
 func (q *Queries) ListDiscoveredPuzzlesForRound(ctx context.Context, discoveredRound sql.NullInt64) ([]DiscoveredPuzzle, error) {
@@ -639,6 +1336,279 @@ func (q *Queries) ListDiscoveredPuzzlesForRound(ctx context.Context, discoveredR
 			&i.PuzzleURL,
 			&i.Name,
 			&i.DiscoveredRound,
+			&i.Hunt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDiscoveredRounds = `-- name: ListDiscoveredRounds :many
+SELECT id, name, message_id, notified_at, created_as, hunt, emoji, edited_name, ignored FROM discovered_rounds WHERE hunt = ? ORDER BY id
+`
+
+func (q *Queries) ListDiscoveredRounds(ctx context.Context, hunt int64) ([]DiscoveredRound, error) {
+	rows, err := q.db.QueryContext(ctx, listDiscoveredRounds, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DiscoveredRound
+	for rows.Next() {
+		var i DiscoveredRound
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.MessageID,
+			&i.NotifiedAt,
+			&i.CreatedAs,
+			&i.Hunt,
+			&i.Emoji,
+			&i.EditedName,
+			&i.Ignored,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFedMetas = `-- name: ListFedMetas :many
+SELECT p.id, p.name, p.answer, p.discord_channel
+FROM puzzle_feeds AS f
+INNER JOIN puzzles AS p ON f.meta = p.id
+WHERE f.puzzle = ? AND p.deleted_at IS NULL
+ORDER BY p.name COLLATE nocase
+`
+
+type ListFedMetasRow struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	Answer         string `json:"answer"`
+	DiscordChannel string `json:"discord_channel"`
+}
+
+func (q *Queries) ListFedMetas(ctx context.Context, puzzle int64) ([]ListFedMetasRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFedMetas, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFedMetasRow
+	for rows.Next() {
+		var i ListFedMetasRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Answer,
+			&i.DiscordChannel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFeeders = `-- name: ListFeeders :many
+SELECT p.id, p.name, p.answer, p.discord_channel
+FROM puzzle_feeds AS f
+INNER JOIN puzzles AS p ON f.puzzle = p.id
+WHERE f.meta = ? AND p.deleted_at IS NULL
+ORDER BY p.name COLLATE nocase
+`
+
+type ListFeedersRow struct {
+	ID             int64  `json:"id"`
+	Name           string `json:"name"`
+	Answer         string `json:"answer"`
+	DiscordChannel string `json:"discord_channel"`
+}
+
+func (q *Queries) ListFeeders(ctx context.Context, meta int64) ([]ListFeedersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFeeders, meta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFeedersRow
+	for rows.Next() {
+		var i ListFeedersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Answer,
+			&i.DiscordChannel,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGuesses = `-- name: ListGuesses :many
+SELECT id, puzzle, guess, result, note, actor, submitted_at FROM guesses
+WHERE puzzle = ?
+ORDER BY id
+`
+
+func (q *Queries) ListGuesses(ctx context.Context, puzzle int64) ([]Guess, error) {
+	rows, err := q.db.QueryContext(ctx, listGuesses, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Guess
+	for rows.Next() {
+		var i Guess
+		if err := rows.Scan(
+			&i.ID,
+			&i.Puzzle,
+			&i.Guess,
+			&i.Result,
+			&i.Note,
+			&i.Actor,
+			&i.SubmittedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHunts = `-- name: ListHunts :many
+SELECT id, name, active, archived, created_at FROM hunts
+ORDER BY id
+`
+
+func (q *Queries) ListHunts(ctx context.Context) ([]Hunt, error) {
+	rows, err := q.db.QueryContext(ctx, listHunts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Hunt
+	for rows.Next() {
+		var i Hunt
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Active,
+			&i.Archived,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listIdlePuzzleSolvers = `-- name: ListIdlePuzzleSolvers :many
+SELECT puzzle_solvers.puzzle, puzzle_solvers.solver, puzzle_solvers.joined_at, puzzle_solvers.active_at FROM puzzle_solvers
+INNER JOIN puzzles AS p ON puzzle_solvers.puzzle = p.id
+INNER JOIN rounds ON p.round = rounds.id
+WHERE puzzle_solvers.active_at < ?1
+    AND p.deleted_at IS NULL AND rounds.hunt = ?2
+ORDER BY puzzle_solvers.active_at
+`
+
+type ListIdlePuzzleSolversParams struct {
+	ActiveBefore time.Time `json:"active_before"`
+	Hunt         int64     `json:"hunt"`
+}
+
+func (q *Queries) ListIdlePuzzleSolvers(ctx context.Context, arg ListIdlePuzzleSolversParams) ([]PuzzleSolver, error) {
+	rows, err := q.db.QueryContext(ctx, listIdlePuzzleSolvers, arg.ActiveBefore, arg.Hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PuzzleSolver
+	for rows.Next() {
+		var i PuzzleSolver
+		if err := rows.Scan(
+			&i.Puzzle,
+			&i.Solver,
+			&i.JoinedAt,
+			&i.ActiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingDiscoveredChanges = `-- name: ListPendingDiscoveredChanges :many
+SELECT id, hunt, puzzle, name, round_name, removed, message_id, notified_at FROM discovered_changes WHERE hunt = ? AND message_id = '' ORDER BY id
+`
+
+func (q *Queries) ListPendingDiscoveredChanges(ctx context.Context, hunt int64) ([]DiscoveredChange, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingDiscoveredChanges, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DiscoveredChange
+	for rows.Next() {
+		var i DiscoveredChange
+		if err := rows.Scan(
+			&i.ID,
+			&i.Hunt,
+			&i.Puzzle,
+			&i.Name,
+			&i.RoundName,
+			&i.Removed,
+			&i.MessageID,
+			&i.NotifiedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -654,11 +1624,11 @@ func (q *Queries) ListDiscoveredPuzzlesForRound(ctx context.Context, discoveredR
 }
 
 const listPendingDiscoveredRounds = `-- name: ListPendingDiscoveredRounds :many
-SELECT id, name, message_id, notified_at, created_as FROM discovered_rounds WHERE created_as = 0
+SELECT id, name, message_id, notified_at, created_as, hunt, emoji, edited_name, ignored FROM discovered_rounds WHERE hunt = ? AND created_as = 0 AND NOT ignored
 `
 
-func (q *Queries) ListPendingDiscoveredRounds(ctx context.Context) ([]DiscoveredRound, error) {
-	rows, err := q.db.QueryContext(ctx, listPendingDiscoveredRounds)
+func (q *Queries) ListPendingDiscoveredRounds(ctx context.Context, hunt int64) ([]DiscoveredRound, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingDiscoveredRounds, hunt)
 	if err != nil {
 		return nil, err
 	}
@@ -672,6 +1642,212 @@ func (q *Queries) ListPendingDiscoveredRounds(ctx context.Context) ([]Discovered
 			&i.MessageID,
 			&i.NotifiedAt,
 			&i.CreatedAs,
+			&i.Hunt,
+			&i.Emoji,
+			&i.EditedName,
+			&i.Ignored,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPuzzleFeeds = `-- name: ListPuzzleFeeds :many
+SELECT puzzle, meta FROM puzzle_feeds
+WHERE puzzle NOT IN (SELECT id FROM puzzles WHERE deleted_at IS NOT NULL)
+    AND meta NOT IN (SELECT id FROM puzzles WHERE deleted_at IS NOT NULL)
+    AND meta IN (
+        SELECT puzzles.id FROM puzzles
+        INNER JOIN rounds ON puzzles.round = rounds.id
+        WHERE rounds.hunt = ?
+    )
+ORDER BY meta, puzzle
+`
+
+func (q *Queries) ListPuzzleFeeds(ctx context.Context, hunt int64) ([]PuzzleFeed, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzleFeeds, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PuzzleFeed
+	for rows.Next() {
+		var i PuzzleFeed
+		if err := rows.Scan(&i.Puzzle, &i.Meta); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPuzzleHistory = `-- name: ListPuzzleHistory :many
+SELECT id, puzzle, field, old_value, new_value, changed_at, actor FROM puzzle_history
+WHERE puzzle = ?
+ORDER BY id
+`
+
+func (q *Queries) ListPuzzleHistory(ctx context.Context, puzzle int64) ([]PuzzleHistory, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzleHistory, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PuzzleHistory
+	for rows.Next() {
+		var i PuzzleHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.Puzzle,
+			&i.Field,
+			&i.OldValue,
+			&i.NewValue,
+			&i.ChangedAt,
+			&i.Actor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPuzzleSolvers = `-- name: ListPuzzleSolvers :many
+SELECT puzzle, solver, joined_at, active_at FROM puzzle_solvers
+WHERE puzzle = ?
+ORDER BY joined_at, solver
+`
+
+func (q *Queries) ListPuzzleSolvers(ctx context.Context, puzzle int64) ([]PuzzleSolver, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzleSolvers, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PuzzleSolver
+	for rows.Next() {
+		var i PuzzleSolver
+		if err := rows.Scan(
+			&i.Puzzle,
+			&i.Solver,
+			&i.JoinedAt,
+			&i.ActiveAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPuzzles = `-- name: ListPuzzles :many
+SELECT
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
+    p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
+FROM puzzles AS p
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NULL AND rounds.hunt = ?
+ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
+COLLATE nocase
+`
+
+type ListPuzzlesRow struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
+}
+
+func (q *Queries) ListPuzzles(ctx context.Context, hunt int64) ([]ListPuzzlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzles, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPuzzlesRow
+	for rows.Next() {
+		var i ListPuzzlesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Answer,
+			&i.Round.ID,
+			&i.Round.Name,
+			&i.Round.Emoji,
+			&i.Round.Hue,
+			&i.Round.Sort,
+			&i.Round.Special,
+			&i.Round.DriveFolder,
+			&i.Round.DiscordCategory,
+			&i.Round.Version,
+			&i.Round.DeletedAt,
+			&i.Round.Hunt,
+			&i.Status,
+			&i.Note,
+			&i.Location,
+			&i.PuzzleURL,
+			&i.SpreadsheetID,
+			&i.DiscordChannel,
+			&i.Meta,
+			&i.VoiceRoom,
+			&i.Reminder,
+			&i.Priority,
+			&i.NeedsHelp,
+			&i.Version,
+			&i.Tags,
+			&i.Solvers,
 		); err != nil {
 			return nil, err
 		}
@@ -686,42 +1862,59 @@ func (q *Queries) ListPendingDiscoveredRounds(ctx context.Context) ([]Discovered
 	return items, nil
 }
 
-const listPuzzles = `-- name: ListPuzzles :many
+const listPuzzlesByRound = `-- name: ListPuzzlesByRound :many
 SELECT
-    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, p.status, p.note,
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
+WHERE p.round = ? AND p.deleted_at IS NULL
 ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
 COLLATE nocase
 `
 
-type ListPuzzlesRow struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          Round         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+type ListPuzzlesByRoundRow struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
 }
 
-func (q *Queries) ListPuzzles(ctx context.Context) ([]ListPuzzlesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listPuzzles)
+func (q *Queries) ListPuzzlesByRound(ctx context.Context, round int64) ([]ListPuzzlesByRoundRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzlesByRound, round)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPuzzlesRow
+	var items []ListPuzzlesByRoundRow
 	for rows.Next() {
-		var i ListPuzzlesRow
+		var i ListPuzzlesByRoundRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -734,6 +1927,9 @@ func (q *Queries) ListPuzzles(ctx context.Context) ([]ListPuzzlesRow, error) {
 			&i.Round.Special,
 			&i.Round.DriveFolder,
 			&i.Round.DiscordCategory,
+			&i.Round.Version,
+			&i.Round.DeletedAt,
+			&i.Round.Hunt,
 			&i.Status,
 			&i.Note,
 			&i.Location,
@@ -743,6 +1939,11 @@ func (q *Queries) ListPuzzles(ctx context.Context) ([]ListPuzzlesRow, error) {
 			&i.Meta,
 			&i.VoiceRoom,
 			&i.Reminder,
+			&i.Priority,
+			&i.NeedsHelp,
+			&i.Version,
+			&i.Tags,
+			&i.Solvers,
 		); err != nil {
 			return nil, err
 		}
@@ -757,43 +1958,65 @@ func (q *Queries) ListPuzzles(ctx context.Context) ([]ListPuzzlesRow, error) {
 	return items, nil
 }
 
-const listPuzzlesByRound = `-- name: ListPuzzlesByRound :many
+const listPuzzlesByTag = `-- name: ListPuzzlesByTag :many
 SELECT
-    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, p.status, p.note,
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
     p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
-    p.meta, p.voice_room, p.reminder
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers
 FROM puzzles AS p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.round = ?
+WHERE p.id IN (SELECT puzzle FROM puzzle_tags WHERE tag = ?)
+    AND p.deleted_at IS NULL AND rounds.hunt = ?
 ORDER BY rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
 COLLATE nocase
 `
 
-type ListPuzzlesByRoundRow struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          Round         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+type ListPuzzlesByTagParams struct {
+	Tag  string `json:"tag"`
+	Hunt int64  `json:"hunt"`
 }
 
-func (q *Queries) ListPuzzlesByRound(ctx context.Context, round int64) ([]ListPuzzlesByRoundRow, error) {
-	rows, err := q.db.QueryContext(ctx, listPuzzlesByRound, round)
+type ListPuzzlesByTagRow struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
+}
+
+func (q *Queries) ListPuzzlesByTag(ctx context.Context, arg ListPuzzlesByTagParams) ([]ListPuzzlesByTagRow, error) {
+	rows, err := q.db.QueryContext(ctx, listPuzzlesByTag, arg.Tag, arg.Hunt)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPuzzlesByRoundRow
+	var items []ListPuzzlesByTagRow
 	for rows.Next() {
-		var i ListPuzzlesByRoundRow
+		var i ListPuzzlesByTagRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
@@ -806,6 +2029,9 @@ func (q *Queries) ListPuzzlesByRound(ctx context.Context, round int64) ([]ListPu
 			&i.Round.Special,
 			&i.Round.DriveFolder,
 			&i.Round.DiscordCategory,
+			&i.Round.Version,
+			&i.Round.DeletedAt,
+			&i.Round.Hunt,
 			&i.Status,
 			&i.Note,
 			&i.Location,
@@ -815,6 +2041,11 @@ func (q *Queries) ListPuzzlesByRound(ctx context.Context, round int64) ([]ListPu
 			&i.Meta,
 			&i.VoiceRoom,
 			&i.Reminder,
+			&i.Priority,
+			&i.NeedsHelp,
+			&i.Version,
+			&i.Tags,
+			&i.Solvers,
 		); err != nil {
 			return nil, err
 		}
@@ -833,7 +2064,7 @@ const listPuzzlesByVoiceRoom = `-- name: ListPuzzlesByVoiceRoom :many
 SELECT p.id, p.name, p.voice_room
 FROM puzzles as p
 INNER JOIN rounds ON p.round = rounds.id
-WHERE p.voice_room != ""
+WHERE p.voice_room != "" AND p.deleted_at IS NULL
 ORDER BY p.voice_room, rounds.special DESC, rounds.sort, rounds.id, p.meta, p.name
 COLLATE nocase
 `
@@ -867,14 +2098,195 @@ func (q *Queries) ListPuzzlesByVoiceRoom(ctx context.Context) ([]ListPuzzlesByVo
 	return items, nil
 }
 
+const listReminders = `-- name: ListReminders :many
+SELECT id, hunt, puzzle, discord_channel, message, remind_at, lead_minutes, every_minutes, created_by, created_at FROM reminders
+WHERE hunt = ?
+    AND (puzzle IS NULL OR puzzle NOT IN (SELECT id FROM puzzles WHERE deleted_at IS NOT NULL))
+ORDER BY remind_at, id
+`
+
+func (q *Queries) ListReminders(ctx context.Context, hunt int64) ([]Reminder, error) {
+	rows, err := q.db.QueryContext(ctx, listReminders, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Reminder
+	for rows.Next() {
+		var i Reminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.Hunt,
+			&i.Puzzle,
+			&i.DiscordChannel,
+			&i.Message,
+			&i.RemindAt,
+			&i.LeadMinutes,
+			&i.EveryMinutes,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listRounds = `-- name: ListRounds :many
-SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category FROM rounds
+SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category, version, deleted_at, hunt FROM rounds
+WHERE deleted_at IS NULL AND hunt = ?
 ORDER BY special DESC, sort, id
 COLLATE nocase
 `
 
-func (q *Queries) ListRounds(ctx context.Context) ([]Round, error) {
-	rows, err := q.db.QueryContext(ctx, listRounds)
+func (q *Queries) ListRounds(ctx context.Context, hunt int64) ([]Round, error) {
+	rows, err := q.db.QueryContext(ctx, listRounds, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Round
+	for rows.Next() {
+		var i Round
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Emoji,
+			&i.Hue,
+			&i.Sort,
+			&i.Special,
+			&i.DriveFolder,
+			&i.DiscordCategory,
+			&i.Version,
+			&i.DeletedAt,
+			&i.Hunt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedPuzzles = `-- name: ListTrashedPuzzles :many
+SELECT
+    p.id, p.name, p.answer, rounds.id, rounds.name, rounds.emoji, rounds.hue, rounds.sort, rounds.special, rounds.drive_folder, rounds.discord_category, rounds.version, rounds.deleted_at, rounds.hunt, p.status, p.note,
+    p.location, p.puzzle_url, p.spreadsheet_id, p.discord_channel,
+    p.meta, p.voice_room, p.reminder, p.priority, p.needs_help, p.version,
+    CAST(COALESCE((
+        SELECT group_concat(tag, ',') FROM (
+            SELECT tag FROM puzzle_tags WHERE puzzle = p.id ORDER BY tag
+        )
+    ), '') AS TEXT) AS tags,
+    CAST(COALESCE((
+        SELECT group_concat(solver, ',') FROM (
+            SELECT solver FROM puzzle_solvers WHERE puzzle = p.id
+            ORDER BY joined_at, solver
+        )
+    ), '') AS TEXT) AS solvers,
+    p.deleted_at
+FROM puzzles AS p
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NOT NULL AND rounds.hunt = ?
+ORDER BY p.deleted_at DESC
+`
+
+type ListTrashedPuzzlesRow struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           string          `json:"tags"`
+	Solvers        string          `json:"solvers"`
+	DeletedAt      sql.NullTime    `json:"-"`
+}
+
+func (q *Queries) ListTrashedPuzzles(ctx context.Context, hunt int64) ([]ListTrashedPuzzlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedPuzzles, hunt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrashedPuzzlesRow
+	for rows.Next() {
+		var i ListTrashedPuzzlesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Answer,
+			&i.Round.ID,
+			&i.Round.Name,
+			&i.Round.Emoji,
+			&i.Round.Hue,
+			&i.Round.Sort,
+			&i.Round.Special,
+			&i.Round.DriveFolder,
+			&i.Round.DiscordCategory,
+			&i.Round.Version,
+			&i.Round.DeletedAt,
+			&i.Round.Hunt,
+			&i.Status,
+			&i.Note,
+			&i.Location,
+			&i.PuzzleURL,
+			&i.SpreadsheetID,
+			&i.DiscordChannel,
+			&i.Meta,
+			&i.VoiceRoom,
+			&i.Reminder,
+			&i.Priority,
+			&i.NeedsHelp,
+			&i.Version,
+			&i.Tags,
+			&i.Solvers,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedRounds = `-- name: ListTrashedRounds :many
+SELECT id, name, emoji, hue, sort, special, drive_folder, discord_category, version, deleted_at, hunt FROM rounds
+WHERE deleted_at IS NOT NULL AND hunt = ?
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListTrashedRounds(ctx context.Context, hunt int64) ([]Round, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedRounds, hunt)
 	if err != nil {
 		return nil, err
 	}
@@ -891,6 +2303,9 @@ func (q *Queries) ListRounds(ctx context.Context) ([]Round, error) {
 			&i.Special,
 			&i.DriveFolder,
 			&i.DiscordCategory,
+			&i.Version,
+			&i.DeletedAt,
+			&i.Hunt,
 		); err != nil {
 			return nil, err
 		}
@@ -919,9 +2334,229 @@ func (q *Queries) PruneChangelog(ctx context.Context) error {
 	return err
 }
 
+const purgeTrashedPuzzles = `-- name: PurgeTrashedPuzzles :execrows
+DELETE FROM puzzles
+WHERE deleted_at IS NOT NULL
+    AND round IN (SELECT id FROM rounds WHERE hunt = ?)
+`
+
+func (q *Queries) PurgeTrashedPuzzles(ctx context.Context, hunt int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeTrashedPuzzles, hunt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeTrashedRounds = `-- name: PurgeTrashedRounds :execrows
+DELETE FROM rounds
+WHERE deleted_at IS NOT NULL AND hunt = ?
+    AND id NOT IN (SELECT round FROM puzzles)
+`
+
+func (q *Queries) PurgeTrashedRounds(ctx context.Context, hunt int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeTrashedRounds, hunt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restorePuzzle = `-- name: RestorePuzzle :execrows
+UPDATE puzzles
+SET deleted_at = NULL, version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestorePuzzle(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restorePuzzle, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreRound = `-- name: RestoreRound :execrows
+UPDATE rounds
+SET deleted_at = NULL, version = version + 1
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreRound(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreRound, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchPuzzles = `-- name: SearchPuzzles :many
+SELECT
+    p.id, p.name, p.answer, p.discord_channel,
+    rounds.name AS round_name, rounds.emoji AS round_emoji,
+    CAST(min(hits.rank) AS REAL) AS rank
+FROM (
+    SELECT search_puzzles.rowid AS puzzle, search_puzzles.rank AS rank
+    FROM search_puzzles
+    WHERE search_puzzles MATCH ?1
+    UNION ALL
+    -- discussion is a weaker signal than the puzzle's own fields
+    SELECT puzzles.id AS puzzle, search_messages.rank * 0.5 AS rank
+    FROM search_messages
+    INNER JOIN puzzles ON search_messages.channel = puzzles.discord_channel
+    WHERE search_messages MATCH ?1
+) AS hits
+INNER JOIN puzzles AS p ON hits.puzzle = p.id
+INNER JOIN rounds ON p.round = rounds.id
+WHERE p.deleted_at IS NULL AND rounds.hunt = ?2
+GROUP BY p.id
+ORDER BY rank
+LIMIT ?3
+`
+
+type SearchPuzzlesParams struct {
+	Query      string `json:"query"`
+	Hunt       int64  `json:"hunt"`
+	MaxResults int64  `json:"max_results"`
+}
+
+type SearchPuzzlesRow struct {
+	ID             int64   `json:"id"`
+	Name           string  `json:"name"`
+	Answer         string  `json:"answer"`
+	DiscordChannel string  `json:"discord_channel"`
+	RoundName      string  `json:"round_name"`
+	RoundEmoji     string  `json:"round_emoji"`
+	Rank           float64 `json:"rank"`
+}
+
+func (q *Queries) SearchPuzzles(ctx context.Context, arg SearchPuzzlesParams) ([]SearchPuzzlesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPuzzles, arg.Query, arg.Hunt, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPuzzlesRow
+	for rows.Next() {
+		var i SearchPuzzlesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Answer,
+			&i.DiscordChannel,
+			&i.RoundName,
+			&i.RoundEmoji,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchPuzzleSolver = `-- name: TouchPuzzleSolver :exec
+UPDATE puzzle_solvers
+SET active_at = ?1
+WHERE solver = ?2 AND active_at < ?1
+    AND puzzle IN (
+        SELECT id FROM puzzles WHERE discord_channel = ?3
+    )
+`
+
+type TouchPuzzleSolverParams struct {
+	ActiveAt       time.Time `json:"active_at"`
+	Solver         string    `json:"solver"`
+	DiscordChannel string    `json:"discord_channel"`
+}
+
+func (q *Queries) TouchPuzzleSolver(ctx context.Context, arg TouchPuzzleSolverParams) error {
+	_, err := q.db.ExecContext(ctx, touchPuzzleSolver, arg.ActiveAt, arg.Solver, arg.DiscordChannel)
+	return err
+}
+
+const touchPuzzleSolversBySpreadsheet = `-- name: TouchPuzzleSolversBySpreadsheet :exec
+UPDATE puzzle_solvers
+SET active_at = ?1
+WHERE active_at < ?1
+    AND puzzle IN (
+        SELECT id FROM puzzles WHERE spreadsheet_id = ?2
+    )
+`
+
+type TouchPuzzleSolversBySpreadsheetParams struct {
+	ActiveAt      time.Time `json:"active_at"`
+	SpreadsheetID string    `json:"spreadsheet_id"`
+}
+
+func (q *Queries) TouchPuzzleSolversBySpreadsheet(ctx context.Context, arg TouchPuzzleSolversBySpreadsheetParams) error {
+	_, err := q.db.ExecContext(ctx, touchPuzzleSolversBySpreadsheet, arg.ActiveAt, arg.SpreadsheetID)
+	return err
+}
+
+const trashPuzzle = `-- name: TrashPuzzle :execrows
+UPDATE puzzles
+SET deleted_at = ?2, version = version + 1
+WHERE id = ?1 AND deleted_at IS NULL
+`
+
+type TrashPuzzleParams struct {
+	ID        int64        `json:"id"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+func (q *Queries) TrashPuzzle(ctx context.Context, arg TrashPuzzleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashPuzzle, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const trashRound = `-- name: TrashRound :execrows
+UPDATE rounds
+SET deleted_at = ?2, version = version + 1
+WHERE id = ?1 AND deleted_at IS NULL
+`
+
+type TrashRoundParams struct {
+	ID        int64        `json:"id"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+func (q *Queries) TrashRound(ctx context.Context, arg TrashRoundParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trashRound, arg.ID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateDiscoveredChange = `-- name: UpdateDiscoveredChange :exec
+UPDATE discovered_changes SET message_id = ?2, notified_at = ?3 WHERE id = ?1
+`
+
+type UpdateDiscoveredChangeParams struct {
+	ID         int64     `json:"id"`
+	MessageID  string    `json:"message_id"`
+	NotifiedAt time.Time `json:"notified_at"`
+}
+
+func (q *Queries) UpdateDiscoveredChange(ctx context.Context, arg UpdateDiscoveredChangeParams) error {
+	_, err := q.db.ExecContext(ctx, updateDiscoveredChange, arg.ID, arg.MessageID, arg.NotifiedAt)
+	return err
+}
+
 const updateDiscoveredRound = `-- name: UpdateDiscoveredRound :exec
 UPDATE discovered_rounds
-SET name = ?2, message_id = ?3, notified_at = ?4, created_as = ?5
+SET name = ?2, message_id = ?3, notified_at = ?4, created_as = ?5, hunt = ?6,
+    emoji = ?7, edited_name = ?8, ignored = ?9
 WHERE id = ?1
 `
 
@@ -931,6 +2566,10 @@ type UpdateDiscoveredRoundParams struct {
 	MessageID  string    `json:"message_id"`
 	NotifiedAt time.Time `json:"notified_at"`
 	CreatedAs  int64     `json:"created_as"`
+	Hunt       int64     `json:"hunt"`
+	Emoji      string    `json:"emoji"`
+	EditedName string    `json:"edited_name"`
+	Ignored    bool      `json:"ignored"`
 }
 
 func (q *Queries) UpdateDiscoveredRound(ctx context.Context, arg UpdateDiscoveredRoundParams) error {
@@ -940,32 +2579,58 @@ func (q *Queries) UpdateDiscoveredRound(ctx context.Context, arg UpdateDiscovere
 		arg.MessageID,
 		arg.NotifiedAt,
 		arg.CreatedAs,
+		arg.Hunt,
+		arg.Emoji,
+		arg.EditedName,
+		arg.Ignored,
 	)
 	return err
 }
 
+const updateHunt = `-- name: UpdateHunt :exec
+UPDATE hunts
+SET name = ?2, archived = ?3
+WHERE id = ?1
+`
+
+type UpdateHuntParams struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
+func (q *Queries) UpdateHunt(ctx context.Context, arg UpdateHuntParams) error {
+	_, err := q.db.ExecContext(ctx, updateHunt, arg.ID, arg.Name, arg.Archived)
+	return err
+}
+
 const updatePuzzle = `-- name: UpdatePuzzle :exec
 UPDATE puzzles
 SET name = ?2, answer = ?3, round = ?4, status = ?5, note = ?6,
 location = ?7, puzzle_url = ?8, spreadsheet_id = ?9, discord_channel = ?10,
-meta = ?11, voice_room = ?12, reminder = ?13
+meta = ?11, voice_room = ?12, reminder = ?13, version = ?14,
+deleted_at = ?15, priority = ?16, needs_help = ?17
 WHERE id = ?1
 `
 
 type UpdatePuzzleParams struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          int64         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          int64           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Version        int64           `json:"version"`
+	DeletedAt      sql.NullTime    `json:"-"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
 }
 
 func (q *Queries) UpdatePuzzle(ctx context.Context, arg UpdatePuzzleParams) error {
@@ -983,6 +2648,10 @@ func (q *Queries) UpdatePuzzle(ctx context.Context, arg UpdatePuzzleParams) erro
 		arg.Meta,
 		arg.VoiceRoom,
 		arg.Reminder,
+		arg.Version,
+		arg.DeletedAt,
+		arg.Priority,
+		arg.NeedsHelp,
 	)
 	return err
 }
@@ -990,19 +2659,23 @@ func (q *Queries) UpdatePuzzle(ctx context.Context, arg UpdatePuzzleParams) erro
 const updateRound = `-- name: UpdateRound :exec
 UPDATE rounds
 SET name = ?2, emoji = ?3, hue = ?4, sort = ?5, special = ?6,
-    drive_folder = ?7, discord_category = ?8
+    drive_folder = ?7, discord_category = ?8, version = ?9, deleted_at = ?10,
+    hunt = ?11
 WHERE id = ?1
 `
 
 type UpdateRoundParams struct {
-	ID              int64  `json:"id"`
-	Name            string `json:"name"`
-	Emoji           string `json:"emoji"`
-	Hue             int64  `json:"hue"`
-	Sort            int64  `json:"sort"`
-	Special         bool   `json:"special"`
-	DriveFolder     string `json:"drive_folder"`
-	DiscordCategory string `json:"discord_category"`
+	ID              int64        `json:"id"`
+	Name            string       `json:"name"`
+	Emoji           string       `json:"emoji"`
+	Hue             int64        `json:"hue"`
+	Sort            int64        `json:"sort"`
+	Special         bool         `json:"special"`
+	DriveFolder     string       `json:"drive_folder"`
+	DiscordCategory string       `json:"discord_category"`
+	Version         int64        `json:"version"`
+	DeletedAt       sql.NullTime `json:"-"`
+	Hunt            int64        `json:"hunt"`
 }
 
 func (q *Queries) UpdateRound(ctx context.Context, arg UpdateRoundParams) error {
@@ -1015,21 +2688,25 @@ func (q *Queries) UpdateRound(ctx context.Context, arg UpdateRoundParams) error
 		arg.Special,
 		arg.DriveFolder,
 		arg.DiscordCategory,
+		arg.Version,
+		arg.DeletedAt,
+		arg.Hunt,
 	)
 	return err
 }
 
 const updateSetting = `-- name: UpdateSetting :exec
-INSERT OR REPLACE INTO settings (key, value)
-VALUES (?, ?)
+INSERT OR REPLACE INTO settings (hunt, key, value)
+VALUES (?, ?, ?)
 `
 
 type UpdateSettingParams struct {
+	Hunt  int64  `json:"hunt"`
 	Key   string `json:"key"`
 	Value []byte `json:"value"`
 }
 
 func (q *Queries) UpdateSetting(ctx context.Context, arg UpdateSettingParams) error {
-	_, err := q.db.ExecContext(ctx, updateSetting, arg.Key, arg.Value)
+	_, err := q.db.ExecContext(ctx, updateSetting, arg.Hunt, arg.Key, arg.Value)
 	return err
 }
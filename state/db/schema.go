@@ -1,6 +1,162 @@
 package db
 
-import _ "embed"
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-//go:embed schema.sql
-var DDL string
+	"golang.org/x/xerrors"
+)
+
+// Migrations live in the migrations/ directory and are named with a numeric
+// prefix, e.g. "0002_add_foo.sql". They are applied in order and must never be
+// edited once they've been deployed; to change the schema, add a new file.
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+func Migrations() ([]Migration, error) {
+	entries, err := fs.Glob(migrationFS, "migrations/*.sql")
+	if err != nil {
+		return nil, xerrors.Errorf("Glob: %w", err)
+	}
+	var migrations []Migration
+	for _, entry := range entries {
+		var name = strings.TrimSuffix(path.Base(entry), ".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version < 1 {
+			return nil, xerrors.Errorf("invalid migration name %q", entry)
+		}
+		data, err := migrationFS.ReadFile(entry)
+		if err != nil {
+			return nil, xerrors.Errorf("ReadFile: %w", err)
+		}
+		migrations = append(migrations, Migration{version, name, string(data)})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, xerrors.Errorf("migration %q is out of sequence", migration.Name)
+		}
+	}
+	return migrations, nil
+}
+
+// Migrate brings the database schema up to date, applying any pending
+// migrations in a single transaction. It returns the schema versions before
+// and after migrating.
+//
+// Databases created before versioning was introduced have the initial schema
+// but no schema_version table, so they're treated as being at version 1.
+//
+// Foreign key enforcement is switched off while migrating so that migrations
+// can rebuild tables that other tables refer to, following the procedure in
+// https://www.sqlite.org/lang_altertable.html#otheralter. The constraints are
+// checked before the transaction is committed.
+func Migrate(ctx context.Context, dbx *sql.DB) (int, int, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	conn, err := dbx.Conn(ctx)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("Conn: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return 0, 0, xerrors.Errorf("disable foreign_keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_version (
+		version    INTEGER PRIMARY KEY,
+		applied_at DATETIME NOT NULL
+	)`)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("create schema_version: %w", err)
+	}
+
+	var current sql.NullInt64
+	err = tx.QueryRowContext(ctx, "SELECT max(version) FROM schema_version").Scan(&current)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("read schema_version: %w", err)
+	}
+	var from = int(current.Int64)
+	if !current.Valid {
+		var legacy int
+		err = tx.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'puzzles'",
+		).Scan(&legacy)
+		if err != nil {
+			return 0, 0, xerrors.Errorf("check legacy schema: %w", err)
+		} else if legacy > 0 {
+			from = 1
+			if err := recordVersion(ctx, tx, from); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	if from > len(migrations) {
+		return from, from, xerrors.Errorf(
+			"database schema is at version %d, but this binary only knows up to "+
+				"version %d; refusing to start", from, len(migrations),
+		)
+	}
+	for _, migration := range migrations[from:] {
+		if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+			return from, from, xerrors.Errorf("migration %s: %w", migration.Name, err)
+		}
+		if err := recordVersion(ctx, tx, migration.Version); err != nil {
+			return from, from, err
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return from, from, xerrors.Errorf("foreign_key_check: %w", err)
+	}
+	var violation = rows.Next()
+	rows.Close()
+	if violation {
+		return from, from, xerrors.Errorf("migrations left foreign key violations")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return from, from, xerrors.Errorf("Commit: %w", err)
+	}
+	return from, len(migrations), nil
+}
+
+func recordVersion(ctx context.Context, tx *sql.Tx, version int) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_version (version, applied_at) VALUES (?, ?)",
+		version, time.Now(),
+	)
+	if err != nil {
+		return xerrors.Errorf("record schema_version: %w", err)
+	}
+	return nil
+}
@@ -3,19 +3,62 @@ package state
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
-	"errors"
 
 	"github.com/emojihunt/emojihunt/state/db"
 	"golang.org/x/xerrors"
 )
 
+const (
+	ScraperHTML = "html"
+	ScraperJSON = "json"
+)
+
 type DiscoveryConfig struct {
 	// URL of the "All Puzzles" page on the hunt website
 	PuzzlesURL  string `json:"puzzles_url"`
 	CookieName  string `json:"cookie_name"`
 	CookieValue string `json:"cookie_value"`
 
+	ScraperConfig
+
+	// URL of the websocket endpoint (optional)
+	WebsocketURL string `json:"websocket_url"`
+
+	// Token to send in the AUTH message (optional)
+	WebsocketToken string `json:"websocket_token"`
+
+	// Optional: more pages to scrape alongside the puzzle list, e.g. a list of
+	// side quests. Their results are merged with the puzzle list's.
+	Sources []DiscoverySource `json:"sources"`
+}
+
+// A DiscoverySource is an additional page to scrape for puzzles.
+type DiscoverySource struct {
+	URL string `json:"url"`
+
+	// Optional: prepended to the name of each puzzle, e.g. "[Task] "
+	NamePrefix string `json:"name_prefix"`
+
+	// Optional: puts all of the source's puzzles in this round, instead of the
+	// scraped one
+	RoundName string `json:"round_name"`
+
+	// Optional: if blank, the puzzle list's cookie is sent
+	CookieName  string `json:"cookie_name"`
+	CookieValue string `json:"cookie_value"`
+
+	// Optional: if left entirely blank, the puzzle list's scraper settings are
+	// used
+	ScraperConfig
+}
+
+// ScraperConfig describes how to read puzzles from a page. It's embedded (and
+// flattened, in JSON) in DiscoveryConfig and DiscoverySource.
+type ScraperConfig struct {
+	// How to read the puzzle list: ScraperHTML (the default) uses the CSS
+	// selectors, and ScraperJSON uses the JSONPath expressions.
+	Scraper string `json:"scraper"`
+
 	// Group Mode: in many years (2021, 2020, etc.), the puzzle list is grouped
 	// by round, and there is some grouping element (e.g. a <section>) for each
 	// round that contains both the round name and the list of puzzles.
@@ -58,32 +101,44 @@ type DiscoveryConfig struct {
 	// Optional: defaults to "a" (this is probably what you want)
 	PuzzleItemSelector string `json:"puzzle_item_selector"`
 
-	// URL of the websocket endpoint (optional)
-	WebsocketURL string `json:"websocket_url"`
-
-	// Token to send in the AUTH message (optional)
-	WebsocketToken string `json:"websocket_token"`
+	// JSON: some hunts publish the puzzle list through an API. Each expression
+	// is a JSONPath, though only `$`, `.key`, `['key']`, `[0]` and `[*]` are
+	// supported.
+	//
+	// If the rounds path is set, it selects the rounds, and the round name and
+	// puzzles are found relative to each round. Otherwise, the puzzles path
+	// selects every puzzle in the document, and the round name is found relative
+	// to each puzzle. The puzzle name and URL are relative to the puzzle; URLs
+	// are resolved against the puzzles URL, so slugs work too.
+	//
+	// EXAMPLES
+	//
+	// {"rounds": [{"title": "...", "puzzles": [{"name": "...", "url": "..."}]}]}
+	// - Rounds:      `$.rounds[*]`
+	// - Round Name:  `$.title`
+	// - Puzzles:     `$.puzzles[*]`
+	// - Puzzle Name: `$.name`
+	// - Puzzle URL:  `$.url`
+	//
+	// {"puzzles": [{"round": {"name": "..."}, "title": "...", "slug": "..."}]}
+	// - Round Name:  `$.round.name`
+	// - Puzzles:     `$.puzzles[*]`
+	// - Puzzle Name: `$.title`
+	// - Puzzle URL:  `$.slug`
+	//
+	RoundsPath     string `json:"rounds_path"`
+	RoundNamePath  string `json:"round_name_path"`
+	PuzzlesPath    string `json:"puzzles_path"`
+	PuzzleNamePath string `json:"puzzle_name_path"`
+	PuzzleURLPath  string `json:"puzzle_url_path"`
 
-	// Honestly, these fields should live somewhere else
-	HuntName        string `json:"hunt_name"`
-	HuntURL         string `json:"hunt_url"`
-	HuntCredentials string `json:"hunt_credentials"`
-	LogisticsURL    string `json:"logistics_url"`
+	// Optional: for GraphQL APIs, a query to POST to the puzzles URL. The
+	// paths are evaluated against the whole response, including `data`.
+	GraphQLQuery string `json:"graphql_query"`
 }
 
 func (c *Client) DiscoveryConfig(ctx context.Context) (DiscoveryConfig, error) {
-	data, err := c.queries.GetSetting(ctx, discoveryConfigSetting)
-	if errors.Is(err, sql.ErrNoRows) {
-		return DiscoveryConfig{}, nil
-	} else if err != nil {
-		return DiscoveryConfig{}, xerrors.Errorf("GetSetting: %w", err)
-	}
-	var result DiscoveryConfig
-	err = json.Unmarshal(data, &result)
-	if err != nil {
-		return DiscoveryConfig{}, xerrors.Errorf("setting unmarshal: %w", err)
-	}
-	return result, nil
+	return getSetting(ctx, c, DiscoveryConfigSetting)
 }
 
 func (c *Client) UpdateDiscoveryConfig(ctx context.Context,
@@ -95,16 +150,20 @@ func (c *Client) UpdateDiscoveryConfig(ctx context.Context,
 		return DiscoveryConfig{}, err
 	} else if err := mutate(&config); err != nil {
 		return DiscoveryConfig{}, err
-	} else if err := c.writeSetting(ctx, discoveryConfigSetting, config); err != nil {
+	} else if err := validateDiscoveryConfig(config); err != nil {
+		return DiscoveryConfig{}, err
+	} else if err := c.writeSetting(ctx, DiscoveryConfigSetting.Key, config); err != nil {
 		return DiscoveryConfig{}, xerrors.Errorf("writeSetting: %w", err)
 	}
 	c.DiscoveryChange <- true
 	return c.DiscoveryConfig(ctx)
 }
 
+// Puzzles in the trash count as created, so that discovery doesn't re-create
+// a puzzle the QMs deleted.
 func (c *Client) IsPuzzleCreated(ctx context.Context, puzzle ScrapedPuzzle) (bool, error) {
-	count, err := c.queries.CheckPuzzleIsCreated(ctx, db.CheckPuzzleIsCreatedParams{
-		Name: puzzle.Name, PuzzleURL: puzzle.PuzzleURL,
+	count, err := c.queries(ctx).CheckPuzzleIsCreated(ctx, db.CheckPuzzleIsCreatedParams{
+		Hunt: c.hunt(ctx), Name: puzzle.Name, PuzzleURL: puzzle.PuzzleURL,
 	})
 	if err != nil {
 		return false, xerrors.Errorf("CheckPuzzleIsCreated: %w", err)
@@ -113,8 +172,8 @@ func (c *Client) IsPuzzleCreated(ctx context.Context, puzzle ScrapedPuzzle) (boo
 }
 
 func (c *Client) IsPuzzleDiscovered(ctx context.Context, puzzle ScrapedPuzzle) (bool, error) {
-	count, err := c.queries.CheckPuzzleIsDiscovered(ctx, db.CheckPuzzleIsDiscoveredParams{
-		Name: puzzle.Name, PuzzleURL: puzzle.PuzzleURL,
+	count, err := c.queries(ctx).CheckPuzzleIsDiscovered(ctx, db.CheckPuzzleIsDiscoveredParams{
+		Hunt: c.hunt(ctx), Name: puzzle.Name, PuzzleURL: puzzle.PuzzleURL,
 	})
 	if err != nil {
 		return false, xerrors.Errorf("CheckPuzzleIsDiscovered: %w", err)
@@ -122,8 +181,13 @@ func (c *Client) IsPuzzleDiscovered(ctx context.Context, puzzle ScrapedPuzzle) (
 	return count > 0, nil
 }
 
+// Rounds in the trash don't count: if the hunt still lists a round the QMs
+// deleted, it goes back to #qm for approval like any other new round, and the
+// QMs can either approve it or restore the old round from the trash.
 func (c *Client) GetCreatedRound(ctx context.Context, name string) (Round, error) {
-	round, err := c.queries.GetCreatedRound(ctx, name)
+	round, err := c.queries(ctx).GetCreatedRound(ctx, db.GetCreatedRoundParams{
+		Hunt: c.hunt(ctx), Name: name,
+	})
 	if err != nil {
 		return Round{}, xerrors.Errorf("GetCreatedRound: %w", err)
 	}
@@ -131,15 +195,28 @@ func (c *Client) GetCreatedRound(ctx context.Context, name string) (Round, error
 }
 
 func (c *Client) GetDiscoveredRound(ctx context.Context, name string) (db.DiscoveredRound, error) {
-	discovered, err := c.queries.GetDiscoveredRound(ctx, name)
+	discovered, err := c.queries(ctx).GetDiscoveredRound(ctx, db.GetDiscoveredRoundParams{
+		Hunt: c.hunt(ctx), Name: name,
+	})
 	if err != nil {
 		return db.DiscoveredRound{}, xerrors.Errorf("GetDiscoveredRound: %w", err)
 	}
 	return discovered, nil
 }
 
+func (c *Client) GetDiscoveredRoundByID(ctx context.Context, id int64) (db.DiscoveredRound, error) {
+	discovered, err := c.queries(ctx).GetDiscoveredRoundByID(ctx, id)
+	if err != nil {
+		return db.DiscoveredRound{}, xerrors.Errorf("GetDiscoveredRoundByID: %w", err)
+	} else if discovered.Hunt != c.hunt(ctx) {
+		return db.DiscoveredRound{}, xerrors.Errorf("GetDiscoveredRoundByID: %w", sql.ErrNoRows)
+	}
+	return discovered, nil
+}
+
 func (c *Client) CreateDiscoveredPuzzle(ctx context.Context, puzzle db.CreateDiscoveredPuzzleParams) error {
-	err := c.queries.CreateDiscoveredPuzzle(ctx, puzzle)
+	puzzle.Hunt = c.hunt(ctx)
+	err := c.queries(ctx).CreateDiscoveredPuzzle(ctx, puzzle)
 	if err != nil {
 		return xerrors.Errorf("CreateDiscoveredPuzzle: %w", err)
 	}
@@ -147,8 +224,8 @@ func (c *Client) CreateDiscoveredPuzzle(ctx context.Context, puzzle db.CreateDis
 }
 
 func (c *Client) CreateDiscoveredRound(ctx context.Context, round string) (int64, error) {
-	id, err := c.queries.CreateDiscoveredRound(ctx, db.CreateDiscoveredRoundParams{
-		Name: round,
+	id, err := c.queries(ctx).CreateDiscoveredRound(ctx, db.CreateDiscoveredRoundParams{
+		Name: round, Hunt: c.hunt(ctx),
 	})
 	if err != nil {
 		return 0, xerrors.Errorf("CreateDiscoveredRound: %w", err)
@@ -157,15 +234,22 @@ func (c *Client) CreateDiscoveredRound(ctx context.Context, round string) (int64
 }
 
 func (c *Client) UpdateDiscoveredRound(ctx context.Context, round db.DiscoveredRound) error {
-	err := c.queries.UpdateDiscoveredRound(ctx, db.UpdateDiscoveredRoundParams(round))
+	err := c.queries(ctx).UpdateDiscoveredRound(ctx, db.UpdateDiscoveredRoundParams(round))
 	if err != nil {
 		return xerrors.Errorf("UpdateDiscoveredRound: %w", err)
 	}
 	return nil
 }
 
+// ReopenDiscoveredRound puts a discovered round back in the queue for QM
+// approval. This happens when the round it was created as has been deleted.
+func (c *Client) ReopenDiscoveredRound(ctx context.Context, round db.DiscoveredRound) error {
+	round.CreatedAs, round.MessageID, round.Ignored = 0, "", false
+	return c.UpdateDiscoveredRound(ctx, round)
+}
+
 func (c *Client) ListPendingDiscoveredRounds(ctx context.Context) ([]db.DiscoveredRound, error) {
-	discovered, err := c.queries.ListPendingDiscoveredRounds(ctx)
+	discovered, err := c.queries(ctx).ListPendingDiscoveredRounds(ctx, c.hunt(ctx))
 	if err != nil {
 		return nil, xerrors.Errorf("ListPendingDiscoveredRounds: %w", err)
 	}
@@ -173,7 +257,7 @@ func (c *Client) ListPendingDiscoveredRounds(ctx context.Context) ([]db.Discover
 }
 
 func (c *Client) ListDiscoveredPuzzlesForRound(ctx context.Context, id int64) ([]db.DiscoveredPuzzle, error) {
-	discovered, err := c.queries.ListDiscoveredPuzzlesForRound(
+	discovered, err := c.queries(ctx).ListDiscoveredPuzzlesForRound(
 		ctx, sql.NullInt64{Int64: id, Valid: true},
 	)
 	if err != nil {
@@ -183,7 +267,7 @@ func (c *Client) ListDiscoveredPuzzlesForRound(ctx context.Context, id int64) ([
 }
 
 func (c *Client) CompleteDiscoveredRound(ctx context.Context, id int64, round Round) error {
-	err := c.queries.CompleteDiscoveredRound(ctx, db.CompleteDiscoveredRoundParams{
+	err := c.queries(ctx).CompleteDiscoveredRound(ctx, db.CompleteDiscoveredRoundParams{
 		ID: id, CreatedAs: round.ID,
 	})
 	if err != nil {
@@ -193,7 +277,7 @@ func (c *Client) CompleteDiscoveredRound(ctx context.Context, id int64, round Ro
 }
 
 func (c *Client) ListCreatablePuzzles(ctx context.Context) ([]db.ListCreatablePuzzlesRow, error) {
-	puzzles, err := c.queries.ListCreatablePuzzles(ctx)
+	puzzles, err := c.queries(ctx).ListCreatablePuzzles(ctx, c.hunt(ctx))
 	if err != nil {
 		return nil, xerrors.Errorf("ListCreatablePuzzles: %w", err)
 	}
@@ -201,9 +285,72 @@ func (c *Client) ListCreatablePuzzles(ctx context.Context) ([]db.ListCreatablePu
 }
 
 func (c *Client) CompleteDiscoveredPuzzle(ctx context.Context, id int64) error {
-	err := c.queries.CompleteDiscoveredPuzzle(ctx, id)
+	err := c.queries(ctx).CompleteDiscoveredPuzzle(ctx, id)
 	if err != nil {
 		return xerrors.Errorf("CompleteDiscoveredPuzzle: %w", err)
 	}
 	return nil
 }
+
+func (c *Client) ListDiscoveredPuzzles(ctx context.Context) ([]db.DiscoveredPuzzle, error) {
+	discovered, err := c.queries(ctx).ListDiscoveredPuzzles(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, xerrors.Errorf("ListDiscoveredPuzzles: %w", err)
+	}
+	return discovered, nil
+}
+
+// LogDiscoveredChange records a change to a puzzle on the hunt site, to be
+// posted to #qm. It returns false if the same change has been logged before.
+func (c *Client) LogDiscoveredChange(ctx context.Context, change db.DiscoveredChange) (bool, error) {
+	count, err := c.queries(ctx).CheckDiscoveredChange(ctx, db.CheckDiscoveredChangeParams{
+		Puzzle:    change.Puzzle,
+		Name:      change.Name,
+		RoundName: change.RoundName,
+		Removed:   change.Removed,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("CheckDiscoveredChange: %w", err)
+	} else if count > 0 {
+		return false, nil
+	}
+	err = c.queries(ctx).CreateDiscoveredChange(ctx, db.CreateDiscoveredChangeParams{
+		Hunt:      c.hunt(ctx),
+		Puzzle:    change.Puzzle,
+		Name:      change.Name,
+		RoundName: change.RoundName,
+		Removed:   change.Removed,
+	})
+	if err != nil {
+		return false, xerrors.Errorf("CreateDiscoveredChange: %w", err)
+	}
+	return true, nil
+}
+
+func (c *Client) GetDiscoveredChange(ctx context.Context, id int64) (db.DiscoveredChange, error) {
+	change, err := c.queries(ctx).GetDiscoveredChange(ctx, id)
+	if err != nil {
+		return db.DiscoveredChange{}, xerrors.Errorf("GetDiscoveredChange: %w", err)
+	} else if change.Hunt != c.hunt(ctx) {
+		return db.DiscoveredChange{}, xerrors.Errorf("GetDiscoveredChange: %w", sql.ErrNoRows)
+	}
+	return change, nil
+}
+
+func (c *Client) ListPendingDiscoveredChanges(ctx context.Context) ([]db.DiscoveredChange, error) {
+	changes, err := c.queries(ctx).ListPendingDiscoveredChanges(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, xerrors.Errorf("ListPendingDiscoveredChanges: %w", err)
+	}
+	return changes, nil
+}
+
+func (c *Client) UpdateDiscoveredChange(ctx context.Context, change db.DiscoveredChange) error {
+	err := c.queries(ctx).UpdateDiscoveredChange(ctx, db.UpdateDiscoveredChangeParams{
+		ID: change.ID, MessageID: change.MessageID, NotifiedAt: change.NotifiedAt,
+	})
+	if err != nil {
+		return xerrors.Errorf("UpdateDiscoveredChange: %w", err)
+	}
+	return nil
+}
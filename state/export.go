@@ -0,0 +1,194 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+// ArchiveVersion identifies the format of the Archive. Bump it when making
+// incompatible changes, so that old archives can still be recognized.
+const ArchiveVersion = 1
+
+// Archive is a complete dump of the hunt, for post-hunt writeups and for
+// seeding a fresh database. Objects reference one another by ID.
+type Archive struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	HuntName   string    `json:"hunt_name"`
+	HuntURL    string    `json:"hunt_url"`
+
+	Rounds             []Round               `json:"rounds"`
+	Puzzles            []ArchivePuzzle       `json:"puzzles"`
+	Feeds              []PuzzleFeed          `json:"feeds"`
+	Guesses            []Guess               `json:"guesses"`
+	History            []PuzzleHistory       `json:"history"`
+	Reminders          []ArchiveReminder     `json:"reminders"`
+	ScheduledReminders []Reminder            `json:"scheduled_reminders"`
+	DiscoveredRounds   []db.DiscoveredRound  `json:"discovered_rounds"`
+	DiscoveredPuzzles  []db.DiscoveredPuzzle `json:"discovered_puzzles"`
+
+	// The most recent entries in the changelog (it's pruned as it grows)
+	Changes []AblySyncMessage `json:"changes"`
+}
+
+type ArchivePuzzle struct {
+	RawPuzzle
+	Tags []string `json:"tags"`
+
+	// When the puzzle was last marked solved, according to the puzzle history.
+	SolvedAt *time.Time `json:"solved_at"`
+}
+
+// ArchiveReminder is a puzzle's own reminder, from Puzzle.Reminder. Reminders
+// set with /remind are archived as ScheduledReminders.
+type ArchiveReminder struct {
+	Puzzle   int64     `json:"puzzle"`
+	Name     string    `json:"name"`
+	Reminder time.Time `json:"reminder"`
+}
+
+// Export builds an Archive of the whole hunt. Writes are paused while the data
+// is read so the archive is consistent.
+func (c *Client) Export(ctx context.Context) (Archive, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var archive = Archive{Version: ArchiveVersion, ExportedAt: time.Now()}
+	hunt, err := c.GetHunt(ctx, c.hunt(ctx))
+	if err != nil {
+		return Archive{}, err
+	}
+	info, err := c.HuntInfo(ctx)
+	if err != nil {
+		return Archive{}, err
+	}
+	archive.HuntName, archive.HuntURL = hunt.Name, info.HuntURL
+
+	if archive.Rounds, err = c.ListRounds(ctx); err != nil {
+		return Archive{}, err
+	}
+	if archive.History, err = c.queries(ctx).ListAllPuzzleHistory(ctx, hunt.ID); err != nil {
+		return Archive{}, xerrors.Errorf("ListAllPuzzleHistory: %w", err)
+	}
+	var times = puzzleTimes(archive.History)
+
+	puzzles, err := c.ListPuzzles(ctx)
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, puzzle := range puzzles {
+		var item = ArchivePuzzle{RawPuzzle: puzzle.RawPuzzle(), Tags: puzzle.TagList()}
+		if t, ok := times[puzzle.ID]; ok && puzzle.Status.IsSolved() {
+			item.SolvedAt = t.SolvedAt
+		}
+		archive.Puzzles = append(archive.Puzzles, item)
+		if puzzle.HasReminder() {
+			archive.Reminders = append(archive.Reminders, ArchiveReminder{
+				puzzle.ID, puzzle.Name, puzzle.Reminder,
+			})
+		}
+	}
+
+	if archive.Feeds, err = c.ListFeeds(ctx); err != nil {
+		return Archive{}, err
+	}
+	if archive.Guesses, err = c.queries(ctx).ListAllGuesses(ctx, hunt.ID); err != nil {
+		return Archive{}, xerrors.Errorf("ListAllGuesses: %w", err)
+	}
+	if archive.ScheduledReminders, err = c.ListReminders(ctx); err != nil {
+		return Archive{}, err
+	}
+	if archive.DiscoveredRounds, err = c.queries(ctx).ListDiscoveredRounds(ctx, hunt.ID); err != nil {
+		return Archive{}, xerrors.Errorf("ListDiscoveredRounds: %w", err)
+	}
+	if archive.DiscoveredPuzzles, err = c.queries(ctx).ListDiscoveredPuzzles(ctx, hunt.ID); err != nil {
+		return Archive{}, xerrors.Errorf("ListDiscoveredPuzzles: %w", err)
+	}
+	if archive.Changes, err = c.Changes(ctx); err != nil {
+		return Archive{}, err
+	}
+	return archive, nil
+}
+
+// ImportArchive recreates the rounds and puzzles from an Archive in the current
+// hunt, along with the puzzles' tags and feeds. Objects get new IDs, and the
+// rest of the archive (history, guesses, discovery) isn't imported. If any
+// object can't be imported, nothing is.
+//
+// The archived Discord categories, channels, spreadsheets and voice rooms still
+// belong to the old hunt, so they're cleared. Rounds keep their DriveFolder,
+// which is required: callers should replace it with a new folder first (see
+// server.ImportArchive). Imported puzzles aren't announced as new.
+func (c *Client) ImportArchive(ctx context.Context, archive Archive) ([]Round, []Puzzle, int64, error) {
+	if archive.Version != ArchiveVersion {
+		return nil, nil, 0, ValidationError{"version",
+			fmt.Sprintf("must be %d, got %d", ArchiveVersion, archive.Version)}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var rounds []Round
+	var puzzles []Puzzle
+	var changeID int64
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		var roundIDs = make(map[int64]int64)
+		for _, round := range archive.Rounds {
+			var id = round.ID
+			round.ID, round.Hunt = 0, 0
+			round.DiscordCategory = ""
+			change, err := c.createRound(ctx, round)
+			if err != nil {
+				return xerrors.Errorf("round %q: %w", round.Name, err)
+			}
+			roundIDs[id] = change.After.ID
+			rounds = append(rounds, *change.After)
+			changeID = change.ChangeID
+		}
+
+		var puzzleIDs = make(map[int64]int64)
+		for _, puzzle := range archive.Puzzles {
+			var raw = puzzle.RawPuzzle
+			raw.SpreadsheetID, raw.DiscordChannel, raw.VoiceRoom = "", "", ""
+			var ok bool
+			if raw.Round, ok = roundIDs[raw.Round]; !ok {
+				return xerrors.Errorf("puzzle %q: %w", raw.Name,
+					ValidationError{"round", "is not in the archive"})
+			}
+			change, err := c.createPuzzle(ctx, raw, puzzle.Tags, true)
+			if err != nil {
+				return xerrors.Errorf("puzzle %q: %w", raw.Name, err)
+			}
+			puzzleIDs[puzzle.ID] = change.After.ID
+			puzzles = append(puzzles, *change.After)
+			changeID = change.ChangeID
+		}
+
+		for _, feed := range archive.Feeds {
+			var remapped = PuzzleFeed{Puzzle: puzzleIDs[feed.Puzzle], Meta: puzzleIDs[feed.Meta]}
+			if remapped.Puzzle == 0 || remapped.Meta == 0 {
+				continue // one end is in the trash
+			}
+			_, err := c.queries(ctx).CreatePuzzleFeed(ctx, db.CreatePuzzleFeedParams(remapped))
+			if err != nil {
+				return xerrors.Errorf("CreatePuzzleFeed: %w", err)
+			} else if !c.publishes(c.hunt(ctx)) {
+				continue
+			}
+			change, err := c.LogFeedChange(ctx, nil, &remapped)
+			if err != nil {
+				return err
+			}
+			changeID = change.ChangeID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return rounds, puzzles, changeID, nil
+}
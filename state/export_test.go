@@ -0,0 +1,101 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/status"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	var ctx = WithActor(context.Background(), ActorSync)
+	var src = NewMemory(ctx)
+
+	round, _, err := src.CreateRound(ctx, Round{
+		Name: "Fruit", Emoji: "🍎", Hue: 10, DriveFolder: "folder",
+		DiscordCategory: "category",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reminder = time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)
+	meta, _, err := src.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Orchard", Round: round.ID, PuzzleURL: "https://example.com/orchard",
+		Meta: true, Reminder: reminder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	puzzle, _, err := src.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+		Status: status.Solved, Answer: "PIPPIN", Note: "done",
+		SpreadsheetID: "sheet", DiscordChannel: "channel", VoiceRoom: "voice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := src.AddPuzzleTag(ctx, puzzle.ID, "wordplay"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.CreateFeed(ctx, PuzzleFeed{Puzzle: puzzle.ID, Meta: meta.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	exported, err := src.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Go through JSON, as archive.json does
+	encoded, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archive Archive
+	if err := json.Unmarshal(encoded, &archive); err != nil {
+		t.Fatal(err)
+	}
+
+	var dst = NewMemory(ctx)
+	rounds, puzzles, _, err := dst.ImportArchive(ctx, archive)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rounds) != 1 || len(puzzles) != 2 {
+		t.Fatalf("imported %d rounds and %d puzzles, want 1 and 2", len(rounds), len(puzzles))
+	}
+
+	reexported, err := dst.Export(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reexported.Rounds[0], exported.Rounds[0]; got.Name != want.Name ||
+		got.Emoji != want.Emoji || got.Hue != want.Hue || got.DriveFolder != want.DriveFolder ||
+		got.DiscordCategory != "" {
+		t.Errorf("round: got %+v, want %+v", got, want)
+	}
+	for i, got := range reexported.Puzzles {
+		// The old hunt's channels, sheets and voice rooms aren't carried over
+		var want = exported.Puzzles[i]
+		want.SpreadsheetID, want.DiscordChannel, want.VoiceRoom = "", "", ""
+		got.ID, got.Round, got.Version = want.ID, want.Round, want.Version
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("puzzle %d:\n got %s\nwant %s", i, gotJSON, wantJSON)
+		}
+	}
+	if len(reexported.Feeds) != 1 {
+		t.Errorf("got %d feeds, want 1", len(reexported.Feeds))
+	}
+	for len(dst.PuzzleRoundChange) > 0 {
+		if change, ok := (<-dst.PuzzleRoundChange).(PuzzleChange); ok && !change.Imported {
+			t.Errorf("puzzle %q: change isn't marked as imported", change.After.Name)
+		}
+	}
+
+	archive.Version = ArchiveVersion + 1
+	if _, _, _, err := NewMemory(ctx).ImportArchive(ctx, archive); err == nil {
+		t.Errorf("expected an error importing an archive with the wrong version")
+	}
+}
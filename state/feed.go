@@ -0,0 +1,152 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+type (
+	// PuzzleFeed records that a puzzle's answer feeds into a meta.
+	PuzzleFeed = db.PuzzleFeed
+
+	// FeedInfo is the puzzle on the other side of a PuzzleFeed.
+	FeedInfo = db.ListFeedersRow
+)
+
+func (c *Client) ListFeeds(ctx context.Context) ([]PuzzleFeed, error) {
+	feeds, err := c.queries(ctx).ListPuzzleFeeds(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, xerrors.Errorf("ListPuzzleFeeds: %w", err)
+	}
+	return feeds, nil
+}
+
+// ListFeeders returns the puzzles that feed into the given meta.
+func (c *Client) ListFeeders(ctx context.Context, meta int64) ([]FeedInfo, error) {
+	// Used by sync! To avoid deadlocks, this function must not acquire the global
+	// database lock.
+	feeders, err := c.queries(ctx).ListFeeders(ctx, meta)
+	if err != nil {
+		return nil, xerrors.Errorf("ListFeeders: %w", err)
+	}
+	return feeders, nil
+}
+
+// ListFedMetas returns the metas that the given puzzle feeds into.
+func (c *Client) ListFedMetas(ctx context.Context, puzzle int64) ([]FeedInfo, error) {
+	// Used by sync! To avoid deadlocks, this function must not acquire the global
+	// database lock.
+	results, err := c.queries(ctx).ListFedMetas(ctx, puzzle)
+	if err != nil {
+		return nil, xerrors.Errorf("ListFedMetas: %w", err)
+	}
+	var metas = make([]FeedInfo, len(results))
+	for i, result := range results {
+		metas[i] = FeedInfo(result)
+	}
+	return metas, nil
+}
+
+func (c *Client) CreateFeed(ctx context.Context, feed PuzzleFeed) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if feed.Puzzle == feed.Meta {
+		return 0, ValidationError{"meta", "must be a different puzzle"}
+	}
+	var change FeedChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		puzzle, err := c.GetPuzzle(ctx, feed.Puzzle)
+		if err != nil {
+			return err
+		}
+		meta, err := c.GetPuzzle(ctx, feed.Meta)
+		if err != nil {
+			return err
+		} else if !meta.Meta {
+			return ValidationError{"meta", "is not marked as a meta"}
+		} else if puzzle.Round.Hunt != meta.Round.Hunt {
+			return ValidationError{"meta", "belongs to a different hunt"}
+		} else if err := c.checkWritable(ctx, meta.Round.Hunt); err != nil {
+			return err
+		}
+
+		count, err := c.queries(ctx).CreatePuzzleFeed(ctx, db.CreatePuzzleFeedParams(feed))
+		if err != nil {
+			return xerrors.Errorf("CreatePuzzleFeed: %w", err)
+		} else if count == 0 {
+			return ValidationError{"meta", "is already fed by this puzzle"}
+		} else if !c.publishes(meta.Round.Hunt) {
+			return nil
+		}
+		change, err = c.LogFeedChange(ctx, nil, &feed)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return change.ChangeID, nil
+}
+
+func (c *Client) DeleteFeed(ctx context.Context, feed PuzzleFeed) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var change FeedChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		meta, err := c.GetPuzzle(ctx, feed.Meta)
+		if err != nil {
+			return err
+		} else if err := c.checkWritable(ctx, meta.Round.Hunt); err != nil {
+			return err
+		}
+		count, err := c.queries(ctx).DeletePuzzleFeed(ctx, db.DeletePuzzleFeedParams(feed))
+		if err != nil {
+			return xerrors.Errorf("DeletePuzzleFeed: %w", err)
+		} else if count == 0 {
+			return xerrors.Errorf("DeletePuzzleFeed: %w", sql.ErrNoRows)
+		} else if !c.publishes(meta.Round.Hunt) {
+			return nil
+		}
+		change, err = c.LogFeedChange(ctx, &feed, nil)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return change.ChangeID, nil
+}
+
+func (c *Client) LogFeedChange(ctx context.Context, before *PuzzleFeed,
+	after *PuzzleFeed) (FeedChange, error) {
+
+	c.changeID += 1
+
+	var change = FeedChange{before, after, ActorFromContext(ctx), c.changeID}
+	var msg = change.SyncMessage()
+
+	encoded, err := json.Marshal(msg.Feed)
+	if err != nil {
+		return FeedChange{}, xerrors.Errorf("Marshal: %w", err)
+	}
+	err = c.queries(ctx).CreateChangelog(ctx, db.CreateChangelogParams{
+		ID:    change.ChangeID,
+		Kind:  msg.Kind,
+		Feed:  encoded,
+		Actor: string(change.Actor),
+	})
+	if err != nil {
+		return FeedChange{}, xerrors.Errorf("CreateChangelog: %w", err)
+	}
+	err = c.queries(ctx).PruneChangelog(ctx)
+	if err != nil {
+		return FeedChange{}, xerrors.Errorf("PruneChangelog: %w", err)
+	}
+
+	c.notify(ctx, change)
+	return change, nil
+}
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+type Guess = db.Guess
+
+// NormalizeGuess reduces a guess to its letters and digits, uppercased. Hunts
+// ignore spacing and punctuation when checking answers, so guesses that
+// normalize to the same string are duplicates.
+func NormalizeGuess(guess string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, guess)
+}
+
+func (c *Client) ListGuesses(ctx context.Context, puzzle int64) ([]Guess, error) {
+	guesses, err := c.queries(ctx).ListGuesses(ctx, puzzle)
+	if err != nil {
+		return nil, xerrors.Errorf("ListGuesses: %w", err)
+	}
+	return guesses, nil
+}
+
+// FindGuess returns the earlier guess that's a duplicate of the given one, if
+// any.
+func (c *Client) FindGuess(ctx context.Context, puzzle int64, guess string) (*Guess, error) {
+	guesses, err := c.ListGuesses(ctx, puzzle)
+	if err != nil {
+		return nil, err
+	}
+	var normalized = NormalizeGuess(guess)
+	for _, existing := range guesses {
+		if NormalizeGuess(existing.Guess) == normalized {
+			return &existing, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateGuess records an answer submission. The actor and timestamp are taken
+// from the context and the current time, respectively.
+func (c *Client) CreateGuess(ctx context.Context, guess Guess) (Guess, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	guess.Guess = strings.ToUpper(strings.TrimSpace(guess.Guess))
+	if NormalizeGuess(guess.Guess) == "" {
+		return Guess{}, 0, ValidationError{"guess", "is required"}
+	} else if !guess.Result.IsValid() {
+		return Guess{}, 0, ValidationError{"result", "is invalid"}
+	}
+	var created Guess
+	var change GuessChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		puzzle, err := c.GetPuzzle(ctx, guess.Puzzle)
+		if err != nil {
+			return err
+		} else if err := c.checkWritable(ctx, puzzle.Round.Hunt); err != nil {
+			return err
+		}
+		previous, err := c.FindGuess(ctx, guess.Puzzle, guess.Guess)
+		if err != nil {
+			return err
+		} else if previous != nil {
+			return ValidationError{
+				"guess", "has already been submitted (" + string(previous.Result) + ")",
+			}
+		}
+
+		created, err = c.queries(ctx).CreateGuess(ctx, db.CreateGuessParams{
+			Puzzle:      guess.Puzzle,
+			Guess:       guess.Guess,
+			Result:      guess.Result,
+			Note:        guess.Note,
+			Actor:       string(ActorFromContext(ctx)),
+			SubmittedAt: time.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("CreateGuess: %w", err)
+		} else if !c.publishes(puzzle.Round.Hunt) {
+			return nil
+		}
+		change, err = c.LogGuessChange(ctx, &created)
+		return err
+	})
+	if err != nil {
+		return Guess{}, 0, err
+	}
+	return created, change.ChangeID, nil
+}
+
+func (c *Client) LogGuessChange(ctx context.Context, guess *Guess) (GuessChange, error) {
+	c.changeID += 1
+
+	var change = GuessChange{guess, ActorFromContext(ctx), c.changeID}
+	var msg = change.SyncMessage()
+
+	encoded, err := json.Marshal(msg.Guess)
+	if err != nil {
+		return GuessChange{}, xerrors.Errorf("Marshal: %w", err)
+	}
+	err = c.queries(ctx).CreateChangelog(ctx, db.CreateChangelogParams{
+		ID:    change.ChangeID,
+		Kind:  msg.Kind,
+		Guess: encoded,
+		Actor: string(change.Actor),
+	})
+	if err != nil {
+		return GuessChange{}, xerrors.Errorf("CreateChangelog: %w", err)
+	}
+	err = c.queries(ctx).PruneChangelog(ctx)
+	if err != nil {
+		return GuessChange{}, xerrors.Errorf("PruneChangelog: %w", err)
+	}
+
+	c.notify(ctx, change)
+	return change, nil
+}
@@ -0,0 +1,103 @@
+package state
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+type PuzzleHistory = db.PuzzleHistory
+
+type historyField struct {
+	Name  string
+	Value string
+}
+
+// The values recorded in the puzzle history. Rounds are recorded by name so
+// the history stays readable even if the round is later deleted.
+func (p Puzzle) historyFields() []historyField {
+	var reminder string
+	if p.HasReminder() {
+		reminder = p.Reminder.Format(time.RFC3339)
+	}
+	return []historyField{
+		{"name", p.Name},
+		{"answer", p.Answer},
+		{"round", p.Round.Name},
+		{"status", string(p.Status)},
+		{"note", p.Note},
+		{"location", p.Location},
+		{"puzzle_url", p.PuzzleURL},
+		{"spreadsheet_id", p.SpreadsheetID},
+		{"discord_channel", p.DiscordChannel},
+		{"meta", strconv.FormatBool(p.Meta)},
+		{"voice_room", p.VoiceRoom},
+		{"reminder", reminder},
+		{"priority", strconv.FormatInt(int64(p.Priority), 10)},
+		{"needs_help", strconv.FormatBool(p.NeedsHelp)},
+		{"tags", p.Tags},
+		{"solvers", p.Solvers},
+	}
+}
+
+// Every history entry has an actor. Changes made without one in the context
+// come from the syncer.
+func historyActor(ctx context.Context) Actor {
+	if actor := ActorFromContext(ctx); actor != "" {
+		return actor
+	}
+	return ActorSync
+}
+
+// Must hold the global lock.
+func (c *Client) logPuzzleHistory(ctx context.Context, before *Puzzle, after *Puzzle) error {
+	var now = time.Now()
+	var actor = historyActor(ctx)
+	var prev, next = before.historyFields(), after.historyFields()
+	for i := range prev {
+		if prev[i].Value == next[i].Value {
+			continue
+		}
+		err := c.queries(ctx).CreatePuzzleHistory(ctx, db.CreatePuzzleHistoryParams{
+			Puzzle:    after.ID,
+			Field:     next[i].Name,
+			OldValue:  prev[i].Value,
+			NewValue:  next[i].Value,
+			ChangedAt: now,
+			Actor:     string(actor),
+		})
+		if err != nil {
+			return xerrors.Errorf("CreatePuzzleHistory: %w", err)
+		}
+	}
+	return nil
+}
+
+// Records a puzzle lifecycle event that isn't captured by historyFields: the
+// puzzle being "created", or being "deleted" (moved to the trash) and restored.
+// Must hold the global lock.
+func (c *Client) logPuzzleEvent(ctx context.Context, id int64, field string, value bool) error {
+	err := c.queries(ctx).CreatePuzzleHistory(ctx, db.CreatePuzzleHistoryParams{
+		Puzzle:    id,
+		Field:     field,
+		OldValue:  strconv.FormatBool(!value),
+		NewValue:  strconv.FormatBool(value),
+		ChangedAt: time.Now(),
+		Actor:     string(historyActor(ctx)),
+	})
+	if err != nil {
+		return xerrors.Errorf("CreatePuzzleHistory: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) ListPuzzleHistory(ctx context.Context, id int64) ([]PuzzleHistory, error) {
+	history, err := c.queries(ctx).ListPuzzleHistory(ctx, id)
+	if err != nil {
+		return nil, xerrors.Errorf("ListPuzzleHistory: %w", err)
+	}
+	return history, nil
+}
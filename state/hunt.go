@@ -0,0 +1,165 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+// A Hunt groups a set of rounds, puzzles, discovery records and settings. One
+// database can hold several hunts (previous years, practice events), but only
+// one is active at a time: the bots, discovery and the syncer all work on the
+// active hunt. Archived hunts are read-only.
+type Hunt = db.Hunt
+
+// ArchivedError is returned when a write touches a hunt that has been archived.
+type ArchivedError struct {
+	Hunt int64
+}
+
+func (e ArchivedError) Error() string {
+	return fmt.Sprintf("hunt %d is archived", e.Hunt)
+}
+
+type huntKey struct{}
+
+// WithHunt scopes the context to the given hunt. Reads and writes made with the
+// returned context operate on that hunt instead of the active hunt.
+func WithHunt(ctx context.Context, hunt int64) context.Context {
+	return context.WithValue(ctx, huntKey{}, hunt)
+}
+
+// hunt returns the ID of the hunt the context is scoped to, or the active hunt
+// if it isn't scoped.
+func (c *Client) hunt(ctx context.Context) int64 {
+	if hunt, ok := ctx.Value(huntKey{}).(int64); ok {
+		return hunt
+	}
+	return c.activeHunt.Load()
+}
+
+// ActiveHunt returns the ID of the active hunt.
+func (c *Client) ActiveHunt() int64 {
+	return c.activeHunt.Load()
+}
+
+// IsActive reports whether the context is scoped to the active hunt. Only the
+// active hunt has Discord channels, Drive folders and live updates.
+func (c *Client) IsActive(ctx context.Context) bool {
+	return c.hunt(ctx) == c.ActiveHunt()
+}
+
+func (c *Client) GetHunt(ctx context.Context, id int64) (Hunt, error) {
+	hunt, err := c.queries(ctx).GetHunt(ctx, id)
+	if err != nil {
+		return Hunt{}, xerrors.Errorf("GetHunt: %w", err)
+	}
+	return hunt, nil
+}
+
+func (c *Client) ListHunts(ctx context.Context) ([]Hunt, error) {
+	hunts, err := c.queries(ctx).ListHunts(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("ListHunts: %w", err)
+	}
+	return hunts, nil
+}
+
+// CreateHunt creates a new, inactive hunt.
+func (c *Client) CreateHunt(ctx context.Context, name string) (Hunt, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Hunt{}, ValidationError{"name", "is required"}
+	}
+	hunt, err := c.queries(ctx).CreateHunt(ctx, db.CreateHuntParams{
+		Name: name, CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return Hunt{}, xerrors.Errorf("CreateHunt: %w", err)
+	}
+	return hunt, nil
+}
+
+// UpdateHunt renames and/or archives (or unarchives) a hunt. The active hunt
+// can't be archived.
+func (c *Client) UpdateHunt(ctx context.Context, id int64, name string, archived bool) (Hunt, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hunt, err := c.GetHunt(ctx, id)
+	if err != nil {
+		return Hunt{}, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Hunt{}, ValidationError{"name", "is required"}
+	} else if archived && hunt.Active {
+		return Hunt{}, ValidationError{"archived", "can't be set on the active hunt"}
+	}
+	err = c.queries(ctx).UpdateHunt(ctx, db.UpdateHuntParams{
+		ID: id, Name: name, Archived: archived,
+	})
+	if err != nil {
+		return Hunt{}, xerrors.Errorf("UpdateHunt: %w", err)
+	}
+	return c.GetHunt(ctx, id)
+}
+
+// SetActiveHunt switches the bots, discovery and the syncer over to the given
+// hunt. Archived hunts can't be made active.
+func (c *Client) SetActiveHunt(ctx context.Context, id int64) (Hunt, error) {
+	hunt, err := c.setActiveHunt(ctx, id)
+	if err != nil {
+		return Hunt{}, err
+	}
+	c.DiscoveryChange <- true
+	return hunt, nil
+}
+
+func (c *Client) setActiveHunt(ctx context.Context, id int64) (Hunt, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	hunt, err := c.GetHunt(ctx, id)
+	if err != nil {
+		return Hunt{}, err
+	} else if hunt.Archived {
+		return Hunt{}, ArchivedError{id}
+	}
+
+	err = c.transaction(ctx, func(ctx context.Context) error {
+		if err := c.queries(ctx).DeactivateHunts(ctx); err != nil {
+			return xerrors.Errorf("DeactivateHunts: %w", err)
+		}
+		if count, err := c.queries(ctx).ActivateHunt(ctx, id); err != nil {
+			return xerrors.Errorf("ActivateHunt: %w", err)
+		} else if count == 0 {
+			return xerrors.Errorf("ActivateHunt: %w", sql.ErrNoRows)
+		}
+		return nil
+	})
+	if err != nil {
+		return Hunt{}, err
+	}
+	c.activeHunt.Store(id)
+	return c.GetHunt(ctx, id)
+}
+
+// checkWritable returns an ArchivedError if the hunt has been archived.
+func (c *Client) checkWritable(ctx context.Context, hunt int64) error {
+	result, err := c.GetHunt(ctx, hunt)
+	if err != nil {
+		return err
+	} else if result.Archived {
+		return ArchivedError{hunt}
+	}
+	return nil
+}
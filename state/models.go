@@ -16,19 +16,29 @@ type (
 
 // Must match db.GetPuzzleRow and db.ListPuzzlesRow
 type Puzzle struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          Round         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       time.Time     `json:"reminder"`
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          Round           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       time.Time       `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+
+	// Comma-separated and sorted. Use TagList() to access.
+	Tags string `json:"-"`
+
+	// Comma-separated Discord user IDs, in the order they joined. Use
+	// SolverList() to access.
+	Solvers string `json:"-"`
 }
 
 func (p Puzzle) Mention() string {
@@ -47,16 +57,26 @@ type PuzzleChange struct {
 	Before *Puzzle
 	After  *Puzzle
 
+	// Who made the change. See Actor.
+	Actor Actor
+
 	// If zero, don't broadcast the change to Ably.
 	ChangeID int64
 
 	// An optional channel to notify on completion. Only set when called from a
 	// bot command.
 	BotComplete chan error
+
+	// Set when a puzzle is restored from the trash, so it isn't announced as
+	// new.
+	Restored bool
+
+	// Set when a puzzle is imported from an archive. It isn't new either.
+	Imported bool
 }
 
 func (change PuzzleChange) SyncMessage() AblySyncMessage {
-	var msg = AblySyncMessage{ChangeID: change.ChangeID}
+	var msg = AblySyncMessage{ChangeID: change.ChangeID, Actor: change.Actor}
 	if change.After == nil {
 		msg.Kind = status.AblyKindDelete
 		msg.Puzzle = &AblyPuzzle{ID: change.Before.ID}
@@ -71,11 +91,12 @@ func (change PuzzleChange) SyncMessage() AblySyncMessage {
 type RoundChange struct {
 	Before   *Round
 	After    *Round
+	Actor    Actor
 	ChangeID int64
 }
 
 func (change RoundChange) SyncMessage() AblySyncMessage {
-	var msg = AblySyncMessage{ChangeID: change.ChangeID}
+	var msg = AblySyncMessage{ChangeID: change.ChangeID, Actor: change.Actor}
 	if change.After == nil {
 		msg.Kind = status.AblyKindDelete
 		msg.Round = &Round{ID: change.Before.ID}
@@ -86,6 +107,41 @@ func (change RoundChange) SyncMessage() AblySyncMessage {
 	return msg
 }
 
+type FeedChange struct {
+	Before   *PuzzleFeed
+	After    *PuzzleFeed
+	Actor    Actor
+	ChangeID int64
+}
+
+func (change FeedChange) SyncMessage() AblySyncMessage {
+	var msg = AblySyncMessage{ChangeID: change.ChangeID, Actor: change.Actor}
+	if change.After == nil {
+		msg.Kind = status.AblyKindDelete
+		msg.Feed = change.Before
+	} else {
+		msg.Kind = status.AblyKindUpsert
+		msg.Feed = change.After
+	}
+	return msg
+}
+
+// Guesses are append-only, so there's no Before.
+type GuessChange struct {
+	Guess    *Guess
+	Actor    Actor
+	ChangeID int64
+}
+
+func (change GuessChange) SyncMessage() AblySyncMessage {
+	return AblySyncMessage{
+		ChangeID: change.ChangeID,
+		Kind:     status.AblyKindUpsert,
+		Guess:    change.Guess,
+		Actor:    change.Actor,
+	}
+}
+
 type EventType string
 
 const (
@@ -133,24 +189,32 @@ func (p Puzzle) RawPuzzle() RawPuzzle {
 		Meta:           p.Meta,
 		VoiceRoom:      p.VoiceRoom,
 		Reminder:       p.Reminder,
+		Version:        p.Version,
+		Priority:       p.Priority,
+		NeedsHelp:      p.NeedsHelp,
 	}
 }
 
 // Works around an encoding bug involving time.Time
 type AblyPuzzle struct {
-	ID             int64         `json:"id"`
-	Name           string        `json:"name"`
-	Answer         string        `json:"answer"`
-	Round          int64         `json:"round"`
-	Status         status.Status `json:"status"`
-	Note           string        `json:"note"`
-	Location       string        `json:"location"`
-	PuzzleURL      string        `json:"puzzle_url"`
-	SpreadsheetID  string        `json:"spreadsheet_id"`
-	DiscordChannel string        `json:"discord_channel"`
-	Meta           bool          `json:"meta"`
-	VoiceRoom      string        `json:"voice_room"`
-	Reminder       string        `json:"reminder"`
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	Answer         string          `json:"answer"`
+	Round          int64           `json:"round"`
+	Status         status.Status   `json:"status"`
+	Note           string          `json:"note"`
+	Location       string          `json:"location"`
+	PuzzleURL      string          `json:"puzzle_url"`
+	SpreadsheetID  string          `json:"spreadsheet_id"`
+	DiscordChannel string          `json:"discord_channel"`
+	Meta           bool            `json:"meta"`
+	VoiceRoom      string          `json:"voice_room"`
+	Reminder       string          `json:"reminder"`
+	Priority       status.Priority `json:"priority"`
+	NeedsHelp      bool            `json:"needs_help"`
+	Version        int64           `json:"version"`
+	Tags           []string        `json:"tags"`
+	Solvers        []string        `json:"solvers"`
 }
 
 type AblySyncMessage struct {
@@ -158,6 +222,9 @@ type AblySyncMessage struct {
 	Kind     status.AblyKind `json:"kind"`
 	Puzzle   *AblyPuzzle     `json:"puzzle,omitempty"`
 	Round    *Round          `json:"round,omitempty"`
+	Feed     *PuzzleFeed     `json:"feed,omitempty"`
+	Guess    *Guess          `json:"guess,omitempty"`
+	Actor    Actor           `json:"actor,omitempty"`
 }
 
 func (m AblySyncMessage) EventType() EventType {
@@ -179,5 +246,10 @@ func (p Puzzle) AblyPuzzle() AblyPuzzle {
 		Meta:           p.Meta,
 		VoiceRoom:      p.VoiceRoom,
 		Reminder:       p.Reminder.Format(time.RFC3339),
+		Priority:       p.Priority,
+		NeedsHelp:      p.NeedsHelp,
+		Version:        p.Version,
+		Tags:           p.TagList(),
+		Solvers:        p.SolverList(),
 	}
 }
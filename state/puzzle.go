@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/url"
+	"time"
 
 	"github.com/emojihunt/emojihunt/state/db"
 	"golang.org/x/xerrors"
@@ -17,6 +18,8 @@ func (c *Client) ValidatePuzzle(ctx context.Context, p RawPuzzle) error {
 		return ValidationError{"round", "is required"}
 	} else if !p.Status.IsValid() {
 		return ValidationError{"status", "is invalid"}
+	} else if !p.Priority.IsValid() {
+		return ValidationError{"priority", "is invalid"}
 	} else if p.PuzzleURL == "" {
 		return ValidationError{"puzzle_url", "is required"}
 	} else if u, err := url.Parse(p.PuzzleURL); err != nil {
@@ -30,7 +33,7 @@ func (c *Client) ValidatePuzzle(ctx context.Context, p RawPuzzle) error {
 	}
 
 	if p.DiscordChannel != "" {
-		puzzle, err := c.queries.GetPuzzleByChannel(ctx, p.DiscordChannel)
+		puzzle, err := c.queries(ctx).GetPuzzleByChannel(ctx, p.DiscordChannel)
 		if err == nil && puzzle.ID != p.ID {
 			return ValidationError{"discord_channel", "is not unique"}
 		} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -40,8 +43,22 @@ func (c *Client) ValidatePuzzle(ctx context.Context, p RawPuzzle) error {
 	return nil
 }
 
+// Checks that the puzzle's round belongs to a writable hunt and, for existing
+// puzzles, that the puzzle isn't being moved to a different hunt.
+func (c *Client) checkPuzzleHunt(ctx context.Context, before *Puzzle, p RawPuzzle) error {
+	round, err := c.queries(ctx).GetRound(ctx, p.Round)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ValidationError{"round", "does not exist"}
+	} else if err != nil {
+		return xerrors.Errorf("GetRound: %w", err)
+	} else if before != nil && before.Round.Hunt != round.Hunt {
+		return ValidationError{"round", "belongs to a different hunt"}
+	}
+	return c.checkWritable(ctx, round.Hunt)
+}
+
 func (c *Client) GetPuzzle(ctx context.Context, id int64) (Puzzle, error) {
-	puzzle, err := c.queries.GetPuzzle(ctx, id)
+	puzzle, err := c.queries(ctx).GetPuzzle(ctx, id)
 	if err != nil {
 		return Puzzle{}, xerrors.Errorf("GetPuzzle: %w", err)
 	}
@@ -49,7 +66,7 @@ func (c *Client) GetPuzzle(ctx context.Context, id int64) (Puzzle, error) {
 }
 
 func (c *Client) GetPuzzleByChannel(ctx context.Context, channel string) (Puzzle, error) {
-	puzzle, err := c.queries.GetPuzzleByChannel(ctx, channel)
+	puzzle, err := c.queries(ctx).GetPuzzleByChannel(ctx, channel)
 	if err != nil {
 		return Puzzle{}, xerrors.Errorf("GetPuzzleByChannel: %w", err)
 	}
@@ -59,7 +76,7 @@ func (c *Client) GetPuzzleByChannel(ctx context.Context, channel string) (Puzzle
 func (c *Client) ListPuzzles(ctx context.Context) ([]Puzzle, error) {
 	// Used by sync! To avoid deadlocks, this function must not acquire the global
 	// database lock.
-	results, err := c.queries.ListPuzzles(ctx)
+	results, err := c.queries(ctx).ListPuzzles(ctx, c.hunt(ctx))
 	if err != nil {
 		return nil, xerrors.Errorf("ListPuzzles: %w", err)
 	}
@@ -70,28 +87,32 @@ func (c *Client) ListPuzzles(ctx context.Context) ([]Puzzle, error) {
 	return puzzles, nil
 }
 
-func (c *Client) ListHome(ctx context.Context) ([]Puzzle, []Round, int64, DiscoveryConfig, error) {
+func (c *Client) ListHome(ctx context.Context) ([]Puzzle, []Round, int64, Hunt, HuntInfo, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 	puzzles, err := c.ListPuzzles(ctx)
 	if err != nil {
-		return nil, nil, 0, DiscoveryConfig{}, err
+		return nil, nil, 0, Hunt{}, HuntInfo{}, err
 	}
 	rounds, err := c.ListRounds(ctx)
 	if err != nil {
-		return nil, nil, 0, DiscoveryConfig{}, err
+		return nil, nil, 0, Hunt{}, HuntInfo{}, err
+	}
+	hunt, err := c.GetHunt(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, nil, 0, Hunt{}, HuntInfo{}, err
 	}
-	discovery, err := c.DiscoveryConfig(ctx)
+	info, err := c.HuntInfo(ctx)
 	if err != nil {
-		return nil, nil, 0, DiscoveryConfig{}, err
+		return nil, nil, 0, Hunt{}, HuntInfo{}, err
 	}
-	return puzzles, rounds, c.changeID, discovery, nil
+	return puzzles, rounds, c.changeID, hunt, info, nil
 }
 
 func (c *Client) ListVoiceRoomInfo(ctx context.Context) ([]VoiceInfo, error) {
 	// Used by sync! To avoid deadlocks, this function must not acquire the global
 	// database lock.
-	results, err := c.queries.ListPuzzlesByVoiceRoom(ctx)
+	results, err := c.queries(ctx).ListPuzzlesByVoiceRoom(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf("ListPuzzlesByVoiceRoom: %w", err)
 	}
@@ -101,10 +122,28 @@ func (c *Client) ListVoiceRoomInfo(ctx context.Context) ([]VoiceInfo, error) {
 func (c *Client) CreatePuzzle(ctx context.Context, puzzle RawPuzzle) (Puzzle, int64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	if err := c.ValidatePuzzle(ctx, puzzle); err != nil {
+
+	var change PuzzleChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		var err error
+		change, err = c.createPuzzle(ctx, puzzle, nil, false)
+		return err
+	})
+	if err != nil {
 		return Puzzle{}, 0, err
 	}
-	id, err := c.queries.CreatePuzzle(ctx, db.CreatePuzzleParams{
+	return *change.After, change.ChangeID, nil
+}
+
+// Creates the puzzle with the given tags. Imported puzzles aren't announced as
+// new. Must hold the global lock and be called in a transaction.
+func (c *Client) createPuzzle(ctx context.Context, puzzle RawPuzzle, tags []string, imported bool) (PuzzleChange, error) {
+	if err := c.ValidatePuzzle(ctx, puzzle); err != nil {
+		return PuzzleChange{}, err
+	} else if err := c.checkPuzzleHunt(ctx, nil, puzzle); err != nil {
+		return PuzzleChange{}, err
+	}
+	id, err := c.queries(ctx).CreatePuzzle(ctx, db.CreatePuzzleParams{
 		Name:           puzzle.Name,
 		Answer:         puzzle.Answer,
 		Round:          puzzle.Round,
@@ -116,50 +155,58 @@ func (c *Client) CreatePuzzle(ctx context.Context, puzzle RawPuzzle) (Puzzle, in
 		DiscordChannel: puzzle.DiscordChannel,
 		Meta:           puzzle.Meta,
 		VoiceRoom:      puzzle.VoiceRoom,
+		Reminder:       puzzle.Reminder,
+		Priority:       puzzle.Priority,
+		NeedsHelp:      puzzle.NeedsHelp,
 	})
 	if err != nil {
-		return Puzzle{}, 0, xerrors.Errorf("CreatePuzzle: %w", err)
+		return PuzzleChange{}, xerrors.Errorf("CreatePuzzle: %w", err)
+	}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if err := ValidateTag(tag); err != nil {
+			return PuzzleChange{}, err
+		}
+		_, err := c.queries(ctx).CreatePuzzleTag(ctx, db.CreatePuzzleTagParams{
+			Puzzle: id, Tag: tag,
+		})
+		if err != nil {
+			return PuzzleChange{}, xerrors.Errorf("CreatePuzzleTag: %w", err)
+		}
 	}
 	created, err := c.GetPuzzle(ctx, id)
 	if err != nil {
-		return Puzzle{}, 0, err
+		return PuzzleChange{}, err
 	}
-	change, err := c.LogPuzzleChange(ctx, nil, &created, nil)
-	if err != nil {
-		return Puzzle{}, 0, err
+	if err := c.logPuzzleEvent(ctx, id, "created", true); err != nil {
+		return PuzzleChange{}, err
 	}
-	return created, change.ChangeID, nil
+	return c.logPuzzleChange(ctx, PuzzleChange{After: &created, Imported: imported})
 }
 
-func (c *Client) UpdatePuzzle(ctx context.Context, id int64,
+// UpdatePuzzle applies the given mutation to the puzzle. If version is not
+// AnyVersion and the puzzle has been modified since that version was read, the
+// mutation is skipped and a ConflictError is returned.
+func (c *Client) UpdatePuzzle(ctx context.Context, id int64, version int64,
 	mutate func(puzzle *RawPuzzle) error) (Puzzle, int64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	before, err := c.GetPuzzle(ctx, id)
-	if err != nil {
-		return Puzzle{}, 0, err
-	}
-	var raw = before.RawPuzzle()
-	if err := mutate(&raw); err != nil {
-		return Puzzle{}, 0, err
-	} else if err := c.ValidatePuzzle(ctx, raw); err != nil {
-		return Puzzle{}, 0, err
-	} else if raw.ID != id {
-		return Puzzle{}, 0, xerrors.Errorf("mutation must not change puzzle ID")
-	} else if err := c.queries.UpdatePuzzle(ctx, db.UpdatePuzzleParams(raw)); err != nil {
-		return Puzzle{}, 0, xerrors.Errorf("UpdatePuzzle: %w", err)
-	}
-
-	after, err := c.GetPuzzle(ctx, id)
-	if err != nil {
-		return Puzzle{}, 0, err
-	}
-	change, err := c.LogPuzzleChange(ctx, &before, &after, nil)
+	var change PuzzleChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		before, err := c.GetPuzzle(ctx, id)
+		if err != nil {
+			return err
+		} else if version != AnyVersion && version != before.Version {
+			return ConflictError{before.AblyPuzzle()}
+		}
+		change, err = c.updatePuzzle(ctx, before, mutate, nil)
+		return err
+	})
 	if err != nil {
 		return Puzzle{}, 0, err
 	}
-	return after, change.ChangeID, nil
+	return *change.After, change.ChangeID, nil
 }
 
 func (c *Client) UpdatePuzzleByDiscordChannel(ctx context.Context, channel string,
@@ -167,18 +214,42 @@ func (c *Client) UpdatePuzzleByDiscordChannel(ctx context.Context, channel strin
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	before, err := c.GetPuzzleByChannel(ctx, channel)
+	var change PuzzleChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		before, err := c.GetPuzzleByChannel(ctx, channel)
+		if err != nil {
+			return err
+		}
+		change, err = c.updatePuzzle(ctx, before, mutate, make(chan error, 1))
+		return err
+	})
 	if err != nil {
 		return PuzzleChange{}, err
 	}
+	return change, nil
+}
+
+// Applies the mutation to the puzzle and logs the change. Must be called in a
+// transaction.
+func (c *Client) updatePuzzle(ctx context.Context, before Puzzle,
+	mutate func(puzzle *RawPuzzle) error, complete chan error) (PuzzleChange, error) {
+
 	var raw = before.RawPuzzle()
 	if err := mutate(&raw); err != nil {
 		return PuzzleChange{}, err
 	} else if err := c.ValidatePuzzle(ctx, raw); err != nil {
 		return PuzzleChange{}, err
+	} else if err := c.checkPuzzleHunt(ctx, &before, raw); err != nil {
+		return PuzzleChange{}, err
 	} else if raw.ID != before.ID {
 		return PuzzleChange{}, xerrors.Errorf("mutation must not change puzzle ID")
-	} else if err := c.queries.UpdatePuzzle(ctx, db.UpdatePuzzleParams(raw)); err != nil {
+	} else if raw.Version != before.Version {
+		return PuzzleChange{}, xerrors.Errorf("mutation must not change puzzle version")
+	} else if raw.DeletedAt.Valid {
+		return PuzzleChange{}, xerrors.Errorf("mutation must not delete puzzle")
+	}
+	raw.Version += 1
+	if err := c.queries(ctx).UpdatePuzzle(ctx, db.UpdatePuzzleParams(raw)); err != nil {
 		return PuzzleChange{}, xerrors.Errorf("UpdatePuzzle: %w", err)
 	}
 
@@ -186,46 +257,114 @@ func (c *Client) UpdatePuzzleByDiscordChannel(ctx context.Context, channel strin
 	if err != nil {
 		return PuzzleChange{}, err
 	}
-	return c.LogPuzzleChange(ctx, &before, &after, make(chan error, 1))
+	if err := c.logPuzzleHistory(ctx, &before, &after); err != nil {
+		return PuzzleChange{}, err
+	}
+	return c.LogPuzzleChange(ctx, &before, &after, complete)
 }
 
 func (c *Client) ClearPuzzleVoiceRoom(ctx context.Context, room string) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	rows, err := c.queries.GetPuzzlesByVoiceRoom(ctx, room)
-	if err != nil {
-		return xerrors.Errorf("GetPuzzlesByVoiceRoom: %w", err)
-	}
-	err = c.queries.ClearPuzzleVoiceRoom(ctx, room)
-	if err != nil {
-		return xerrors.Errorf("ClearPuzzleVoiceRoom: %w", err)
-	}
-	for _, row := range rows {
-		var before, after = Puzzle(row), Puzzle(row)
-		after.VoiceRoom = ""
-		// Each puzzle gets a different Change ID, that's fine.
-		_, err2 := c.LogPuzzleChange(ctx, &before, &after, nil)
-		if err2 != nil {
-			err = err2
+
+	return c.transaction(ctx, func(ctx context.Context) error {
+		rows, err := c.queries(ctx).GetPuzzlesByVoiceRoom(ctx, room)
+		if err != nil {
+			return xerrors.Errorf("GetPuzzlesByVoiceRoom: %w", err)
 		}
-	}
-	return err
+		err = c.queries(ctx).ClearPuzzleVoiceRoom(ctx, room)
+		if err != nil {
+			return xerrors.Errorf("ClearPuzzleVoiceRoom: %w", err)
+		}
+		for _, row := range rows {
+			var before, after = Puzzle(row), Puzzle(row)
+			after.VoiceRoom = ""
+			after.Version += 1
+			if err := c.logPuzzleHistory(ctx, &before, &after); err != nil {
+				return err
+			}
+			// Each puzzle gets a different Change ID, that's fine.
+			if _, err := c.LogPuzzleChange(ctx, &before, &after, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
+// DeletePuzzle moves the puzzle to the trash. It can be brought back with
+// RestorePuzzle until the trash is purged.
 func (c *Client) DeletePuzzle(ctx context.Context, id int64) (int64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	puzzle, err := c.GetPuzzle(ctx, id)
+
+	var change PuzzleChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		puzzle, err := c.GetPuzzle(ctx, id)
+		if err != nil {
+			return err
+		} else if err := c.checkWritable(ctx, puzzle.Round.Hunt); err != nil {
+			return err
+		}
+		_, err = c.queries(ctx).TrashPuzzle(ctx, db.TrashPuzzleParams{
+			ID:        id,
+			DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("TrashPuzzle: %w", err)
+		}
+		if err := c.logPuzzleEvent(ctx, id, "deleted", true); err != nil {
+			return err
+		}
+		change, err = c.LogPuzzleChange(ctx, &puzzle, nil, nil)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
-	err = c.queries.DeletePuzzle(ctx, id)
-	if err != nil {
-		return 0, xerrors.Errorf("DeletePuzzle: %w", err)
-	}
-	change, err := c.LogPuzzleChange(ctx, &puzzle, nil, nil)
+	return change.ChangeID, nil
+}
+
+// Applies an update to one of the puzzle's associated tables, like its tags or
+// solvers. If the update affects any rows, the change is recorded in the
+// puzzle's history and broadcast.
+func (c *Client) updatePuzzleAssociations(ctx context.Context, id int64,
+	update func(ctx context.Context) (int64, error)) (Puzzle, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var after Puzzle
+	var changeID int64
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		before, err := c.GetPuzzle(ctx, id)
+		if err != nil {
+			return err
+		} else if err := c.checkWritable(ctx, before.Round.Hunt); err != nil {
+			return err
+		}
+		if count, err := update(ctx); err != nil {
+			return err
+		} else if count == 0 {
+			after, changeID = before, c.changeID
+			return nil
+		}
+
+		after, err = c.GetPuzzle(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := c.logPuzzleHistory(ctx, &before, &after); err != nil {
+			return err
+		}
+		change, err := c.LogPuzzleChange(ctx, &before, &after, nil)
+		if err != nil {
+			return err
+		}
+		changeID = change.ChangeID
+		return nil
+	})
 	if err != nil {
-		return 0, err
+		return Puzzle{}, 0, err
 	}
-	return change.ChangeID, nil
+	return after, changeID, nil
 }
@@ -0,0 +1,137 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/emojihunt/emojihunt/state/status"
+)
+
+func newTestHunt(t *testing.T) (context.Context, *Client, Round) {
+	t.Helper()
+	var ctx = WithActor(context.Background(), ActorSync)
+	var c = NewMemory(ctx)
+	round, _, err := c.CreateRound(ctx, Round{
+		Name: "Fruit", Emoji: "🍎", DriveFolder: "folder",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx, c, round
+}
+
+func TestValidatePuzzle(t *testing.T) {
+	ctx, c, round := newTestHunt(t)
+	var valid = RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+	}
+	if _, _, err := c.CreatePuzzle(ctx, valid); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(p *RawPuzzle)
+		field  string
+	}{
+		{"no name", func(p *RawPuzzle) { p.Name = "" }, "name"},
+		{"no round", func(p *RawPuzzle) { p.Round = 0 }, "round"},
+		{"missing round", func(p *RawPuzzle) { p.Round = 999 }, "round"},
+		{"bad status", func(p *RawPuzzle) { p.Status = "Bogus" }, "status"},
+		{"no url", func(p *RawPuzzle) { p.PuzzleURL = "" }, "puzzle_url"},
+		{"bad url", func(p *RawPuzzle) { p.PuzzleURL = "ftp://example.com" }, "puzzle_url"},
+		{"answer but unsolved", func(p *RawPuzzle) { p.Answer = "PIPPIN" }, "status"},
+		{"solved but no answer", func(p *RawPuzzle) { p.Status = status.Solved }, "status"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var puzzle = valid
+			puzzle.Name = "Bananas"
+			tc.mutate(&puzzle)
+			_, _, err := c.CreatePuzzle(ctx, puzzle)
+			var ve ValidationError
+			if !errors.As(err, &ve) || ve.Field != tc.field {
+				t.Errorf("got %v, want ValidationError on %s", err, tc.field)
+			}
+		})
+	}
+}
+
+func TestUpdatePuzzleConflict(t *testing.T) {
+	ctx, c, round := newTestHunt(t)
+	puzzle, _, err := c.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _, err := c.UpdatePuzzle(ctx, puzzle.ID, puzzle.Version,
+		func(p *RawPuzzle) error { p.Note = "first"; return nil })
+	if err != nil {
+		t.Fatal(err)
+	} else if updated.Version != puzzle.Version+1 {
+		t.Errorf("version: got %d, want %d", updated.Version, puzzle.Version+1)
+	}
+
+	// A second writer with the old version loses, and gets the current puzzle
+	_, _, err = c.UpdatePuzzle(ctx, puzzle.ID, puzzle.Version,
+		func(p *RawPuzzle) error { p.Note = "second"; return nil })
+	var ce ConflictError
+	if !errors.As(err, &ce) {
+		t.Fatalf("got %v, want ConflictError", err)
+	} else if current, ok := ce.Current.(AblyPuzzle); !ok || current.Note != "first" {
+		t.Errorf("ConflictError.Current: got %#v", ce.Current)
+	}
+
+	// AnyVersion skips the check
+	if _, _, err := c.UpdatePuzzle(ctx, puzzle.ID, AnyVersion,
+		func(p *RawPuzzle) error { p.Note = "third"; return nil }); err != nil {
+		t.Error(err)
+	}
+	if got, err := c.GetPuzzle(ctx, puzzle.ID); err != nil {
+		t.Fatal(err)
+	} else if got.Note != "third" || got.Version != puzzle.Version+2 {
+		t.Errorf("got note %q, version %d", got.Note, got.Version)
+	}
+}
+
+func TestUpdateRoundConflict(t *testing.T) {
+	ctx, c, round := newTestHunt(t)
+	if _, _, err := c.UpdateRound(ctx, round.ID, round.Version,
+		func(r *Round) error { r.Hue = 10; return nil }); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := c.UpdateRound(ctx, round.ID, round.Version,
+		func(r *Round) error { r.Hue = 20; return nil })
+	if !errors.As(err, new(ConflictError)) {
+		t.Errorf("got %v, want ConflictError", err)
+	}
+}
+
+func TestPuzzleHistoryActor(t *testing.T) {
+	_, c, round := newTestHunt(t)
+	var ctx = context.Background() // no actor
+	puzzle, _, err := c.CreatePuzzle(ctx, RawPuzzle{
+		Name: "Apples", Round: round.ID, PuzzleURL: "https://example.com/apples",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.UpdatePuzzle(WithActor(ctx, "1234"), puzzle.ID, AnyVersion,
+		func(p *RawPuzzle) error { p.Note = "note"; return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := c.ListPuzzleHistory(ctx, puzzle.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actors = make(map[string]string)
+	for _, entry := range history {
+		actors[entry.Field] = entry.Actor
+	}
+	if actors["created"] != string(ActorSync) || actors["note"] != "1234" {
+		t.Errorf("got actors %v, want sync for created and 1234 for note", actors)
+	}
+}
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+// A Reminder is set with /remind, either on a puzzle or for the whole team.
+// (Puzzles also have a single reminder of their own, in Puzzle.Reminder.)
+type Reminder = db.Reminder
+
+// ReminderLeadTimes returns how long before each occurrence the reminder sends
+// a heads-up, longest first.
+func ReminderLeadTimes(reminder Reminder) []time.Duration {
+	var leads []time.Duration
+	for _, item := range strings.Split(reminder.LeadMinutes, ",") {
+		if minutes, err := strconv.ParseInt(item, 10, 64); err == nil {
+			leads = append(leads, time.Duration(minutes)*time.Minute)
+		}
+	}
+	return leads
+}
+
+// FormatLeadTimes is the inverse of ReminderLeadTimes: it encodes lead times
+// for Reminder.LeadMinutes, rounding down to the minute.
+func FormatLeadTimes(leads []time.Duration) string {
+	var items []string
+	for _, lead := range leads {
+		items = append(items, strconv.FormatInt(int64(lead/time.Minute), 10))
+	}
+	return strings.Join(items, ",")
+}
+
+// NextOccurrence returns the first time the reminder goes off at or after t.
+// Reminders that don't recur return their only occurrence, which may be in the
+// past.
+func NextOccurrence(reminder Reminder, t time.Time) time.Time {
+	var every = time.Duration(reminder.EveryMinutes) * time.Minute
+	if every <= 0 || !t.After(reminder.RemindAt) {
+		return reminder.RemindAt
+	}
+	var n = (t.Sub(reminder.RemindAt) + every - 1) / every
+	return reminder.RemindAt.Add(n * every)
+}
+
+// ReminderDue checks whether the reminder needs to be sent for the window
+// (since, now]. If so, it returns the occurrence and the lead time (zero for
+// the occurrence itself). If several are due, e.g. because the worker was
+// down, only the latest is returned.
+func ReminderDue(reminder Reminder, since, now time.Time) (time.Time, time.Duration, bool) {
+	var every = time.Duration(reminder.EveryMinutes) * time.Minute
+	var due bool
+	var at, trigger time.Time
+	var lead time.Duration
+	for _, l := range append([]time.Duration{0}, ReminderLeadTimes(reminder)...) {
+		// Find the latest occurrence whose trigger isn't in the future
+		var occurrence = reminder.RemindAt
+		if every > 0 && now.Add(l).After(reminder.RemindAt) {
+			occurrence = occurrence.Add(now.Add(l).Sub(reminder.RemindAt) / every * every)
+		}
+		var t = occurrence.Add(-l)
+		if t.After(now) || !t.After(since) {
+			continue
+		} else if !due || t.After(trigger) {
+			due, at, trigger, lead = true, occurrence, t, l
+		}
+	}
+	return at, lead, due
+}
+
+// ListReminders returns the current hunt's reminders. Reminders on puzzles in
+// the trash are left out until the puzzle is restored (or purged, which deletes
+// them).
+func (c *Client) ListReminders(ctx context.Context) ([]Reminder, error) {
+	reminders, err := c.queries(ctx).ListReminders(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, xerrors.Errorf("ListReminders: %w", err)
+	}
+	return reminders, nil
+}
+
+// CreateReminder validates and saves a new reminder in the current hunt. The
+// creation timestamp is filled in automatically.
+func (c *Client) CreateReminder(ctx context.Context, reminder Reminder) (Reminder, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	reminder.Message = strings.TrimSpace(reminder.Message)
+	if reminder.Message == "" {
+		return Reminder{}, ValidationError{"message", "is required"}
+	} else if reminder.DiscordChannel == "" {
+		return Reminder{}, ValidationError{"discord_channel", "is required"}
+	} else if reminder.RemindAt.IsZero() {
+		return Reminder{}, ValidationError{"remind_at", "is required"}
+	} else if reminder.EveryMinutes < 0 {
+		return Reminder{}, ValidationError{"every_minutes", "must not be negative"}
+	} else if reminder.EveryMinutes == 0 && reminder.RemindAt.Before(time.Now()) {
+		return Reminder{}, ValidationError{"remind_at", "is in the past"}
+	}
+
+	// Normalize the lead times: longest first, without duplicates
+	var leads []time.Duration
+	for _, item := range strings.Split(reminder.LeadMinutes, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		minutes, err := strconv.ParseInt(item, 10, 64)
+		if err != nil || minutes <= 0 {
+			return Reminder{}, ValidationError{"lead_minutes", "must be a list of positive numbers"}
+		}
+		leads = append(leads, time.Duration(minutes)*time.Minute)
+	}
+	slices.Sort(leads)
+	slices.Reverse(leads)
+	leads = slices.Compact(leads)
+
+	var hunt = c.hunt(ctx)
+	var created Reminder
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkWritable(ctx, hunt); err != nil {
+			return err
+		}
+		if reminder.Puzzle.Valid {
+			puzzle, err := c.GetPuzzle(ctx, reminder.Puzzle.Int64)
+			if err != nil {
+				return err
+			} else if puzzle.Round.Hunt != hunt {
+				return ValidationError{"puzzle", "is in another hunt"}
+			}
+		}
+		var err error
+		created, err = c.queries(ctx).CreateReminder(ctx, db.CreateReminderParams{
+			Hunt:           hunt,
+			Puzzle:         reminder.Puzzle,
+			DiscordChannel: reminder.DiscordChannel,
+			Message:        reminder.Message,
+			// Timestamps are compared as strings in SQL, so always store them in UTC
+			RemindAt:     reminder.RemindAt.UTC(),
+			LeadMinutes:  FormatLeadTimes(leads),
+			EveryMinutes: reminder.EveryMinutes,
+			CreatedBy:    reminder.CreatedBy,
+			CreatedAt:    time.Now().UTC(),
+		})
+		if err != nil {
+			return xerrors.Errorf("CreateReminder: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Reminder{}, err
+	}
+	return created, nil
+}
+
+// CancelReminder deletes a reminder from the current hunt.
+func (c *Client) CancelReminder(ctx context.Context, id int64) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var hunt = c.hunt(ctx)
+	return c.transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkWritable(ctx, hunt); err != nil {
+			return err
+		}
+		count, err := c.queries(ctx).DeleteReminder(ctx, db.DeleteReminderParams{
+			ID: id, Hunt: hunt,
+		})
+		if err != nil {
+			return xerrors.Errorf("DeleteReminder: %w", err)
+		} else if count == 0 {
+			return xerrors.Errorf("DeleteReminder: %w", sql.ErrNoRows)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,105 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var noon = time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)
+
+func clock(hour, minute int) time.Time {
+	return time.Date(2026, 1, 16, hour, minute, 0, 0, time.UTC)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	var once = Reminder{RemindAt: noon}
+	var hourly = Reminder{RemindAt: noon, EveryMinutes: 60}
+	for _, tc := range []struct {
+		name     string
+		reminder Reminder
+		t        time.Time
+		want     time.Time
+	}{
+		{"once, before", once, clock(11, 0), noon},
+		{"once, at", once, noon, noon},
+		{"once, passed", once, clock(13, 0), noon},
+		{"hourly, before first", hourly, clock(9, 30), noon},
+		{"hourly, at first", hourly, noon, noon},
+		{"hourly, just after", hourly, clock(12, 1), clock(13, 0)},
+		{"hourly, at later", hourly, clock(14, 0), clock(14, 0)},
+		{"hourly, next day", hourly, clock(23, 30), noon.Add(12 * time.Hour)},
+	} {
+		if got := NextOccurrence(tc.reminder, tc.t); !got.Equal(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestReminderDue(t *testing.T) {
+	var once = Reminder{RemindAt: noon, LeadMinutes: "10"}
+	var hourly = Reminder{RemindAt: noon, EveryMinutes: 60, LeadMinutes: "15,5"}
+	for _, tc := range []struct {
+		name       string
+		reminder   Reminder
+		since, now time.Time
+		due        bool
+		at         time.Time
+		lead       time.Duration
+	}{
+		{"once, early", once, clock(11, 0), clock(11, 30), false, time.Time{}, 0},
+		{"once, heads-up", once, clock(11, 49), clock(11, 50), true, noon, 10 * time.Minute},
+		{"once, at", once, clock(11, 59), noon, true, noon, 0},
+		{"once, window starts at", once, noon, clock(12, 1), false, time.Time{}, 0},
+		{"once, passed", once, clock(13, 0), clock(13, 1), false, time.Time{}, 0},
+		{"hourly, first heads-up", hourly, clock(11, 44), clock(11, 45), true, noon, 15 * time.Minute},
+		{"hourly, later occurrence", hourly, clock(13, 59), clock(14, 0), true, clock(14, 0), 0},
+		{"hourly, later heads-up", hourly, clock(14, 54), clock(14, 55), true, clock(15, 0), 5 * time.Minute},
+		{"hourly, between", hourly, clock(13, 20), clock(13, 30), false, time.Time{}, 0},
+
+		// If the worker was down, only the latest trigger is sent
+		{"hourly, catch up", hourly, clock(11, 0), clock(13, 50), true, clock(14, 0), 15 * time.Minute},
+	} {
+		at, lead, due := ReminderDue(tc.reminder, tc.since, tc.now)
+		if due != tc.due || !at.Equal(tc.at) || lead != tc.lead {
+			t.Errorf("%s: got %v, %v, %v, want %v, %v, %v",
+				tc.name, at, lead, due, tc.at, tc.lead, tc.due)
+		}
+	}
+}
+
+// Consecutive windows, as the worker uses them, send each heads-up and each
+// occurrence exactly once, however the windows are cut.
+func TestReminderDueExactlyOnce(t *testing.T) {
+	type trigger struct {
+		at   time.Time
+		lead time.Duration
+	}
+	var hourly = Reminder{RemindAt: noon, EveryMinutes: 60, LeadMinutes: "15,5"}
+	var want []trigger
+	for _, at := range []time.Time{clock(12, 0), clock(13, 0), clock(14, 0)} {
+		for _, lead := range []time.Duration{15 * time.Minute, 5 * time.Minute, 0} {
+			want = append(want, trigger{at, lead})
+		}
+	}
+
+	for _, tc := range []struct {
+		name  string
+		start time.Time
+		step  time.Duration
+	}{
+		{"every minute", clock(11, 0), time.Minute},
+		{"every five minutes", clock(11, 0), 5 * time.Minute},
+		{"unaligned", clock(11, 1), 3 * time.Minute},
+	} {
+		var got []trigger
+		for since := tc.start; since.Before(clock(14, 30)); since = since.Add(tc.step) {
+			if at, lead, due := ReminderDue(hourly, since, since.Add(tc.step)); due {
+				got = append(got, trigger{at, lead})
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, want)
+		}
+	}
+}
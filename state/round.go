@@ -2,6 +2,9 @@ package state
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/emojihunt/emojihunt/state/db"
 	"github.com/rivo/uniseg"
@@ -29,8 +32,31 @@ func ValidateRound(r Round) error {
 	return nil
 }
 
+// ValidateRoundUnique checks that no other round in the hunt has the same name
+// or emoji. Rounds in the trash don't count.
+func (c *Client) ValidateRoundUnique(ctx context.Context, r Round) error {
+	var hunt = r.Hunt
+	if hunt == 0 {
+		hunt = c.hunt(ctx)
+	}
+	rounds, err := c.queries(ctx).ListRounds(ctx, hunt)
+	if err != nil {
+		return xerrors.Errorf("ListRounds: %w", err)
+	}
+	for _, other := range rounds {
+		if other.ID == r.ID {
+			continue
+		} else if other.Name == r.Name {
+			return ValidationError{"name", "is already used by another round"}
+		} else if other.Emoji == r.Emoji {
+			return ValidationError{"emoji", fmt.Sprintf("is already used by %s", other.Name)}
+		}
+	}
+	return nil
+}
+
 func (c *Client) GetRound(ctx context.Context, id int64) (Round, error) {
-	round, err := c.queries.GetRound(ctx, id)
+	round, err := c.queries(ctx).GetRound(ctx, id)
 	if err != nil {
 		return Round{}, xerrors.Errorf("GetRound: %w", err)
 	}
@@ -40,7 +66,7 @@ func (c *Client) GetRound(ctx context.Context, id int64) (Round, error) {
 func (c *Client) ListRounds(ctx context.Context) ([]Round, error) {
 	// Used by sync! To avoid deadlocks, this function must not acquire the global
 	// database lock.
-	results, err := c.queries.ListRounds(ctx)
+	results, err := c.queries(ctx).ListRounds(ctx, c.hunt(ctx))
 	if err != nil {
 		return nil, xerrors.Errorf("ListRounds: %w", err)
 	}
@@ -52,10 +78,35 @@ func (c *Client) ListRounds(ctx context.Context) ([]Round, error) {
 }
 
 func (c *Client) CreateRound(ctx context.Context, round Round) (Round, int64, error) {
-	if err := ValidateRound(round); err != nil {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var change RoundChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		var err error
+		change, err = c.createRound(ctx, round)
+		return err
+	})
+	if err != nil {
 		return Round{}, 0, err
 	}
-	result, err := c.queries.CreateRound(ctx, db.CreateRoundParams{
+	return *change.After, change.ChangeID, nil
+}
+
+// Must hold the global lock and be called in a transaction.
+func (c *Client) createRound(ctx context.Context, round Round) (RoundChange, error) {
+	if err := ValidateRound(round); err != nil {
+		return RoundChange{}, err
+	}
+	if round.Hunt == 0 {
+		round.Hunt = c.hunt(ctx)
+	}
+	if err := c.checkWritable(ctx, round.Hunt); err != nil {
+		return RoundChange{}, err
+	} else if err := c.ValidateRoundUnique(ctx, round); err != nil {
+		return RoundChange{}, err
+	}
+	result, err := c.queries(ctx).CreateRound(ctx, db.CreateRoundParams{
 		Name:            round.Name,
 		Emoji:           round.Emoji,
 		Hue:             round.Hue,
@@ -63,69 +114,112 @@ func (c *Client) CreateRound(ctx context.Context, round Round) (Round, int64, er
 		Special:         round.Special,
 		DriveFolder:     round.DriveFolder,
 		DiscordCategory: round.DiscordCategory,
+		Hunt:            round.Hunt,
 	})
 	if err != nil {
-		return Round{}, 0, xerrors.Errorf("CreateRound: %w", err)
-	}
-	created := Round(result)
-	change, err := c.LogRoundChange(ctx, nil, &created)
-	if err != nil {
-		return Round{}, 0, err
+		return RoundChange{}, xerrors.Errorf("CreateRound: %w", err)
 	}
-	return created, change.ChangeID, nil
+	var created = Round(result)
+	return c.LogRoundChange(ctx, nil, &created)
 }
 
-func (c *Client) UpdateRound(ctx context.Context, id int64,
+// UpdateRound applies the given mutation to the round. If version is not
+// AnyVersion and the round has been modified since that version was read, the
+// mutation is skipped and a ConflictError is returned.
+func (c *Client) UpdateRound(ctx context.Context, id int64, version int64,
 	mutate func(round *Round) error) (Round, int64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	before, err := c.GetRound(ctx, id)
-	if err != nil {
-		return Round{}, 0, err
-	}
-	var raw = before
-	if err := mutate(&raw); err != nil {
-		return Round{}, 0, err
-	} else if err := ValidateRound(raw); err != nil {
-		return Round{}, 0, err
-	} else if raw.ID != id {
-		return Round{}, 0, xerrors.Errorf("mutation must not change round ID")
-	} else if err := c.queries.UpdateRound(ctx, db.UpdateRoundParams(raw)); err != nil {
-		return Round{}, 0, xerrors.Errorf("UpdateRound: %w", err)
-	}
-	after, err := c.GetRound(ctx, id)
-	if err != nil {
-		return Round{}, 0, err
-	}
 
-	change, err := c.LogRoundChange(ctx, &before, &after)
+	var after Round
+	var change RoundChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		before, err := c.GetRound(ctx, id)
+		if err != nil {
+			return err
+		} else if version != AnyVersion && version != before.Version {
+			return ConflictError{before}
+		} else if err := c.checkWritable(ctx, before.Hunt); err != nil {
+			return err
+		}
+		var raw = before
+		if err := mutate(&raw); err != nil {
+			return err
+		} else if err := ValidateRound(raw); err != nil {
+			return err
+		} else if raw.ID != id {
+			return xerrors.Errorf("mutation must not change round ID")
+		} else if raw.Version != before.Version {
+			return xerrors.Errorf("mutation must not change round version")
+		} else if raw.DeletedAt.Valid {
+			return xerrors.Errorf("mutation must not delete round")
+		} else if raw.Hunt != before.Hunt {
+			return xerrors.Errorf("mutation must not change round hunt")
+		} else if err := c.ValidateRoundUnique(ctx, raw); err != nil {
+			return err
+		}
+		raw.Version += 1
+		if err := c.queries(ctx).UpdateRound(ctx, db.UpdateRoundParams(raw)); err != nil {
+			return xerrors.Errorf("UpdateRound: %w", err)
+		}
+		after, err = c.GetRound(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		change, err = c.LogRoundChange(ctx, &before, &after)
+		if err != nil {
+			return err
+		} else if !c.publishes(after.Hunt) {
+			return nil
+		}
+		puzzles, err := c.queries(ctx).ListPuzzlesByRound(ctx, id)
+		if err != nil {
+			return xerrors.Errorf("ListPuzzlesByRound: %w", err)
+		}
+		for _, puzzle := range puzzles {
+			var pre, post = Puzzle(puzzle), Puzzle(puzzle)
+			pre.Round = before
+			c.notify(ctx, PuzzleChange{&pre, &post, change.Actor, 0, nil, false, false})
+		}
+		return nil
+	})
 	if err != nil {
 		return Round{}, 0, err
 	}
-	puzzles, err := c.queries.ListPuzzlesByRound(ctx, id)
-	if err != nil {
-		return Round{}, 0, xerrors.Errorf("ListPuzzlesByRound: %w", err)
-	}
-	for _, puzzle := range puzzles {
-		var pre, post = Puzzle(puzzle), Puzzle(puzzle)
-		pre.Round = before
-		c.PuzzleRoundChange <- PuzzleChange{&pre, &post, 0, nil}
-	}
 	return after, change.ChangeID, nil
 }
 
+// DeleteRound moves the round to the trash. Rounds can only be deleted once
+// they're empty.
 func (c *Client) DeleteRound(ctx context.Context, id int64) (int64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	round, err := c.GetRound(ctx, id)
-	if err != nil {
-		return 0, err
-	}
-	err = c.queries.DeleteRound(ctx, id)
-	if err != nil {
-		return 0, xerrors.Errorf("DeleteRound: %w", err)
-	}
-	change, err := c.LogRoundChange(ctx, &round, nil)
+
+	var change RoundChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		round, err := c.GetRound(ctx, id)
+		if err != nil {
+			return err
+		} else if err := c.checkWritable(ctx, round.Hunt); err != nil {
+			return err
+		}
+		count, err := c.queries(ctx).CountPuzzlesInRound(ctx, id)
+		if err != nil {
+			return xerrors.Errorf("CountPuzzlesInRound: %w", err)
+		} else if count > 0 {
+			return ValidationError{"round", "still has puzzles"}
+		}
+		_, err = c.queries(ctx).TrashRound(ctx, db.TrashRoundParams{
+			ID:        id,
+			DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("TrashRound: %w", err)
+		}
+		change, err = c.LogRoundChange(ctx, &round, nil)
+		return err
+	})
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+type SearchResult = db.SearchPuzzlesRow
+
+// Search returns the puzzles that best match the query, searching the puzzle
+// name, note, answer and round name, as well as messages sent in the puzzle
+// channel. Results are ranked from best to worst.
+func (c *Client) Search(ctx context.Context, query string, limit int64) ([]SearchResult, error) {
+	var match = searchExpression(query)
+	if match == "" {
+		return nil, nil
+	}
+	results, err := c.queries(ctx).SearchPuzzles(ctx, db.SearchPuzzlesParams{
+		Query: match, Hunt: c.hunt(ctx), MaxResults: limit,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("SearchPuzzles: %w", err)
+	}
+	return results, nil
+}
+
+// Converts user input into an FTS5 query. Each word is quoted, so punctuation
+// can't be interpreted as query syntax, and matched as a prefix, so "knot"
+// matches "knotted".
+func searchExpression(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.ReplaceAll(word, `"`, "")
+		if word != "" {
+			terms = append(terms, `"`+word+`"*`)
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// IndexMessage adds or updates a Discord message in the search index.
+func (c *Client) IndexMessage(ctx context.Context, id, channel, content string) error {
+	rowid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return xerrors.Errorf("invalid message ID %q: %w", id, err)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if content == "" {
+		err = c.queries(ctx).DeleteSearchMessage(ctx, rowid)
+	} else {
+		err = c.queries(ctx).CreateSearchMessage(ctx, db.CreateSearchMessageParams{
+			Rowid: rowid, Content: content, Channel: channel,
+		})
+	}
+	if err != nil {
+		return xerrors.Errorf("IndexMessage: %w", err)
+	}
+	return nil
+}
+
+// UnindexMessage removes a deleted Discord message from the search index.
+func (c *Client) UnindexMessage(ctx context.Context, id string) error {
+	rowid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return xerrors.Errorf("invalid message ID %q: %w", id, err)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.queries(ctx).DeleteSearchMessage(ctx, rowid); err != nil {
+		return xerrors.Errorf("DeleteSearchMessage: %w", err)
+	}
+	return nil
+}
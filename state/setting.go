@@ -1,34 +1,180 @@
 package state
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"reflect"
+	"strings"
 	"time"
 
+	"github.com/andybalholm/cascadia"
+	"github.com/emojihunt/emojihunt/jsonpath"
 	"github.com/emojihunt/emojihunt/state/db"
 	"golang.org/x/xerrors"
 )
 
-const (
-	discoveryConfigSetting = "discovery_config"
-	enabledSetting         = "discovery_enabled"
-	reminderSetting        = "reminder_timestamp"
+// Settings are stored as JSON in the settings table, one row per hunt and key.
+// Every setting is declared below, along with its type, default value and
+// validation, and the declarations drive the settings API.
+var (
+	HuntInfoSetting = register(&Setting[HuntInfo]{
+		Key:         "hunt_info",
+		Description: "Information about the hunt, shown to the team",
+		Validate:    validateHuntInfo,
+	})
+	DiscoveryConfigSetting = register(&Setting[DiscoveryConfig]{
+		Key:         "discovery_config",
+		Description: "Where and how to scrape the hunt website for new puzzles",
+		Validate:    validateDiscoveryConfig,
+		Discovery:   true,
+	})
+	DiscoveryEnabledSetting = register(&Setting[bool]{
+		Key:         "discovery_enabled",
+		Description: "Kill switch for puzzle discovery",
+		Default:     true,
+		Discovery:   true,
+	})
+	SolverIdleSetting = register(&Setting[int64]{
+		Key: "solver_idle_minutes",
+		Description: "How long someone can go without posting in a puzzle's " +
+			"channel or editing its sheet before their claim on the puzzle " +
+			"expires, in minutes (0 means never)",
+		Default:  90,
+		Validate: validateSolverIdle,
+	})
+	ReminderTimestampSetting = register(&Setting[time.Time]{
+		Key:         "reminder_timestamp",
+		Description: "When the reminder bot last sent reminders",
+		Internal:    true,
+	})
 )
 
-func (c *Client) IsEnabled(ctx context.Context) bool {
-	data, err := c.readSetting(ctx, enabledSetting)
-	if err != nil {
-		panic(err)
+// HuntInfo is the hunt metadata shown to the team. (The hunt's name lives on
+// the Hunt itself.)
+type HuntInfo struct {
+	HuntURL         string `json:"hunt_url"`
+	HuntCredentials string `json:"hunt_credentials"`
+	LogisticsURL    string `json:"logistics_url"`
+}
+
+// A Setting is a typed, per-hunt value. If the setting has never been written,
+// its Default is used.
+type Setting[T any] struct {
+	Key         string
+	Description string
+	Default     T
+
+	// Optional: checks a new value before it's written
+	Validate func(value T) error
+
+	// Internal settings are managed by the bots, and aren't exposed through the
+	// settings API.
+	Internal bool
+
+	// Changing a discovery setting restarts puzzle discovery
+	Discovery bool
+}
+
+// SettingInfo describes a setting and its current value, for the settings API.
+type SettingInfo struct {
+	Key         string         `json:"key"`
+	Description string         `json:"description"`
+	Schema      map[string]any `json:"schema"`
+	Default     any            `json:"default"`
+	Value       any            `json:"value"`
+}
+
+type setting interface {
+	describe() SettingInfo
+	isInternal() bool
+	isDiscovery() bool
+	decode(data []byte) (any, error)
+	parse(data []byte) (any, error)
+}
+
+var registry = make(map[string]setting)
+var registryOrder []string
+
+func register[T any](s *Setting[T]) *Setting[T] {
+	if _, ok := registry[s.Key]; ok {
+		panic("duplicate setting: " + s.Key)
 	}
-	var enabled bool = true // default
+	registry[s.Key] = s
+	registryOrder = append(registryOrder, s.Key)
+	return s
+}
+
+func (s *Setting[T]) describe() SettingInfo {
+	var schema = jsonSchema(reflect.TypeFor[T]())
+	schema["description"] = s.Description
+	return SettingInfo{
+		Key:         s.Key,
+		Description: s.Description,
+		Schema:      schema,
+		Default:     s.Default,
+	}
+}
+
+func (s *Setting[T]) isInternal() bool {
+	return s.Internal
+}
+
+func (s *Setting[T]) isDiscovery() bool {
+	return s.Discovery
+}
+
+// Decodes a stored value, falling back to the default if there isn't one.
+func (s *Setting[T]) decode(data []byte) (any, error) {
+	var value = s.Default
 	if len(data) > 0 {
-		err = json.Unmarshal(data, &enabled)
-		if err != nil {
-			panic(xerrors.Errorf("IsEnabled unmarshal: %w", err))
+		if err := json.Unmarshal(data, &value); err != nil {
+			return value, xerrors.Errorf("%s unmarshal: %w", s.Key, err)
 		}
 	}
+	return value, nil
+}
+
+// Parses and validates a new value from the settings API. Unknown fields are
+// rejected, since they're probably typos.
+func (s *Setting[T]) parse(data []byte) (any, error) {
+	var value T
+	var decoder = json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, ValidationError{s.Key, "is invalid: " + err.Error()}
+	}
+	if s.Validate != nil {
+		if err := s.Validate(value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func getSetting[T any](ctx context.Context, c *Client, s *Setting[T]) (T, error) {
+	data, err := c.readSetting(ctx, s.Key)
+	if err != nil {
+		return s.Default, err
+	}
+	value, err := s.decode(data)
+	return value.(T), err
+}
+
+func (c *Client) HuntInfo(ctx context.Context) (HuntInfo, error) {
+	return getSetting(ctx, c, HuntInfoSetting)
+}
+
+func (c *Client) IsEnabled(ctx context.Context) bool {
+	enabled, err := getSetting(ctx, c, DiscoveryEnabledSetting)
+	if err != nil {
+		panic(err)
+	}
 	return enabled
 }
 
@@ -39,7 +185,7 @@ func (c *Client) EnableDiscovery(ctx context.Context, enabled bool) bool {
 	if c.IsEnabled(ctx) == enabled {
 		return false
 	}
-	if err := c.writeSetting(ctx, enabledSetting, enabled); err != nil {
+	if err := c.writeSetting(ctx, DiscoveryEnabledSetting.Key, enabled); err != nil {
 		panic(err)
 	}
 	c.DiscoveryChange <- true
@@ -47,28 +193,252 @@ func (c *Client) EnableDiscovery(ctx context.Context, enabled bool) bool {
 }
 
 func (c *Client) ReminderTimestamp(ctx context.Context) (time.Time, error) {
-	data, err := c.readSetting(ctx, reminderSetting)
+	return getSetting(ctx, c, ReminderTimestampSetting)
+}
+
+func (c *Client) SetReminderTimestamp(ctx context.Context, reminder time.Time) error {
+	// Concurrency rule: this setting is only written from the reminder bot's
+	// worker goroutine.
+	return c.writeSetting(ctx, ReminderTimestampSetting.Key, reminder)
+}
+
+// ListSettings returns the settings exposed through the settings API, along
+// with their current values.
+func (c *Client) ListSettings(ctx context.Context) ([]SettingInfo, error) {
+	var results []SettingInfo
+	for _, key := range registryOrder {
+		var s = registry[key]
+		if s.isInternal() {
+			continue
+		}
+		data, err := c.readSetting(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		var info = s.describe()
+		if info.Value, err = s.decode(data); err != nil {
+			return nil, err
+		}
+		results = append(results, info)
+	}
+	return results, nil
+}
+
+// UpdateSettings validates and writes the given settings, which are keyed by
+// setting key and encoded as JSON. Values replace the existing value entirely.
+// If any value is invalid, nothing is written.
+func (c *Client) UpdateSettings(ctx context.Context, values map[string]json.RawMessage) ([]SettingInfo, error) {
+	err := func() error {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if err := c.checkWritable(ctx, c.hunt(ctx)); err != nil {
+			return err
+		}
+		var parsed = make(map[string]any)
+		for key, data := range values {
+			s, ok := registry[key]
+			if !ok || s.isInternal() {
+				return ValidationError{key, "is not a setting"}
+			}
+			value, err := s.parse(data)
+			if err != nil {
+				return err
+			}
+			parsed[key] = value
+		}
+		return c.transaction(ctx, func(ctx context.Context) error {
+			for key, value := range parsed {
+				if err := c.writeSetting(ctx, key, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}()
 	if err != nil {
-		return time.Time{}, err
+		return nil, err
 	}
-	var reminder time.Time
-	if len(data) > 0 {
-		err = json.Unmarshal(data, &reminder)
-		if err != nil {
-			return time.Time{}, xerrors.Errorf("ReminderTimestamp unmarshal: %w", err)
+
+	// Discovery and the syncer only run on the active hunt
+	if !c.IsActive(ctx) {
+		return c.ListSettings(ctx)
+	}
+
+	// Restarting discovery also pushes the new settings out to clients (see
+	// syncer.TriggerDiscovery), so only one signal is needed.
+	var discovery bool
+	for key := range values {
+		discovery = discovery || registry[key].isDiscovery()
+	}
+	if discovery {
+		c.DiscoveryChange <- true
+	} else if len(values) > 0 {
+		c.SettingsChange <- true
+	}
+	return c.ListSettings(ctx)
+}
+
+func validateHuntInfo(info HuntInfo) error {
+	if err := validateURL(info.HuntURL, "http", "https"); err != nil {
+		return ValidationError{"hunt_url", err.Error()}
+	} else if err := validateURL(info.LogisticsURL, "http", "https"); err != nil {
+		return ValidationError{"logistics_url", err.Error()}
+	}
+	return nil
+}
+
+func validateSolverIdle(minutes int64) error {
+	if minutes < 0 {
+		return ValidationError{"solver_idle_minutes", "must not be negative"}
+	}
+	return nil
+}
+
+func validateDiscoveryConfig(config DiscoveryConfig) error {
+	if config.PuzzlesURL == "" {
+		return nil // discovery is disabled
+	} else if err := validateURL(config.PuzzlesURL, "http", "https"); err != nil {
+		return ValidationError{"puzzles_url", err.Error()}
+	} else if err := validateURL(config.WebsocketURL, "ws", "wss"); err != nil {
+		return ValidationError{"websocket_url", err.Error()}
+	} else if err := validateScraperConfig(config.ScraperConfig); err != nil {
+		return err
+	}
+	for i, source := range config.Sources {
+		var prefix = fmt.Sprintf("sources[%d].", i)
+		if source.URL == "" {
+			return ValidationError{prefix + "url", "is required"}
+		} else if err := validateURL(source.URL, "http", "https"); err != nil {
+			return ValidationError{prefix + "url", err.Error()}
+		} else if source.ScraperConfig == (ScraperConfig{}) {
+			continue // inherited from the puzzle list
+		} else if err := validateScraperConfig(source.ScraperConfig); err != nil {
+			var ve ValidationError
+			if errors.As(err, &ve) {
+				ve.Field = prefix + ve.Field
+				return ve
+			}
+			return err
 		}
 	}
-	return reminder, nil
+	return nil
 }
 
-func (c *Client) SetReminderTimestamp(ctx context.Context, reminder time.Time) error {
-	// Concurrency rule: this setting is only written from the reminder bot's
-	// worker goroutine.
-	return c.writeSetting(ctx, reminderSetting, reminder)
+func validateScraperConfig(config ScraperConfig) error {
+	switch config.Scraper {
+	case "", ScraperHTML:
+		var selectors = []struct {
+			field, selector string
+			required        bool
+		}{
+			{"group_selector", config.GroupSelector, true},
+			{"round_name_selector", config.RoundNameSelector, true},
+			{"puzzle_list_selector", config.PuzzleListSelector, true},
+			{"puzzle_item_selector", config.PuzzleItemSelector, false},
+		}
+		for _, item := range selectors {
+			if item.selector == "" && !item.required {
+				continue
+			} else if item.selector == "" {
+				return ValidationError{item.field, "is required"}
+			} else if _, err := cascadia.Compile(item.selector); err != nil {
+				return ValidationError{item.field, err.Error()}
+			}
+		}
+	case ScraperJSON:
+		var paths = []struct {
+			field, path string
+			required    bool
+		}{
+			{"rounds_path", config.RoundsPath, false},
+			{"round_name_path", config.RoundNamePath, true},
+			{"puzzles_path", config.PuzzlesPath, true},
+			{"puzzle_name_path", config.PuzzleNamePath, true},
+			{"puzzle_url_path", config.PuzzleURLPath, true},
+		}
+		for _, item := range paths {
+			if item.path == "" && !item.required {
+				continue
+			} else if item.path == "" {
+				return ValidationError{item.field, "is required"}
+			} else if _, err := jsonpath.Compile(item.path); err != nil {
+				return ValidationError{item.field, err.Error()}
+			}
+		}
+	default:
+		return ValidationError{"scraper", `must be "html" or "json"`}
+	}
+	return nil
+}
+
+// Checks that the string is blank or is a URL with one of the given schemes.
+func validateURL(raw string, schemes ...string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return xerrors.New("is not a valid URL")
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return xerrors.Errorf("must be a %s URL", strings.Join(schemes, " or "))
+}
+
+// Builds a JSON Schema for the given type, using the same field names as
+// encoding/json.
+func jsonSchema(t reflect.Type) map[string]any {
+	switch {
+	case t == reflect.TypeFor[time.Time]():
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case t.Kind() == reflect.String:
+		return map[string]any{"type": "string"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]any{"type": "number"}
+	case t.Kind() == reflect.Slice:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case t.Kind() == reflect.Pointer:
+		return jsonSchema(t.Elem())
+	case t.Kind() == reflect.Struct:
+		var properties = make(map[string]any)
+		for i := range t.NumField() {
+			var field = t.Field(i)
+			var name, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			} else if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+				// Embedded structs are flattened
+				maps.Copy(properties, jsonSchema(field.Type)["properties"].(map[string]any))
+				continue
+			} else if name == "" {
+				name = field.Name
+			}
+			properties[name] = jsonSchema(field.Type)
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	default:
+		return map[string]any{}
+	}
 }
 
 func (c *Client) readSetting(ctx context.Context, key string) ([]byte, error) {
-	data, err := c.queries.GetSetting(ctx, key)
+	data, err := c.queries(ctx).GetSetting(ctx, db.GetSettingParams{
+		Hunt: c.hunt(ctx), Key: key,
+	})
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	} else if err != nil {
@@ -82,8 +452,8 @@ func (c *Client) writeSetting(ctx context.Context, key string, value interface{}
 	if err != nil {
 		return xerrors.Errorf("setting marshal: %w", err)
 	}
-	err = c.queries.UpdateSetting(ctx, db.UpdateSettingParams{
-		Key: key, Value: data,
+	err = c.queries(ctx).UpdateSetting(ctx, db.UpdateSettingParams{
+		Hunt: c.hunt(ctx), Key: key, Value: data,
 	})
 	if err != nil {
 		return xerrors.Errorf("UpdateSetting: %w", err)
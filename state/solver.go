@@ -0,0 +1,125 @@
+package state
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+// A PuzzleSolver is a claim by a Discord user to be working on a puzzle.
+type PuzzleSolver = db.PuzzleSolver
+
+func (p Puzzle) SolverList() []string {
+	if p.Solvers == "" {
+		return []string{}
+	}
+	return strings.Split(p.Solvers, ",")
+}
+
+func (p Puzzle) HasSolver(user string) bool {
+	for _, s := range p.SolverList() {
+		if s == user {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) ListPuzzleSolvers(ctx context.Context, id int64) ([]PuzzleSolver, error) {
+	solvers, err := c.queries(ctx).ListPuzzleSolvers(ctx, id)
+	if err != nil {
+		return nil, xerrors.Errorf("ListPuzzleSolvers: %w", err)
+	}
+	return solvers, nil
+}
+
+// JoinPuzzle records that the user is working on the puzzle. Joining a puzzle
+// you've already joined is a no-op, and doesn't generate a change.
+func (c *Client) JoinPuzzle(ctx context.Context, id int64, user string) (Puzzle, int64, error) {
+	if user == "" {
+		return Puzzle{}, 0, ValidationError{"solver", "is required"}
+	}
+	return c.updatePuzzleAssociations(ctx, id, func(ctx context.Context) (int64, error) {
+		// Timestamps are compared as strings in SQL, so always store them in UTC
+		var now = time.Now().UTC()
+		count, err := c.queries(ctx).CreatePuzzleSolver(ctx, db.CreatePuzzleSolverParams{
+			Puzzle: id, Solver: user, JoinedAt: now, ActiveAt: now,
+		})
+		if err != nil {
+			return 0, xerrors.Errorf("CreatePuzzleSolver: %w", err)
+		}
+		return count, nil
+	})
+}
+
+// LeavePuzzle removes the user's claim on the puzzle. Leaving a puzzle you
+// haven't joined is a no-op, and doesn't generate a change.
+func (c *Client) LeavePuzzle(ctx context.Context, id int64, user string) (Puzzle, int64, error) {
+	return c.updatePuzzleAssociations(ctx, id, func(ctx context.Context) (int64, error) {
+		count, err := c.queries(ctx).DeletePuzzleSolver(ctx, db.DeletePuzzleSolverParams{
+			Puzzle: id, Solver: user,
+		})
+		if err != nil {
+			return 0, xerrors.Errorf("DeletePuzzleSolver: %w", err)
+		}
+		return count, nil
+	})
+}
+
+// TouchPuzzleSolver records activity by the user in a Discord channel, which
+// keeps their claim on the channel's puzzle (if any) from expiring.
+func (c *Client) TouchPuzzleSolver(ctx context.Context, channel, user string, at time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err := c.queries(ctx).TouchPuzzleSolver(ctx, db.TouchPuzzleSolverParams{
+		ActiveAt: at.UTC(), Solver: user, DiscordChannel: channel,
+	})
+	if err != nil {
+		return xerrors.Errorf("TouchPuzzleSolver: %w", err)
+	}
+	return nil
+}
+
+// TouchPuzzleSolversBySpreadsheet records an edit to a puzzle's spreadsheet.
+// Google Drive doesn't tell us which Discord user made the edit, so it keeps
+// all of the puzzle's claims from expiring.
+func (c *Client) TouchPuzzleSolversBySpreadsheet(ctx context.Context, spreadsheet string, at time.Time) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	err := c.queries(ctx).TouchPuzzleSolversBySpreadsheet(ctx,
+		db.TouchPuzzleSolversBySpreadsheetParams{ActiveAt: at.UTC(), SpreadsheetID: spreadsheet},
+	)
+	if err != nil {
+		return xerrors.Errorf("TouchPuzzleSolversBySpreadsheet: %w", err)
+	}
+	return nil
+}
+
+// ExpirePuzzleSolvers removes the claims that have been idle for longer than
+// the solver_idle_minutes setting, and returns them.
+func (c *Client) ExpirePuzzleSolvers(ctx context.Context) ([]PuzzleSolver, error) {
+	idle, err := getSetting(ctx, c, SolverIdleSetting)
+	if err != nil {
+		return nil, err
+	} else if idle <= 0 {
+		return nil, nil // claims never expire
+	}
+	solvers, err := c.queries(ctx).ListIdlePuzzleSolvers(ctx, db.ListIdlePuzzleSolversParams{
+		ActiveBefore: time.Now().UTC().Add(-time.Duration(idle) * time.Minute),
+		Hunt:         c.hunt(ctx),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("ListIdlePuzzleSolvers: %w", err)
+	}
+	for _, solver := range solvers {
+		if _, _, err := c.LeavePuzzle(ctx, solver.Puzzle, solver.Solver); err != nil {
+			return nil, err
+		}
+	}
+	return solvers, nil
+}
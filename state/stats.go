@@ -0,0 +1,243 @@
+package state
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/status"
+	"golang.org/x/xerrors"
+)
+
+const longestOpenLimit = 10
+
+// PuzzleTimes records when a puzzle reached each milestone, according to the
+// puzzle history. Times are nil if the milestone hasn't been reached (or if it
+// predates the history).
+type PuzzleTimes struct {
+	Puzzle int64 `json:"puzzle"`
+
+	// When the puzzle was created. Puzzles created before creation was recorded
+	// in the history fall back to their earliest history entry.
+	UnlockedAt *time.Time `json:"unlocked_at"`
+
+	// When the puzzle was first marked Working.
+	WorkingAt *time.Time `json:"working_at"`
+
+	// When the puzzle was last marked solved. Cleared if the puzzle was later
+	// marked unsolved.
+	SolvedAt *time.Time `json:"solved_at"`
+}
+
+// Builds each puzzle's PuzzleTimes from the history, which must be in
+// chronological order.
+func puzzleTimes(history []PuzzleHistory) map[int64]*PuzzleTimes {
+	var times = make(map[int64]*PuzzleTimes)
+	for _, entry := range history {
+		var t, ok = times[entry.Puzzle]
+		if !ok {
+			var changed = entry.ChangedAt
+			t = &PuzzleTimes{Puzzle: entry.Puzzle, UnlockedAt: &changed}
+			times[entry.Puzzle] = t
+		}
+		var changed = entry.ChangedAt
+		switch entry.Field {
+		case "created":
+			t.UnlockedAt = &changed
+		case "status":
+			if status.Status(entry.NewValue) == status.Working && t.WorkingAt == nil {
+				t.WorkingAt = &changed
+			}
+			if status.Status(entry.NewValue).IsSolved() {
+				t.SolvedAt = &changed
+			} else {
+				t.SolvedAt = nil
+			}
+		}
+	}
+	return times
+}
+
+type Stats struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Unlocked    int       `json:"unlocked"`
+	Solved      int       `json:"solved"`
+	MetasTotal  int       `json:"metas_total"`
+	MetasSolved int       `json:"metas_solved"`
+
+	// Solves in the hour up to GeneratedAt. (The last SolvesPerHour bucket only
+	// covers the current clock hour.)
+	SolvesLastHour int `json:"solves_last_hour"`
+
+	SolvesPerHour []HourlySolves `json:"solves_per_hour"`
+	Rounds        []RoundStats   `json:"rounds"`
+	LongestOpen   []OpenPuzzle   `json:"longest_open"`
+	Burndown      []BurndownStep `json:"burndown"`
+	Puzzles       []PuzzleTimes  `json:"puzzles"`
+}
+
+type HourlySolves struct {
+	Hour   time.Time `json:"hour"`
+	Solves int       `json:"solves"`
+}
+
+type RoundStats struct {
+	Round    int64  `json:"round"`
+	Name     string `json:"name"`
+	Emoji    string `json:"emoji"`
+	Unlocked int    `json:"unlocked"`
+	Solved   int    `json:"solved"`
+
+	// The median time from unlock to solve, in seconds. Zero if no puzzles in the
+	// round have been solved (with a known unlock time).
+	MedianSolveSeconds int64 `json:"median_solve_seconds"`
+}
+
+type OpenPuzzle struct {
+	Puzzle     int64     `json:"puzzle"`
+	Name       string    `json:"name"`
+	Round      int64     `json:"round"`
+	UnlockedAt time.Time `json:"unlocked_at"`
+}
+
+// BurndownStep is the number of puzzles unlocked and solved as of Time. There's
+// one step for each unlock and solve.
+type BurndownStep struct {
+	Time     time.Time `json:"time"`
+	Unlocked int       `json:"unlocked"`
+	Solved   int       `json:"solved"`
+}
+
+// Stats computes solve statistics for the hunt from the puzzle history.
+func (c *Client) Stats(ctx context.Context) (Stats, error) {
+	puzzles, err := c.ListPuzzles(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	rounds, err := c.ListRounds(ctx)
+	if err != nil {
+		return Stats{}, err
+	}
+	history, err := c.queries(ctx).ListAllPuzzleHistory(ctx, c.hunt(ctx))
+	if err != nil {
+		return Stats{}, xerrors.Errorf("ListAllPuzzleHistory: %w", err)
+	}
+	return computeStats(puzzles, rounds, history, time.Now()), nil
+}
+
+func computeStats(puzzles []Puzzle, rounds []Round, history []PuzzleHistory, now time.Time) Stats {
+	var stats = Stats{
+		GeneratedAt:   now,
+		SolvesPerHour: []HourlySolves{},
+		Rounds:        []RoundStats{},
+		LongestOpen:   []OpenPuzzle{},
+		Burndown:      []BurndownStep{},
+		Puzzles:       []PuzzleTimes{},
+	}
+	var times = puzzleTimes(history)
+	var durations = make(map[int64][]time.Duration)
+	var unlocks, solves []time.Time
+	for _, puzzle := range puzzles {
+		var t = PuzzleTimes{Puzzle: puzzle.ID}
+		if found, ok := times[puzzle.ID]; ok {
+			t = *found
+		}
+		if !puzzle.Status.IsSolved() {
+			t.SolvedAt = nil // history is incomplete, don't trust it
+		}
+		stats.Puzzles = append(stats.Puzzles, t)
+
+		stats.Unlocked += 1
+		if puzzle.Meta {
+			stats.MetasTotal += 1
+		}
+		if t.UnlockedAt != nil {
+			unlocks = append(unlocks, *t.UnlockedAt)
+		}
+		if puzzle.Status.IsSolved() {
+			stats.Solved += 1
+			if puzzle.Meta {
+				stats.MetasSolved += 1
+			}
+			if t.SolvedAt != nil {
+				solves = append(solves, *t.SolvedAt)
+				if t.SolvedAt.After(now.Add(-time.Hour)) && !t.SolvedAt.After(now) {
+					stats.SolvesLastHour += 1
+				}
+				if t.UnlockedAt != nil {
+					durations[puzzle.Round.ID] = append(durations[puzzle.Round.ID],
+						t.SolvedAt.Sub(*t.UnlockedAt))
+				}
+			}
+		} else if t.UnlockedAt != nil && !puzzle.Round.Special {
+			stats.LongestOpen = append(stats.LongestOpen, OpenPuzzle{
+				puzzle.ID, puzzle.Name, puzzle.Round.ID, *t.UnlockedAt,
+			})
+		}
+	}
+
+	sort.Slice(stats.LongestOpen, func(i, j int) bool {
+		return stats.LongestOpen[i].UnlockedAt.Before(stats.LongestOpen[j].UnlockedAt)
+	})
+	if len(stats.LongestOpen) > longestOpenLimit {
+		stats.LongestOpen = stats.LongestOpen[:longestOpenLimit]
+	}
+
+	for _, round := range rounds {
+		var rs = RoundStats{Round: round.ID, Name: round.Name, Emoji: round.Emoji}
+		for _, puzzle := range puzzles {
+			if puzzle.Round.ID != round.ID {
+				continue
+			}
+			rs.Unlocked += 1
+			if puzzle.Status.IsSolved() {
+				rs.Solved += 1
+			}
+		}
+		rs.MedianSolveSeconds = int64(median(durations[round.ID]).Seconds())
+		stats.Rounds = append(stats.Rounds, rs)
+	}
+
+	// Solves per hour, from the first unlock through the current hour
+	sort.Slice(unlocks, func(i, j int) bool { return unlocks[i].Before(unlocks[j]) })
+	sort.Slice(solves, func(i, j int) bool { return solves[i].Before(solves[j]) })
+	if len(unlocks) > 0 {
+		var buckets = make(map[time.Time]int)
+		for _, t := range solves {
+			buckets[t.UTC().Truncate(time.Hour)] += 1
+		}
+		var end = now.UTC().Truncate(time.Hour)
+		for hour := unlocks[0].UTC().Truncate(time.Hour); !hour.After(end); hour = hour.Add(time.Hour) {
+			stats.SolvesPerHour = append(stats.SolvesPerHour, HourlySolves{hour, buckets[hour]})
+		}
+	}
+
+	// Burndown: merge the unlock and solve timelines
+	var step BurndownStep
+	for i, j := 0, 0; i < len(unlocks) || j < len(solves); {
+		if j >= len(solves) || (i < len(unlocks) && !unlocks[i].After(solves[j])) {
+			step.Time = unlocks[i]
+			step.Unlocked += 1
+			i += 1
+		} else {
+			step.Time = solves[j]
+			step.Solved += 1
+			j += 1
+		}
+		stats.Burndown = append(stats.Burndown, step)
+	}
+	return stats
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	var sorted = append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var mid = len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
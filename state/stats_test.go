@@ -0,0 +1,34 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/status"
+)
+
+func TestSolvesLastHour(t *testing.T) {
+	var now = clock(14, 20)
+	var round = Round{ID: 1, Name: "Fruit"}
+	var puzzles []Puzzle
+	var history []PuzzleHistory
+	// Solved at 12:50 and 13:30 (last clock hour) and 14:10 (this clock hour)
+	for i, solved := range []time.Time{clock(12, 50), clock(13, 30), clock(14, 10)} {
+		var id = int64(i + 1)
+		puzzles = append(puzzles, Puzzle{
+			ID: id, Round: round, Status: status.Solved, Answer: "X",
+		})
+		history = append(history,
+			PuzzleHistory{Puzzle: id, Field: "created", ChangedAt: clock(12, 0)},
+			PuzzleHistory{Puzzle: id, Field: "status", NewValue: string(status.Solved), ChangedAt: solved},
+		)
+	}
+
+	var stats = computeStats(puzzles, []Round{round}, history, now)
+	if stats.SolvesLastHour != 2 {
+		t.Errorf("SolvesLastHour: got %d, want 2", stats.SolvesLastHour)
+	}
+	if n := len(stats.SolvesPerHour); n != 3 || stats.SolvesPerHour[n-1].Solves != 1 {
+		t.Errorf("SolvesPerHour: got %+v, want 3 hours ending with 1 solve", stats.SolvesPerHour)
+	}
+}
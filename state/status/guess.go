@@ -0,0 +1,45 @@
+package status
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// GuessResult is the hunt site's response to an answer submission.
+type GuessResult string
+
+const (
+	GuessCorrect      GuessResult = "correct"
+	GuessIncorrect    GuessResult = "incorrect"
+	GuessPartial      GuessResult = "partial"      // "keep going"
+	GuessIntermediate GuessResult = "intermediate" // an instruction, not an answer
+)
+
+func (r GuessResult) IsValid() bool {
+	switch r {
+	case GuessCorrect, GuessIncorrect, GuessPartial, GuessIntermediate:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r GuessResult) Pretty() string {
+	return fmt.Sprintf("%s %s", r.Emoji(), r)
+}
+
+func (r GuessResult) Emoji() string {
+	switch r {
+	case GuessCorrect:
+		return "✅"
+	case GuessIncorrect:
+		return "❌"
+	case GuessPartial:
+		return "🟨"
+	case GuessIntermediate:
+		return "➡️"
+	default:
+		panic(xerrors.Errorf("called Emoji() on unknown guess result %q", r))
+	}
+}
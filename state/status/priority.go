@@ -0,0 +1,56 @@
+package status
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// Priority orders the puzzles within a round: higher-priority puzzles are
+// listed first.
+type Priority int64
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+	PriorityUrgent Priority = 2
+)
+
+func (p Priority) IsValid() bool {
+	return p >= PriorityLow && p <= PriorityUrgent
+}
+
+func (p Priority) Pretty() string {
+	return fmt.Sprintf("%s %s", p.Emoji(), p.Name())
+}
+
+func (p Priority) Name() string {
+	switch p {
+	case PriorityLow:
+		return "Low"
+	case PriorityNormal:
+		return "Normal"
+	case PriorityHigh:
+		return "High"
+	case PriorityUrgent:
+		return "Urgent"
+	default:
+		panic(xerrors.Errorf("called Name() on unknown priority %d", p))
+	}
+}
+
+func (p Priority) Emoji() string {
+	switch p {
+	case PriorityLow:
+		return "🐢"
+	case PriorityNormal:
+		return "➖"
+	case PriorityHigh:
+		return "🔥"
+	case PriorityUrgent:
+		return "🚨"
+	default:
+		panic(xerrors.Errorf("called Emoji() on unknown priority %d", p))
+	}
+}
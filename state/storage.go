@@ -0,0 +1,94 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"github.com/labstack/gommon/log"
+	"golang.org/x/xerrors"
+)
+
+// Storage is the database behind the Client. In production it's SQLite; tests
+// can use an in-memory implementation instead (see NewMemory).
+type Storage interface {
+	db.Querier
+
+	// Transaction runs fn in a transaction, which is committed if fn returns
+	// nil and rolled back otherwise. Queries made through the Storage itself,
+	// rather than through q, are not part of the transaction.
+	Transaction(ctx context.Context, fn func(q db.Querier) error) error
+
+	// Backup writes a consistent copy of the database to the given path.
+	Backup(ctx context.Context, path string) error
+}
+
+type sqliteStorage struct {
+	*db.Queries
+	dbx *sql.DB
+}
+
+// OpenSQLite opens (or creates) the SQLite database at the given path and
+// migrates it to the latest schema.
+func OpenSQLite(ctx context.Context, path string) (Storage, error) {
+	dbx, err := sql.Open("sqlite3", path+"?_fk=on")
+	if err != nil {
+		return nil, xerrors.Errorf("sql.Open: %w", err)
+	}
+	if path == ":memory:" {
+		// Each connection would otherwise get its own, empty database
+		dbx.SetMaxOpenConns(1)
+	}
+	if err := dbx.PingContext(ctx); err != nil {
+		return nil, xerrors.Errorf("PingContext: %w", err)
+	}
+	var fts5 bool
+	err = dbx.QueryRowContext(ctx, "SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5)
+	if err != nil {
+		return nil, xerrors.Errorf("sqlite_compileoption_used: %w", err)
+	} else if !fts5 {
+		return nil, xerrors.New("SQLite was built without FTS5, which is needed " +
+			"for search. Rebuild with `-tags sqlite_fts5`.")
+	}
+	from, to, err := db.Migrate(ctx, dbx)
+	if err != nil {
+		return nil, xerrors.Errorf("Migrate: %w", err)
+	} else if from != to {
+		log.Printf("state: migrated database schema from v%d to v%d", from, to)
+	}
+	return &sqliteStorage{db.New(dbx), dbx}, nil
+}
+
+func (s *sqliteStorage) Transaction(ctx context.Context, fn func(q db.Querier) error) error {
+	tx, err := s.dbx.BeginTx(ctx, nil)
+	if err != nil {
+		return xerrors.Errorf("BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+	if err := fn(s.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return xerrors.Errorf("Commit: %w", err)
+	}
+	return nil
+}
+
+// Backup uses SQLite's online backup API.
+func (s *sqliteStorage) Backup(ctx context.Context, path string) error {
+	return copyDatabase(ctx, s.dbx, path)
+}
+
+type memoryStorage struct {
+	*db.Memory
+}
+
+// NewMemory returns a Client backed by an empty in-memory database, for
+// tests. The database holds a single, active hunt.
+func NewMemory(ctx context.Context) *Client {
+	return NewWithStorage(ctx, memoryStorage{db.NewMemory()})
+}
+
+func (s memoryStorage) Backup(ctx context.Context, path string) error {
+	return xerrors.New("in-memory databases can't be backed up")
+}
@@ -0,0 +1,348 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"github.com/emojihunt/emojihunt/state/status"
+	"github.com/mattn/go-sqlite3"
+)
+
+// The storage tests run against both SQLite and the in-memory fake, to check
+// that the fake behaves the same way.
+func forEachStorage(t *testing.T, fn func(t *testing.T, s Storage)) {
+	var ctx = context.Background()
+	t.Run("sqlite", func(t *testing.T) {
+		s, err := OpenSQLite(ctx, ":memory:")
+		if err != nil && strings.Contains(err.Error(), "FTS5") && os.Getenv("CI") == "" {
+			// In CI, this is a failure: see .github/workflows/test.yaml
+			t.Skip("SQLite was built without FTS5 (run with -tags sqlite_fts5)")
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		fn(t, s)
+	})
+	t.Run("memory", func(t *testing.T) {
+		fn(t, memoryStorage{db.NewMemory()})
+	})
+}
+
+func isConstraintError(err error, code sqlite3.ErrNoExtended) bool {
+	var sqe sqlite3.Error
+	return errors.As(err, &sqe) && sqe.ExtendedCode == code
+}
+
+func createTestRound(t *testing.T, s Storage, name, emoji string) db.Round {
+	t.Helper()
+	round, err := s.CreateRound(context.Background(), db.CreateRoundParams{
+		Name: name, Emoji: emoji, DriveFolder: "folder", Hunt: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return round
+}
+
+func createTestPuzzle(t *testing.T, s Storage, name string, round int64) int64 {
+	t.Helper()
+	id, err := s.CreatePuzzle(context.Background(), db.CreatePuzzleParams{
+		Name: name, Round: round, PuzzleURL: fmt.Sprintf("https://example.com/%d/%s", round, name),
+		Status: status.NotStarted,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func trashed() sql.NullTime {
+	return sql.NullTime{Time: time.Now(), Valid: true}
+}
+
+func TestStorageRounds(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		var ctx = context.Background()
+		var fruit = createTestRound(t, s, "Fruit", "🍎")
+		_, err := s.CreateRound(ctx, db.CreateRoundParams{
+			Name: "Events", Emoji: "📅", Special: true, DriveFolder: "folder", Hunt: 1,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var veg = createTestRound(t, s, "Vegetables", "🥕")
+
+		rounds, err := s.ListRounds(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, round := range rounds {
+			names = append(names, round.Name)
+		}
+		if got, want := strings.Join(names, ","), "Events,Fruit,Vegetables"; got != want {
+			t.Errorf("ListRounds: got %s, want %s", got, want)
+		}
+		if count, err := s.CountRounds(ctx, 1); err != nil {
+			t.Fatal(err)
+		} else if count != 2 {
+			t.Errorf("CountRounds: got %d, want 2 (special rounds don't count)", count)
+		}
+
+		_, err = s.CreateRound(ctx, db.CreateRoundParams{
+			Name: "Fruit", Emoji: "🍐", DriveFolder: "folder", Hunt: 1,
+		})
+		if !isConstraintError(err, sqlite3.ErrConstraintUnique) {
+			t.Errorf("duplicate name: got %v, want unique constraint error", err)
+		}
+		_, err = s.CreateRound(ctx, db.CreateRoundParams{
+			Name: "Apples", Emoji: "🍎", DriveFolder: "folder", Hunt: 1,
+		})
+		if !isConstraintError(err, sqlite3.ErrConstraintUnique) {
+			t.Errorf("duplicate emoji: got %v, want unique constraint error", err)
+		}
+		_, err = s.CreateRound(ctx, db.CreateRoundParams{
+			Name: "Nowhere", Emoji: "🕳️", DriveFolder: "folder", Hunt: 999,
+		})
+		if !isConstraintError(err, sqlite3.ErrConstraintForeignKey) {
+			t.Errorf("missing hunt: got %v, want foreign key error", err)
+		}
+
+		if round, err := s.GetCreatedRound(ctx, db.GetCreatedRoundParams{
+			Hunt: 1, Name: "FRUIT",
+		}); err != nil || round.ID != fruit.ID {
+			t.Errorf("GetCreatedRound: got %v, %v, want round %d", round.ID, err, fruit.ID)
+		}
+
+		// Trashed rounds are hidden, and don't count towards uniqueness
+		if n, err := s.TrashRound(ctx, db.TrashRoundParams{ID: fruit.ID, DeletedAt: trashed()}); err != nil || n != 1 {
+			t.Fatalf("TrashRound: got %d, %v", n, err)
+		}
+		if _, err := s.GetRound(ctx, fruit.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetRound (trashed): got %v, want sql.ErrNoRows", err)
+		}
+		if _, err := s.GetCreatedRound(ctx, db.GetCreatedRoundParams{
+			Hunt: 1, Name: "Fruit",
+		}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetCreatedRound (trashed): got %v, want sql.ErrNoRows", err)
+		}
+		if list, err := s.ListTrashedRounds(ctx, 1); err != nil || len(list) != 1 {
+			t.Errorf("ListTrashedRounds: got %d rounds, %v", len(list), err)
+		}
+		var again = createTestRound(t, s, "Fruit", "🍎")
+		if _, err := s.RestoreRound(ctx, fruit.ID); !isConstraintError(err, sqlite3.ErrConstraintUnique) {
+			t.Errorf("RestoreRound (name taken): got %v, want unique constraint error", err)
+		}
+
+		// Updates bump the version and are checked for uniqueness, too
+		var update = db.UpdateRoundParams{
+			ID: veg.ID, Name: "Greens", Emoji: veg.Emoji, Hue: 120,
+			DriveFolder: veg.DriveFolder, Version: veg.Version + 1, Hunt: 1,
+		}
+		if err := s.UpdateRound(ctx, update); err != nil {
+			t.Fatal(err)
+		} else if round, err := s.GetRound(ctx, veg.ID); err != nil {
+			t.Fatal(err)
+		} else if round.Name != "Greens" || round.Hue != 120 || round.Version != 2 {
+			t.Errorf("UpdateRound: got %+v", round)
+		}
+		update.Emoji = again.Emoji
+		if err := s.UpdateRound(ctx, update); !isConstraintError(err, sqlite3.ErrConstraintUnique) {
+			t.Errorf("UpdateRound (emoji taken): got %v, want unique constraint error", err)
+		}
+	})
+}
+
+func TestStoragePuzzles(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		var ctx = context.Background()
+		var fruit = createTestRound(t, s, "Fruit", "🍎")
+		var veg = createTestRound(t, s, "Vegetables", "🥕")
+		var apples = createTestPuzzle(t, s, "Apples", fruit.ID)
+		createTestPuzzle(t, s, "Bananas", fruit.ID)
+		createTestPuzzle(t, s, "apples", veg.ID) // a different round is fine
+
+		_, err := s.CreatePuzzle(ctx, db.CreatePuzzleParams{
+			Name: "APPLES", Round: fruit.ID, PuzzleURL: "https://example.com/x",
+		})
+		if !isConstraintError(err, sqlite3.ErrConstraintUnique) {
+			t.Errorf("duplicate name: got %v, want unique constraint error", err)
+		}
+		_, err = s.CreatePuzzle(ctx, db.CreatePuzzleParams{
+			Name: "Orphan", Round: 999, PuzzleURL: "https://example.com/x",
+		})
+		if !isConstraintError(err, sqlite3.ErrConstraintForeignKey) {
+			t.Errorf("missing round: got %v, want foreign key error", err)
+		}
+
+		for _, tag := range []string{"wordplay", "audio"} {
+			if _, err := s.CreatePuzzleTag(ctx, db.CreatePuzzleTagParams{
+				Puzzle: apples, Tag: tag,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		puzzle, err := s.GetPuzzle(ctx, apples)
+		if err != nil {
+			t.Fatal(err)
+		} else if puzzle.Name != "Apples" || puzzle.Round.ID != fruit.ID ||
+			puzzle.Round.Name != "Fruit" || puzzle.Version != 1 {
+			t.Errorf("GetPuzzle: got %+v", puzzle)
+		} else if puzzle.Tags != "audio,wordplay" {
+			t.Errorf("GetPuzzle: got tags %q, want audio,wordplay", puzzle.Tags)
+		}
+
+		puzzles, err := s.ListPuzzles(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(puzzles) != 3 {
+			t.Errorf("ListPuzzles: got %d puzzles, want 3", len(puzzles))
+		}
+		if n, err := s.CountPuzzlesInRound(ctx, fruit.ID); err != nil || n != 2 {
+			t.Errorf("CountPuzzlesInRound: got %d, %v, want 2", n, err)
+		}
+
+		// Trashed puzzles are hidden, but still count as created (so discovery
+		// won't re-create them), and don't count towards uniqueness
+		if n, err := s.TrashPuzzle(ctx, db.TrashPuzzleParams{ID: apples, DeletedAt: trashed()}); err != nil || n != 1 {
+			t.Fatalf("TrashPuzzle: got %d, %v", n, err)
+		}
+		if _, err := s.GetPuzzle(ctx, apples); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetPuzzle (trashed): got %v, want sql.ErrNoRows", err)
+		}
+		if n, err := s.CheckPuzzleIsCreated(ctx, db.CheckPuzzleIsCreatedParams{
+			Hunt: 1, PuzzleURL: fmt.Sprintf("HTTPS://EXAMPLE.COM/%d/APPLES", fruit.ID),
+		}); err != nil || n != 1 {
+			t.Errorf("CheckPuzzleIsCreated (trashed): got %d, %v, want 1", n, err)
+		}
+		createTestPuzzle(t, s, "Apples", fruit.ID)
+		if _, err := s.RestorePuzzle(ctx, apples); !isConstraintError(err, sqlite3.ErrConstraintUnique) {
+			t.Errorf("RestorePuzzle (name taken): got %v, want unique constraint error", err)
+		}
+		if n, err := s.PurgeTrashedPuzzles(ctx, 1); err != nil || n != 1 {
+			t.Errorf("PurgeTrashedPuzzles: got %d, %v, want 1", n, err)
+		}
+	})
+}
+
+func TestStorageReminders(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		var ctx = context.Background()
+		var fruit = createTestRound(t, s, "Fruit", "🍎")
+		var apples = createTestPuzzle(t, s, "Apples", fruit.ID)
+		var at = time.Date(2026, 1, 16, 12, 0, 0, 0, time.UTC)
+		for i, puzzle := range []sql.NullInt64{{}, {Int64: apples, Valid: true}} {
+			if _, err := s.CreateReminder(ctx, db.CreateReminderParams{
+				Hunt: 1, Puzzle: puzzle, DiscordChannel: "channel", Message: "hello",
+				RemindAt: at.Add(time.Duration(i) * time.Hour), CreatedAt: at,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var check = func(label string, want int) {
+			t.Helper()
+			if reminders, err := s.ListReminders(ctx, 1); err != nil {
+				t.Fatal(err)
+			} else if len(reminders) != want {
+				t.Errorf("ListReminders (%s): got %d reminders, want %d", label, len(reminders), want)
+			}
+		}
+		check("live", 2)
+
+		// Reminders on trashed puzzles are hidden, and come back on restore
+		if n, err := s.TrashPuzzle(ctx, db.TrashPuzzleParams{ID: apples, DeletedAt: trashed()}); err != nil || n != 1 {
+			t.Fatalf("TrashPuzzle: got %d, %v", n, err)
+		}
+		check("trashed", 1)
+		if _, err := s.RestorePuzzle(ctx, apples); err != nil {
+			t.Fatal(err)
+		}
+		check("restored", 2)
+	})
+}
+
+func TestStorageSettings(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		var ctx = context.Background()
+		var key = db.GetSettingParams{Hunt: 1, Key: "example"}
+		if _, err := s.GetSetting(ctx, key); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetSetting (unset): got %v, want sql.ErrNoRows", err)
+		}
+		for _, value := range []string{`"one"`, `"two"`} {
+			if err := s.UpdateSetting(ctx, db.UpdateSettingParams{
+				Hunt: 1, Key: "example", Value: []byte(value),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if data, err := s.GetSetting(ctx, key); err != nil || string(data) != value {
+				t.Errorf("GetSetting: got %s, %v, want %s", data, err, value)
+			}
+		}
+	})
+}
+
+func TestStorageChangelog(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		var ctx = context.Background()
+		if id, err := s.GetLastChangeID(ctx); err != nil || id != nil {
+			t.Errorf("GetLastChangeID (empty): got %v, %v, want nil", id, err)
+		}
+		for _, id := range []int64{3001, 3002} {
+			if err := s.CreateChangelog(ctx, db.CreateChangelogParams{
+				ID: id, Kind: status.AblyKindUpsert, Puzzle: []byte(`{}`),
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if id, err := s.GetLastChangeID(ctx); err != nil || id != int64(3002) {
+			t.Errorf("GetLastChangeID: got %#v, %v, want 3002", id, err)
+		}
+		err := s.CreateChangelog(ctx, db.CreateChangelogParams{
+			ID: 3002, Kind: status.AblyKindUpsert, Puzzle: []byte(`{}`),
+		})
+		if !isConstraintError(err, sqlite3.ErrConstraintPrimaryKey) {
+			t.Errorf("duplicate ID: got %v, want primary key error", err)
+		}
+	})
+}
+
+func TestStorageTransaction(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, s Storage) {
+		var ctx = context.Background()
+		var rollback = errors.New("rollback")
+		err := s.Transaction(ctx, func(q db.Querier) error {
+			_, err := q.CreateRound(ctx, db.CreateRoundParams{
+				Name: "Fruit", Emoji: "🍎", DriveFolder: "folder", Hunt: 1,
+			})
+			if err != nil {
+				return err
+			}
+			return rollback
+		})
+		if !errors.Is(err, rollback) {
+			t.Fatalf("Transaction: got %v, want rollback", err)
+		}
+		if rounds, err := s.ListRounds(ctx, 1); err != nil || len(rounds) != 0 {
+			t.Errorf("ListRounds after rollback: got %d rounds, %v", len(rounds), err)
+		}
+
+		err = s.Transaction(ctx, func(q db.Querier) error {
+			_, err := q.CreateRound(ctx, db.CreateRoundParams{
+				Name: "Fruit", Emoji: "🍎", DriveFolder: "folder", Hunt: 1,
+			})
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rounds, err := s.ListRounds(ctx, 1); err != nil || len(rounds) != 1 {
+			t.Errorf("ListRounds after commit: got %d rounds, %v", len(rounds), err)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package state
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/emojihunt/emojihunt/state/db"
+	"golang.org/x/xerrors"
+)
+
+const maxTagLength = 32
+
+var tagPattern = regexp.MustCompile(`^[\p{Ll}\p{N}]+(-[\p{Ll}\p{N}]+)*$`)
+
+// NormalizeTag converts user input like "#Needs Fresh Eyes" to the canonical
+// form, "needs-fresh-eyes".
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return ValidationError{"tag", "is required"}
+	} else if len(tag) > maxTagLength {
+		return ValidationError{"tag", "is too long"}
+	} else if !tagPattern.MatchString(tag) {
+		return ValidationError{"tag", "may only contain letters, numbers and hyphens"}
+	}
+	return nil
+}
+
+func (p Puzzle) TagList() []string {
+	if p.Tags == "" {
+		return []string{}
+	}
+	return strings.Split(p.Tags, ",")
+}
+
+func (p Puzzle) HasTag(tag string) bool {
+	for _, t := range p.TagList() {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) ListPuzzlesByTag(ctx context.Context, tag string) ([]Puzzle, error) {
+	results, err := c.queries(ctx).ListPuzzlesByTag(ctx, db.ListPuzzlesByTagParams{
+		Tag: NormalizeTag(tag), Hunt: c.hunt(ctx),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("ListPuzzlesByTag: %w", err)
+	}
+	var puzzles = make([]Puzzle, len(results))
+	for i, result := range results {
+		puzzles[i] = Puzzle(result)
+	}
+	return puzzles, nil
+}
+
+// AddPuzzleTag tags the puzzle. Adding a tag that's already present is a
+// no-op, and doesn't generate a change.
+func (c *Client) AddPuzzleTag(ctx context.Context, id int64, tag string) (Puzzle, int64, error) {
+	tag = NormalizeTag(tag)
+	if err := ValidateTag(tag); err != nil {
+		return Puzzle{}, 0, err
+	}
+	return c.updatePuzzleAssociations(ctx, id, func(ctx context.Context) (int64, error) {
+		count, err := c.queries(ctx).CreatePuzzleTag(ctx, db.CreatePuzzleTagParams{
+			Puzzle: id, Tag: tag,
+		})
+		if err != nil {
+			return 0, xerrors.Errorf("CreatePuzzleTag: %w", err)
+		}
+		return count, nil
+	})
+}
+
+// RemovePuzzleTag untags the puzzle. Removing a tag that isn't present is a
+// no-op, and doesn't generate a change.
+func (c *Client) RemovePuzzleTag(ctx context.Context, id int64, tag string) (Puzzle, int64, error) {
+	tag = NormalizeTag(tag)
+	return c.updatePuzzleAssociations(ctx, id, func(ctx context.Context) (int64, error) {
+		count, err := c.queries(ctx).DeletePuzzleTag(ctx, db.DeletePuzzleTagParams{
+			Puzzle: id, Tag: tag,
+		})
+		if err != nil {
+			return 0, xerrors.Errorf("DeletePuzzleTag: %w", err)
+		}
+		return count, nil
+	})
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"context"
+
+	"github.com/emojihunt/emojihunt/state/db"
+)
+
+type txKey struct{}
+
+// A transaction tracks the side effects of a write that's in progress, so they
+// can be held back until the write commits.
+type transaction struct {
+	queries db.Querier
+	changes []PuzzleRoundChange
+}
+
+// Runs fn in a database transaction. Queries made with the context passed to fn
+// (see queries) are part of the transaction, as are the changelog entries for
+// any changes it logs. The changes are sent to PuzzleRoundChange only once the
+// transaction commits; if fn fails, they're discarded and the change IDs they
+// used are given back.
+//
+// Transactions don't nest: if ctx is already in a transaction, fn runs as part
+// of it. Must hold the global lock.
+func (c *Client) transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return fn(ctx)
+	}
+
+	var tx transaction
+	var changeID = c.changeID
+	err := c.storage.Transaction(ctx, func(q db.Querier) error {
+		tx.queries = q
+		return fn(context.WithValue(ctx, txKey{}, &tx))
+	})
+	if err != nil {
+		c.changeID = changeID
+		return err
+	}
+	for _, change := range tx.changes {
+		c.PuzzleRoundChange <- change
+	}
+	return nil
+}
+
+// Returns the queries to use with the given context: the transaction's, if
+// it's in a transaction, or the database's.
+func (c *Client) queries(ctx context.Context) db.Querier {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok {
+		return tx.queries
+	}
+	return c.storage
+}
+
+// Sends the change to PuzzleRoundChange once the transaction, if any, commits.
+// Must hold the global lock.
+func (c *Client) notify(ctx context.Context, change PuzzleRoundChange) {
+	if tx, ok := ctx.Value(txKey{}).(*transaction); ok {
+		tx.changes = append(tx.changes, change)
+	} else {
+		c.PuzzleRoundChange <- change
+	}
+}
@@ -0,0 +1,208 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Deleted puzzles and rounds are kept in the trash, hidden from everything
+// else, until they're restored or the trash is purged.
+
+type TrashedPuzzle struct {
+	Puzzle
+	DeletedAt time.Time
+}
+
+type TrashedRound struct {
+	Round
+	DeletedAt time.Time
+}
+
+// ListTrash returns the puzzles and rounds in the trash, most recently deleted
+// first.
+func (c *Client) ListTrash(ctx context.Context) ([]TrashedPuzzle, []TrashedRound, error) {
+	results, err := c.queries(ctx).ListTrashedPuzzles(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("ListTrashedPuzzles: %w", err)
+	}
+	var puzzles = make([]TrashedPuzzle, len(results))
+	for i, result := range results {
+		puzzles[i] = TrashedPuzzle{
+			Puzzle: Puzzle{
+				ID:             result.ID,
+				Name:           result.Name,
+				Answer:         result.Answer,
+				Round:          result.Round,
+				Status:         result.Status,
+				Note:           result.Note,
+				Location:       result.Location,
+				PuzzleURL:      result.PuzzleURL,
+				SpreadsheetID:  result.SpreadsheetID,
+				DiscordChannel: result.DiscordChannel,
+				Meta:           result.Meta,
+				VoiceRoom:      result.VoiceRoom,
+				Reminder:       result.Reminder,
+				Priority:       result.Priority,
+				NeedsHelp:      result.NeedsHelp,
+				Version:        result.Version,
+				Tags:           result.Tags,
+				Solvers:        result.Solvers,
+			},
+			DeletedAt: result.DeletedAt.Time,
+		}
+	}
+	rounds, err := c.queries(ctx).ListTrashedRounds(ctx, c.hunt(ctx))
+	if err != nil {
+		return nil, nil, xerrors.Errorf("ListTrashedRounds: %w", err)
+	}
+	var trashed = make([]TrashedRound, len(rounds))
+	for i, round := range rounds {
+		trashed[i] = TrashedRound{Round: round, DeletedAt: round.DeletedAt.Time}
+	}
+	return puzzles, trashed, nil
+}
+
+// RestorePuzzle takes the puzzle out of the trash. The puzzle's round must not
+// be in the trash.
+func (c *Client) RestorePuzzle(ctx context.Context, id int64) (Puzzle, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var restored Puzzle
+	var change PuzzleChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		trashed, _, err := c.ListTrash(ctx)
+		if err != nil {
+			return err
+		}
+		var found *Puzzle
+		for _, item := range trashed {
+			if item.ID == id {
+				found = &item.Puzzle
+				break
+			}
+		}
+		if found == nil {
+			return xerrors.Errorf("RestorePuzzle: %w", sql.ErrNoRows)
+		}
+		if _, err := c.GetRound(ctx, found.Round.ID); errors.Is(err, sql.ErrNoRows) {
+			return ValidationError{"round", "is in the trash; restore it first"}
+		} else if err != nil {
+			return err
+		}
+		// Someone may have reused the name or Discord channel in the meantime.
+		puzzles, err := c.ListPuzzles(ctx)
+		if err != nil {
+			return err
+		}
+		for _, other := range puzzles {
+			if other.Round.ID == found.Round.ID && strings.EqualFold(other.Name, found.Name) {
+				return ValidationError{"name", "is already used by another puzzle in the round"}
+			}
+		}
+		if err := c.ValidatePuzzle(ctx, found.RawPuzzle()); err != nil {
+			return err
+		} else if err := c.checkPuzzleHunt(ctx, nil, found.RawPuzzle()); err != nil {
+			return err
+		}
+
+		if _, err := c.queries(ctx).RestorePuzzle(ctx, id); err != nil {
+			return xerrors.Errorf("RestorePuzzle: %w", err)
+		}
+		restored, err = c.GetPuzzle(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := c.logPuzzleEvent(ctx, id, "deleted", false); err != nil {
+			return err
+		}
+		change, err = c.logPuzzleChange(ctx, PuzzleChange{After: &restored, Restored: true})
+		return err
+	})
+	if err != nil {
+		return Puzzle{}, 0, err
+	}
+	return restored, change.ChangeID, nil
+}
+
+// RestoreRound takes the round out of the trash.
+func (c *Client) RestoreRound(ctx context.Context, id int64) (Round, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var restored Round
+	var change RoundChange
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkWritable(ctx, c.hunt(ctx)); err != nil {
+			return err
+		}
+		trashed, err := c.queries(ctx).ListTrashedRounds(ctx, c.hunt(ctx))
+		if err != nil {
+			return xerrors.Errorf("ListTrashedRounds: %w", err)
+		}
+		var found *Round
+		for _, round := range trashed {
+			if round.ID == id {
+				found = &round
+				break
+			}
+		}
+		if found == nil {
+			return xerrors.Errorf("RestoreRound: %w", sql.ErrNoRows)
+		}
+		// Someone may have reused the name or emoji in the meantime.
+		if err := c.ValidateRoundUnique(ctx, *found); err != nil {
+			return err
+		}
+		count, err := c.queries(ctx).RestoreRound(ctx, id)
+		if err != nil {
+			return xerrors.Errorf("RestoreRound: %w", err)
+		} else if count == 0 {
+			return xerrors.Errorf("RestoreRound: %w", sql.ErrNoRows)
+		}
+		restored, err = c.GetRound(ctx, id)
+		if err != nil {
+			return err
+		}
+		change, err = c.LogRoundChange(ctx, nil, &restored)
+		return err
+	})
+	if err != nil {
+		return Round{}, 0, err
+	}
+	return restored, change.ChangeID, nil
+}
+
+// PurgeTrash permanently deletes everything in the trash. Discord channels and
+// spreadsheets are left as-is and should be cleaned up manually. Trashed
+// rounds that still contain (trashed) puzzles are purged along with them.
+func (c *Client) PurgeTrash(ctx context.Context) (int64, int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var puzzles, rounds int64
+	err := c.transaction(ctx, func(ctx context.Context) error {
+		if err := c.checkWritable(ctx, c.hunt(ctx)); err != nil {
+			return err
+		}
+		var err error
+		puzzles, err = c.queries(ctx).PurgeTrashedPuzzles(ctx, c.hunt(ctx))
+		if err != nil {
+			return xerrors.Errorf("PurgeTrashedPuzzles: %w", err)
+		}
+		rounds, err = c.queries(ctx).PurgeTrashedRounds(ctx, c.hunt(ctx))
+		if err != nil {
+			return xerrors.Errorf("PurgeTrashedRounds: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return puzzles, rounds, nil
+}
@@ -107,7 +107,7 @@ func (c *Client) CheckDiscordRound(ctx context.Context, round state.Round) {
 		if err != nil {
 			return
 		}
-		go c.state.UpdateRound(ctx, round.ID,
+		go c.state.UpdateRound(ctx, round.ID, state.AnyVersion,
 			func(round *state.Round) error {
 				if round.DiscordCategory == original {
 					log.Printf("sync: replacing deleted discord category for %q", round.Name)
@@ -35,7 +35,7 @@ func (c *Client) CreateDiscordChannel(ctx context.Context, puzzle state.RawPuzzl
 		if err != nil {
 			return "", err
 		}
-		round, _, err = c.state.UpdateRound(ctx, round.ID,
+		round, _, err = c.state.UpdateRound(ctx, round.ID, state.AnyVersion,
 			func(round *state.Round) error {
 				if round.DiscordCategory == original {
 					log.Printf("sync: replacing deleted discord category for %q", round.Name)
@@ -150,7 +150,7 @@ func (c *Client) CheckDiscordPuzzle(ctx context.Context, puzzle state.Puzzle) {
 	ch, ok := c.discord.GetChannel(channel)
 	if !ok || ch.Type != discordgo.ChannelTypeGuildText {
 		log.Printf("sync: found invalid puzzle channel %#v, %v", ch, ok)
-		go c.state.UpdatePuzzle(ctx, puzzle.ID,
+		go c.state.UpdatePuzzle(ctx, puzzle.ID, state.AnyVersion,
 			func(puzzle *state.RawPuzzle) error {
 				if puzzle.DiscordChannel == channel {
 					log.Printf("sync: clearing invalid discord channel %q on %q", channel, puzzle.Name)
@@ -173,7 +173,7 @@ func (c *Client) CheckDiscordVoiceRoom(ctx context.Context, puzzle state.VoiceIn
 	if channel != "" {
 		ch, ok := c.discord.GetChannel(channel)
 		if !ok || ch.Type != discordgo.ChannelTypeGuildVoice {
-			go c.state.UpdatePuzzle(ctx, puzzle.ID,
+			go c.state.UpdatePuzzle(ctx, puzzle.ID, state.AnyVersion,
 				func(puzzle *state.RawPuzzle) error {
 					if puzzle.VoiceRoom == channel {
 						log.Printf("sync: clearing invalid voice channel %q on %q", channel, puzzle.Name)
@@ -55,6 +55,7 @@ func (c *Client) Watch(ctx context.Context) {
 		scope.SetTag("task", "sync")
 	})
 	ctx = sentry.SetHubOnContext(ctx, hub)
+	ctx = state.WithActor(ctx, state.ActorSync)
 	// *do* allow panics to bubble up to main()
 
 	// The bot's status is reset when we connect to Discord
@@ -74,6 +75,8 @@ func (c *Client) Watch(ctx context.Context) {
 		case <-c.state.DiscoveryChange:
 			err = c.TriggerDiscovery(ctx)
 			c.RestartDiscovery <- true
+		case <-c.state.SettingsChange:
+			err = c.TriggerSettings(ctx)
 		case change := <-c.state.PuzzleRoundChange:
 			switch chg := change.(type) {
 			case state.PuzzleChange:
@@ -83,6 +86,10 @@ func (c *Client) Watch(ctx context.Context) {
 				}
 			case state.RoundChange:
 				err = c.TriggerRound(ctx, chg)
+			case state.FeedChange:
+				err = c.TriggerFeed(ctx, chg)
+			case state.GuessChange:
+				err = c.TriggerGuess(ctx, chg)
 			default:
 				log.Panicf("unhandled PuzzleRoundChange variant: %T", change)
 			}
@@ -103,11 +110,8 @@ func (c *Client) TriggerDiscovery(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-
-	var message = c.live.ComputeMeta(config)
-	c.state.LiveMessage <- message
-	if err := c.ably.Publish(ctx, state.EventTypeSettings, message); err != nil {
-		return xerrors.Errorf("ably.Publish: %w", err)
+	if err := c.TriggerSettings(ctx); err != nil {
+		return err
 	}
 
 	var data discordgo.UpdateStatusData
@@ -128,6 +132,19 @@ func (c *Client) TriggerDiscovery(ctx context.Context) error {
 	return c.discord.UpdateStatus(data)
 }
 
+// TriggerSettings pushes the hunt's settings out to clients.
+func (c *Client) TriggerSettings(ctx context.Context) error {
+	message, err := c.live.LoadMeta(ctx)
+	if err != nil {
+		return err
+	}
+	c.state.LiveMessage <- message
+	if err := c.ably.Publish(ctx, state.EventTypeSettings, message); err != nil {
+		return xerrors.Errorf("ably.Publish: %w", err)
+	}
+	return nil
+}
+
 func (c *Client) TriggerPuzzle(ctx context.Context, change state.PuzzleChange) error {
 	puzzlesProcessed.Inc()
 	if change.ChangeID > 0 {
@@ -141,9 +158,15 @@ func (c *Client) TriggerPuzzle(ctx context.Context, change state.PuzzleChange) e
 	}
 
 	if change.After == nil {
-		// Don't take any action when a puzzle is deleted. To avoid accidents, the
-		// channel and spreadsheet should be cleaned up manually.
-		return nil
+		// Deleted puzzles go to the trash. To avoid accidents, the channel and
+		// spreadsheet are left alone and should be cleaned up manually, but the
+		// voice room and the metas' pinned messages need updating.
+		if change.Before.VoiceRoom != "" {
+			if err := c.SyncVoiceRooms(ctx); err != nil {
+				return err
+			}
+		}
+		return c.refreshFedMetas(ctx, change.Before.ID)
 	}
 
 	var wg sync.WaitGroup
@@ -219,9 +242,27 @@ func (c *Client) TriggerPuzzle(ctx context.Context, change state.PuzzleChange) e
 		}
 	}
 
+	// Maybe sync updates to the pinned messages of the metas this puzzle feeds
+	// into, which list the feeders' answers
+	if change.Before == nil || change.Before.Name != puzzle.Name ||
+		change.Before.Answer != puzzle.Answer ||
+		change.Before.DiscordChannel != puzzle.DiscordChannel {
+		if err := c.refreshFedMetas(ctx, puzzle.ID); err != nil {
+			return err
+		}
+	}
+
+	// Ping #progress when someone asks for help with an unsolved puzzle
+	if change.Before != nil && !change.Before.NeedsHelp && puzzle.NeedsHelp &&
+		!puzzle.Status.IsSolved() {
+		if err := c.NotifyHelpWanted(puzzle, change.Actor); err != nil {
+			return err
+		}
+	}
+
 	// Notify the puzzle channel and #progress of significant status changes
 	if change.Before == nil {
-		if !puzzle.Round.Special { // skip Events round
+		if !puzzle.Round.Special && !change.Restored && !change.Imported { // skip Events round
 			return c.NotifyNewPuzzle(puzzle)
 		}
 	} else if !change.Before.Status.IsSolved() && puzzle.Status.IsSolved() {
@@ -236,9 +277,23 @@ func (c *Client) TriggerPuzzle(ctx context.Context, change state.PuzzleChange) e
 		}
 		// Always notify on solve, even if the puzzle doesn't have a Discord
 		// channel.
-		return c.NotifySolveInProgress(puzzle)
+		return c.NotifySolveInProgress(puzzle, change.Actor)
 	} else if change.Before.Status == status.NotStarted && puzzle.Status == status.Working {
-		return c.NotifyPuzzleWorking(puzzle)
+		return c.NotifyPuzzleWorking(puzzle, change.Actor)
+	}
+	return nil
+}
+
+// Refreshes the pinned messages of the metas the puzzle feeds into.
+func (c *Client) refreshFedMetas(ctx context.Context, id int64) error {
+	metas, err := c.state.ListFedMetas(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, meta := range metas {
+		if err := c.RefreshDiscordPin(ctx, meta.ID); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -300,3 +355,38 @@ func (c *Client) TriggerRound(ctx context.Context, change state.RoundChange) err
 	}
 	return nil
 }
+
+func (c *Client) TriggerFeed(ctx context.Context, change state.FeedChange) error {
+	feedsProcessed.Inc()
+	if change.ChangeID > 0 {
+		// Publish the update to Ably
+		var message = change.SyncMessage()
+		c.state.LiveMessage <- message
+		err := c.ably.Publish(ctx, state.EventTypeSync, message)
+		if err != nil {
+			return xerrors.Errorf("ably.Publish: %w", err)
+		}
+	}
+
+	// Both puzzles' pinned messages mention the relationship
+	var feed = change.After
+	if feed == nil {
+		feed = change.Before
+	}
+	if err := c.RefreshDiscordPin(ctx, feed.Puzzle); err != nil {
+		return err
+	}
+	return c.RefreshDiscordPin(ctx, feed.Meta)
+}
+
+func (c *Client) TriggerGuess(ctx context.Context, change state.GuessChange) error {
+	guessesProcessed.Inc()
+	// Publish the update to Ably
+	var message = change.SyncMessage()
+	c.state.LiveMessage <- message
+	err := c.ably.Publish(ctx, state.EventTypeSync, message)
+	if err != nil {
+		return xerrors.Errorf("ably.Publish: %w", err)
+	}
+	return nil
+}
@@ -29,6 +29,14 @@ var (
 		Name: "sync_round_count",
 		Help: "The total number of puzzles synced",
 	})
+	feedsProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sync_feed_count",
+		Help: "The total number of puzzle feeds synced",
+	})
+	guessesProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sync_guess_count",
+		Help: "The total number of guesses synced",
+	})
 )
 
 func (c *Client) HandleMetrics() {
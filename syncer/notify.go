@@ -3,6 +3,7 @@ package syncer
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/emojihunt/emojihunt/state"
 )
@@ -20,11 +21,30 @@ func (c *Client) NotifyNewPuzzle(puzzle state.Puzzle) error {
 }
 
 // NotifyPuzzleWorking sends the "Work started on puzzle" message to #progress.
-func (c *Client) NotifyPuzzleWorking(puzzle state.Puzzle) error {
+func (c *Client) NotifyPuzzleWorking(puzzle state.Puzzle, actor state.Actor) error {
 	log.Printf("sync: notifying for working puzzle %q", puzzle.Name)
+	var suffix string
+	if actor.IsUser() {
+		suffix = fmt.Sprintf(" by %s", c.discord.DisplayActor(actor))
+	}
+	_, err := c.discord.ChannelSend(
+		c.discord.ProgressChannel, fmt.Sprintf(
+			"%s Work started on puzzle %s%s", puzzle.Round.Emoji, puzzle.Mention(), suffix,
+		),
+	)
+	return err
+}
+
+// NotifyHelpWanted sends the "Help wanted" message to #progress.
+func (c *Client) NotifyHelpWanted(puzzle state.Puzzle, actor state.Actor) error {
+	log.Printf("sync: notifying for help wanted on puzzle %q", puzzle.Name)
+	var suffix string
+	if actor.IsUser() {
+		suffix = fmt.Sprintf(" (asked by %s)", c.discord.DisplayActor(actor))
+	}
 	_, err := c.discord.ChannelSend(
 		c.discord.ProgressChannel, fmt.Sprintf(
-			"%s Work started on puzzle %s", puzzle.Round.Emoji, puzzle.Mention(),
+			"%s :sos: **Help wanted** on puzzle %s%s", puzzle.Round.Emoji, puzzle.Mention(), suffix,
 		),
 	)
 	return err
@@ -43,18 +63,25 @@ func (c *Client) NotifySolveInPuzzleChannel(puzzle state.Puzzle) error {
 	)
 }
 
-// NotifySolveInProgress sends the same message as above to #progress.
-func (c *Client) NotifySolveInProgress(puzzle state.Puzzle) error {
+// NotifySolveInProgress sends the same message as above to #progress, noting
+// who marked the puzzle as solved.
+func (c *Client) NotifySolveInProgress(puzzle state.Puzzle, actor state.Actor) error {
 	log.Printf("sync: notifying for solved puzzle %q in #progress", puzzle.Name)
 	kind := "Puzzle"
 	if puzzle.Meta {
 		kind = "Meta"
 	}
+	var suffix string
+	if actor.IsUser() {
+		suffix = fmt.Sprintf(" Marked %s by %s.",
+			strings.ToLower(string(puzzle.Status)), c.discord.DisplayActor(actor))
+	}
 	_, err := c.discord.ChannelSend(
 		c.discord.ProgressChannel,
 		fmt.Sprintf(
-			"%s %s %s was **%s** Answer: `%s`.",
+			"%s %s %s was **%s** Answer: `%s`.%s",
 			puzzle.Round.Emoji, kind, puzzle.Mention(), puzzle.Status.SolvedVerb(), puzzle.Answer,
+			suffix,
 		),
 	)
 	return err
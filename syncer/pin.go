@@ -2,8 +2,11 @@ package syncer
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/emojihunt/emojihunt/state"
@@ -28,6 +31,9 @@ type DiscordPinFields struct {
 	SpreadsheetID  string
 	DiscordChannel string
 	VoiceRoom      string
+	Priority       status.Priority
+	NeedsHelp      bool
+	Solvers        string
 }
 
 func NewDiscordPinFields(puzzle state.Puzzle) DiscordPinFields {
@@ -44,6 +50,9 @@ func NewDiscordPinFields(puzzle state.Puzzle) DiscordPinFields {
 		SpreadsheetID:  puzzle.SpreadsheetID,
 		DiscordChannel: puzzle.DiscordChannel,
 		VoiceRoom:      puzzle.VoiceRoom,
+		Priority:       puzzle.Priority,
+		NeedsHelp:      puzzle.NeedsHelp,
+		Solvers:        puzzle.Solvers,
 	}
 }
 
@@ -97,6 +106,67 @@ func (c *Client) UpdateDiscordPin(ctx context.Context, fields DiscordPinFields)
 		})
 	}
 
+	if !fields.Status.IsSolved() &&
+		(fields.Priority != status.PriorityNormal || fields.NeedsHelp) {
+		var value = fields.Priority.Pretty()
+		if fields.NeedsHelp {
+			value += "  ·  🆘 Help wanted!"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Priority",
+			Value:  value,
+			Inline: false,
+		})
+	}
+
+	metas, err := c.state.ListFedMetas(ctx, fields.PuzzleID)
+	if err != nil {
+		return err
+	} else if len(metas) > 0 {
+		var mentions []string
+		for _, meta := range metas {
+			mentions = append(mentions, feedMention(meta))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Feeds into",
+			Value:  truncateEmbedValue(strings.Join(mentions, ", ")),
+			Inline: false,
+		})
+	}
+
+	feeders, err := c.state.ListFeeders(ctx, fields.PuzzleID)
+	if err != nil {
+		return err
+	} else if len(feeders) > 0 {
+		var solved int
+		var lines []string
+		for _, feeder := range feeders {
+			if feeder.Answer == "" {
+				lines = append(lines, fmt.Sprintf("%s: ?", feedMention(feeder)))
+			} else {
+				solved += 1
+				lines = append(lines, fmt.Sprintf("%s: `%s`", feedMention(feeder), feeder.Answer))
+			}
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Feeders (%d/%d solved)", solved, len(feeders)),
+			Value:  truncateEmbedValue(strings.Join(lines, "\n")),
+			Inline: false,
+		})
+	}
+
+	if fields.Solvers != "" {
+		var mentions []string
+		for _, solver := range strings.Split(fields.Solvers, ",") {
+			mentions = append(mentions, fmt.Sprintf("<@%s>", solver))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Solvers",
+			Value:  truncateEmbedValue(strings.Join(mentions, ", ")),
+			Inline: false,
+		})
+	}
+
 	if fields.Note != "" {
 		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
 			Name:   "Note",
@@ -126,3 +196,33 @@ func (c *Client) UpdateDiscordPin(ctx context.Context, fields DiscordPinFields)
 
 	return c.discord.CreateUpdatePin(fields.DiscordChannel, embed)
 }
+
+// RefreshDiscordPin re-renders the pinned message for the given puzzle. It's
+// used when something outside of the puzzle itself, like the list of feeders,
+// has changed.
+func (c *Client) RefreshDiscordPin(ctx context.Context, id int64) error {
+	puzzle, err := c.state.GetPuzzle(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // puzzle was deleted in the meantime
+	} else if err != nil {
+		return err
+	} else if puzzle.DiscordChannel == "" {
+		return nil
+	}
+	return c.UpdateDiscordPin(ctx, NewDiscordPinFields(puzzle))
+}
+
+func feedMention(info state.FeedInfo) string {
+	if info.DiscordChannel == "" {
+		return fmt.Sprintf("%q", info.Name)
+	}
+	return fmt.Sprintf("<#%s>", info.DiscordChannel)
+}
+
+// Discord rejects embed fields longer than 1024 characters
+func truncateEmbedValue(value string) string {
+	if runes := []rune(value); len(runes) > 1024 {
+		return string(runes[:1020]) + " ..."
+	}
+	return value
+}
@@ -0,0 +1,60 @@
+package syncer
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/emojihunt/emojihunt/state"
+	"github.com/getsentry/sentry-go"
+)
+
+const solverCheckInterval = 5 * time.Minute
+
+// SolverWorker periodically records recent spreadsheet edits as solver
+// activity, then expires the puzzle claims that have gone idle.
+func (c *Client) SolverWorker(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTag("task", "solvers")
+	})
+	ctx = sentry.SetHubOnContext(ctx, hub)
+	ctx = state.WithActor(ctx, state.ActorSync)
+	// *do* allow panics to bubble up to main()
+
+	for {
+		if err := c.ExpireSolvers(ctx); err != nil {
+			sentry.GetHubFromContext(ctx).CaptureException(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(solverCheckInterval):
+		}
+	}
+}
+
+func (c *Client) ExpireSolvers(ctx context.Context) error {
+	activity, err := c.drive.QueryActivity(ctx)
+	if err != nil {
+		return err
+	}
+	for spreadsheet, ts := range activity {
+		err := c.state.TouchPuzzleSolversBySpreadsheet(ctx, spreadsheet, ts)
+		if err != nil {
+			return err
+		}
+	}
+
+	expired, err := c.state.ExpirePuzzleSolvers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, solver := range expired {
+		log.Printf("sync: expired claim by %s on puzzle %d", solver.Solver, solver.Puzzle)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/emojihunt/emojihunt/discord"
 	"github.com/emojihunt/emojihunt/state"
+	"github.com/emojihunt/emojihunt/state/status"
 )
 
 // When setting the position of Discord channels and categories, start at a high
@@ -21,9 +22,11 @@ type PuzzleSortFields struct {
 	DiscordChannel string
 	IsSolved       bool
 
-	Name     string
-	Meta     bool
-	Reminder time.Time
+	Name      string
+	Priority  status.Priority
+	NeedsHelp bool
+	Meta      bool
+	Reminder  time.Time
 	RoundSortFields
 }
 
@@ -33,15 +36,28 @@ func NewPuzzleSortFields(puzzle state.RawPuzzle, round state.Round) PuzzleSortFi
 		DiscordChannel:  puzzle.DiscordChannel,
 		IsSolved:        puzzle.Status.IsSolved(),
 		Name:            puzzle.Name,
+		Priority:        puzzle.Priority,
+		NeedsHelp:       puzzle.NeedsHelp,
 		Meta:            puzzle.Meta,
 		Reminder:        puzzle.Reminder,
 		RoundSortFields: NewRoundSortFields(round),
 	}
 }
 
+// Within a round, puzzles are ordered by priority (highest first), then with
+// the ones that need help first, then with metas last, then by reminder and
+// name.
 func PuzzleSort(a, b PuzzleSortFields) int {
 	if round := RoundSort(a.RoundSortFields, b.RoundSortFields); round != 0 {
 		return round
+	} else if a.Priority != b.Priority {
+		return cmp.Compare(b.Priority, a.Priority)
+	} else if a.NeedsHelp != b.NeedsHelp {
+		if a.NeedsHelp {
+			return -1
+		} else {
+			return 1
+		}
 	} else if a.Meta != b.Meta {
 		if a.Meta {
 			return 1